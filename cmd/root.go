@@ -44,7 +44,7 @@ var rootCmd = &cobra.Command{
 			logr.SetLevel(logrus.DebugLevel)
 		}
 
-		coord := coordinator.NewCoordinator(config, logr, metricsPort)
+		coord := coordinator.NewCoordinator(config, cfgFile, logr, metricsPort)
 
 		if err := coord.Run(cmd.Context()); err != nil {
 			logr.Fatal(err)
@@ -0,0 +1,337 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	ChainSpecLayerConsensus = "consensus"
+	ChainSpecLayerExecution = "execution"
+)
+
+// ChainSpecReport is the result of comparing the chain configuration of multiple clients.
+// Every value is compared against the value reported by the majority of the clients.
+type ChainSpecReport struct {
+	Clients        []string          `json:"clients"`
+	Fields         []*ChainSpecField `json:"fields"`
+	Errors         []*ChainSpecError `json:"errors"`
+	FieldCount     int               `json:"fieldCount"`
+	MismatchCount  int               `json:"mismatchCount"`
+	DeviatingNames []string          `json:"deviatingClients"`
+}
+
+type ChainSpecField struct {
+	Layer         string            `json:"layer"`
+	Key           string            `json:"key"`
+	MajorityValue string            `json:"majorityValue"`
+	Values        map[string]string `json:"values"`
+	Deviating     []string          `json:"deviating"`
+}
+
+type ChainSpecError struct {
+	Client string `json:"client"`
+	Layer  string `json:"layer"`
+	Error  string `json:"error"`
+}
+
+type ChainSpecCompareOptions struct {
+	// Don't report keys that are missing on some clients
+	IgnoreMissing bool
+
+	// Keys matching any of these patterns are excluded from the comparison
+	IgnoreKeys []*regexp.Regexp
+}
+
+type chainSpecValues struct {
+	client string
+	layer  string
+	values map[string]string
+}
+
+// CompareChainSpecs fetches the chain configuration from all given clients and compares it.
+// Consensus clients are compared by `/eth/v1/config/spec`, deposit contract & fork schedule,
+// execution clients by chain id and `eth_config` (which includes the blob schedule).
+func CompareChainSpecs(ctx context.Context, poolClients []*PoolClient, options *ChainSpecCompareOptions) *ChainSpecReport {
+	if options == nil {
+		options = &ChainSpecCompareOptions{}
+	}
+
+	report := &ChainSpecReport{
+		Clients:        make([]string, len(poolClients)),
+		Fields:         []*ChainSpecField{},
+		Errors:         []*ChainSpecError{},
+		DeviatingNames: []string{},
+	}
+
+	valuesMutex := sync.Mutex{}
+	clientValues := []*chainSpecValues{}
+	wg := sync.WaitGroup{}
+
+	addResult := func(client, layer string, values map[string]string, err error) {
+		valuesMutex.Lock()
+		defer valuesMutex.Unlock()
+
+		if err != nil {
+			report.Errors = append(report.Errors, &ChainSpecError{
+				Client: client,
+				Layer:  layer,
+				Error:  err.Error(),
+			})
+
+			return
+		}
+
+		clientValues = append(clientValues, &chainSpecValues{
+			client: client,
+			layer:  layer,
+			values: values,
+		})
+	}
+
+	for idx, client := range poolClients {
+		report.Clients[idx] = client.Config.Name
+
+		if client.ConsensusClient != nil {
+			wg.Add(1)
+
+			go func(client *PoolClient) {
+				defer wg.Done()
+
+				values, err := getConsensusSpecValues(ctx, client)
+				addResult(client.Config.Name, ChainSpecLayerConsensus, values, err)
+			}(client)
+		}
+
+		if client.ExecutionClient != nil {
+			wg.Add(1)
+
+			go func(client *PoolClient) {
+				defer wg.Done()
+
+				values, err := getExecutionSpecValues(ctx, client)
+				addResult(client.Config.Name, ChainSpecLayerExecution, values, err)
+			}(client)
+		}
+	}
+
+	wg.Wait()
+
+	sort.Slice(report.Errors, func(a, b int) bool {
+		if report.Errors[a].Client != report.Errors[b].Client {
+			return report.Errors[a].Client < report.Errors[b].Client
+		}
+
+		return report.Errors[a].Layer < report.Errors[b].Layer
+	})
+
+	deviatingClients := map[string]bool{}
+
+	for _, layer := range []string{ChainSpecLayerConsensus, ChainSpecLayerExecution} {
+		layerValues := []*chainSpecValues{}
+
+		for _, values := range clientValues {
+			if values.layer == layer {
+				layerValues = append(layerValues, values)
+			}
+		}
+
+		// keep the client order stable, so ties are resolved the same way on every run
+		sort.Slice(layerValues, func(a, b int) bool {
+			return layerValues[a].client < layerValues[b].client
+		})
+
+		for _, field := range compareSpecValues(layer, layerValues, options) {
+			report.Fields = append(report.Fields, field)
+			report.FieldCount++
+
+			if len(field.Deviating) > 0 {
+				report.MismatchCount++
+
+				for _, client := range field.Deviating {
+					deviatingClients[client] = true
+				}
+			}
+		}
+	}
+
+	for _, client := range report.Clients {
+		if deviatingClients[client] {
+			report.DeviatingNames = append(report.DeviatingNames, client)
+		}
+	}
+
+	return report
+}
+
+func compareSpecValues(layer string, layerValues []*chainSpecValues, options *ChainSpecCompareOptions) []*ChainSpecField {
+	keyMap := map[string]bool{}
+
+	for _, values := range layerValues {
+		for key := range values.values {
+			keyMap[key] = !slices.ContainsFunc(options.IgnoreKeys, func(pattern *regexp.Regexp) bool {
+				return pattern.MatchString(key)
+			})
+		}
+	}
+
+	keys := make([]string, 0, len(keyMap))
+	for key, include := range keyMap {
+		if include {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	fields := make([]*ChainSpecField, 0, len(keys))
+
+	for _, key := range keys {
+		field := &ChainSpecField{
+			Layer:     layer,
+			Key:       key,
+			Values:    map[string]string{},
+			Deviating: []string{},
+		}
+
+		valueCounts := map[string]int{}
+		valueOrder := []string{}
+
+		for _, values := range layerValues {
+			value, found := values.values[key]
+			if !found {
+				if options.IgnoreMissing {
+					continue
+				}
+
+				value = "<missing>"
+			}
+
+			field.Values[values.client] = value
+
+			if valueCounts[value] == 0 {
+				valueOrder = append(valueOrder, value)
+			}
+
+			valueCounts[value]++
+		}
+
+		for _, value := range valueOrder {
+			if valueCounts[value] > valueCounts[field.MajorityValue] {
+				field.MajorityValue = value
+			}
+		}
+
+		for _, values := range layerValues {
+			if value, found := field.Values[values.client]; found && value != field.MajorityValue {
+				field.Deviating = append(field.Deviating, values.client)
+			}
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+func getConsensusSpecValues(ctx context.Context, client *PoolClient) (map[string]string, error) {
+	rpcClient := client.ConsensusClient.GetRPCClient()
+	values := map[string]string{}
+
+	specs, err := rpcClient.GetRawConfigSpecs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	flattenSpecValue("spec", specs, values)
+
+	depositContract, err := rpcClient.GetDepositContract(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving deposit contract: %v", err)
+	}
+
+	values["deposit_contract.chain_id"] = fmt.Sprintf("%v", depositContract.ChainID)
+	values["deposit_contract.address"] = fmt.Sprintf("0x%x", depositContract.Address)
+
+	forkSchedule, err := rpcClient.GetForkSchedule(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving fork schedule: %v", err)
+	}
+
+	for _, fork := range forkSchedule {
+		values[fmt.Sprintf("fork_schedule.%#x", fork.CurrentVersion)] = fmt.Sprintf("epoch: %v, previous: %#x", fork.Epoch, fork.PreviousVersion)
+	}
+
+	return values, nil
+}
+
+func getExecutionSpecValues(ctx context.Context, client *PoolClient) (map[string]string, error) {
+	rpcClient := client.ExecutionClient.GetRPCClient()
+	values := map[string]string{}
+
+	chainSpec, err := rpcClient.GetChainSpec(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving chain id: %v", err)
+	}
+
+	values["chain_id"] = chainSpec.ChainID
+
+	ethConfig, err := rpcClient.GetEthConfig(ctx)
+	if err != nil {
+		var rpcErr rpc.Error
+		if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32601 {
+			// eth_config is not supported by all clients yet
+			return values, nil
+		}
+
+		return nil, fmt.Errorf("error retrieving eth_config: %v", err)
+	}
+
+	// convert to generic json values, so every eth_config field is compared
+	configJSON, err := json.Marshal(ethConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var configValues interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(configJSON))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&configValues); err != nil {
+		return nil, err
+	}
+
+	flattenSpecValue("eth_config", configValues, values)
+
+	return values, nil
+}
+
+func flattenSpecValue(prefix string, value interface{}, values map[string]string) {
+	switch typedValue := value.(type) {
+	case map[string]interface{}:
+		for key, entry := range typedValue {
+			flattenSpecValue(prefix+"."+key, entry, values)
+		}
+	case []interface{}:
+		for idx, entry := range typedValue {
+			flattenSpecValue(fmt.Sprintf("%v[%v]", prefix, idx), entry, values)
+		}
+	case nil:
+		values[prefix] = "null"
+	case string:
+		values[prefix] = typedValue
+	default:
+		values[prefix] = strings.TrimSpace(fmt.Sprintf("%v", typedValue))
+	}
+}
@@ -2,24 +2,32 @@ package clients
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"regexp"
 	"runtime/debug"
+	"sync"
 	"time"
 
 	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus"
 	"github.com/erigontech/assertoor/pkg/coordinator/clients/execution"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/rpcstats"
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/sirupsen/logrus"
 )
 
+// ErrClientExists is returned by AddClient if a client with the same name is already in the pool.
+var ErrClientExists = errors.New("client with this name already exists")
+
 type ClientPool struct {
 	logger        logrus.FieldLogger
 	ctx           context.Context
 	ctxCancel     context.CancelFunc
 	consensusPool *consensus.Pool
 	executionPool *execution.Pool
+	clientsMutex  sync.RWMutex
 	clients       []*PoolClient
 }
 
@@ -27,26 +35,52 @@ type PoolClient struct {
 	Config          *ClientConfig
 	ConsensusClient *consensus.Client
 	ExecutionClient *execution.Client
+	closeChan       chan struct{}
 }
 
 type ClientConfig struct {
-	Name             string            `yaml:"name"`
-	ConsensusURL     string            `yaml:"consensusUrl"`
-	ConsensusHeaders map[string]string `yaml:"consensusHeaders"`
-	ExecutionURL     string            `yaml:"executionUrl"`
-	ExecutionHeaders map[string]string `yaml:"executionHeaders"`
+	Name             string                 `yaml:"name"`
+	ConsensusURL     string                 `yaml:"consensusUrl"`
+	ConsensusHeaders map[string]string      `yaml:"consensusHeaders"`
+	ExecutionURL     string                 `yaml:"executionUrl"`
+	ExecutionHeaders map[string]string      `yaml:"executionHeaders"`
+	Weight           uint64                 `yaml:"weight"`
+	RPCPolicy        *rpcstats.PolicyConfig `yaml:"rpcPolicy"`
+	DisableSSZ       bool                   `yaml:"disableSsz"`
 }
 
-func NewClientPool(logger logrus.FieldLogger) (*ClientPool, error) {
-	return NewClientPoolWithContext(context.Background(), logger)
+type PoolConfig struct {
+	// Client selection mode (roundrobin, latency, errors, sticky)
+	SchedulerMode string `yaml:"schedulerMode" json:"schedulerMode"`
+
+	// Number of consecutive timeouts after which a client is temporarily ejected from scheduling
+	EjectionThreshold uint64 `yaml:"ejectionThreshold" json:"ejectionThreshold"`
+
+	// Duration of the first ejection, repeated ejections double the duration
+	EjectionDuration helper.Duration `yaml:"ejectionDuration" json:"ejectionDuration"`
+
+	// Default retry, timeout & circuit breaker policy for all endpoints
+	RPCPolicy *rpcstats.PolicyConfig `yaml:"rpcPolicy" json:"rpcPolicy"`
 }
 
-func NewClientPoolWithContext(ctx context.Context, logger logrus.FieldLogger) (*ClientPool, error) {
+func NewClientPool(config *PoolConfig, logger logrus.FieldLogger) (*ClientPool, error) {
+	return NewClientPoolWithContext(context.Background(), config, logger)
+}
+
+func NewClientPoolWithContext(ctx context.Context, config *PoolConfig, logger logrus.FieldLogger) (*ClientPool, error) {
+	if config == nil {
+		config = &PoolConfig{}
+	}
+
 	poolCtx, ctxCancel := context.WithCancel(ctx)
 
 	consensusPool, err := consensus.NewPool(poolCtx, &consensus.PoolConfig{
-		FollowDistance: 10,
-		ForkDistance:   1,
+		FollowDistance:    10,
+		ForkDistance:      1,
+		SchedulerMode:     config.SchedulerMode,
+		EjectionThreshold: config.EjectionThreshold,
+		EjectionDuration:  config.EjectionDuration.Duration,
+		RPCPolicy:         config.RPCPolicy,
 	}, logger.WithField("module", "consensus"))
 	if err != nil {
 		ctxCancel()
@@ -54,8 +88,12 @@ func NewClientPoolWithContext(ctx context.Context, logger logrus.FieldLogger) (*
 	}
 
 	executionPool, err := execution.NewPool(poolCtx, &execution.PoolConfig{
-		FollowDistance: 10,
-		ForkDistance:   1,
+		FollowDistance:    10,
+		ForkDistance:      1,
+		SchedulerMode:     config.SchedulerMode,
+		EjectionThreshold: config.EjectionThreshold,
+		EjectionDuration:  config.EjectionDuration.Duration,
+		RPCPolicy:         config.RPCPolicy,
 	}, logger.WithField("module", "execution"))
 	if err != nil {
 		ctxCancel()
@@ -77,10 +115,25 @@ func (pool *ClientPool) Close() {
 }
 
 func (pool *ClientPool) AddClient(config *ClientConfig) error {
+	pool.clientsMutex.Lock()
+	defer pool.clientsMutex.Unlock()
+
+	// names identify clients for removal & reloads, so they must be unique (unnamed clients are not checked)
+	if config.Name != "" {
+		for _, client := range pool.clients {
+			if client.Config.Name == config.Name {
+				return fmt.Errorf("%w: %v", ErrClientExists, config.Name)
+			}
+		}
+	}
+
 	consensusClient, err := pool.consensusPool.AddEndpoint(&consensus.ClientConfig{
-		Name:    config.Name,
-		URL:     config.ConsensusURL,
-		Headers: config.ConsensusHeaders,
+		Name:       config.Name,
+		URL:        config.ConsensusURL,
+		Headers:    config.ConsensusHeaders,
+		Weight:     config.Weight,
+		Policy:     config.RPCPolicy,
+		DisableSSZ: config.DisableSSZ,
 	})
 	if err != nil {
 		return fmt.Errorf("could not init consensus client: %w", err)
@@ -90,15 +143,21 @@ func (pool *ClientPool) AddClient(config *ClientConfig) error {
 		Name:    config.Name,
 		URL:     config.ExecutionURL,
 		Headers: config.ExecutionHeaders,
+		Weight:  config.Weight,
+		Policy:  config.RPCPolicy,
 	})
 	if err != nil {
-		return fmt.Errorf("could not init consensus client: %w", err)
+		// the consensus client is not owned by any pool client yet, remove it to stop its connection loop
+		pool.consensusPool.RemoveEndpoint(consensusClient)
+
+		return fmt.Errorf("could not init execution client: %w", err)
 	}
 
 	poolClient := &PoolClient{
 		Config:          config,
 		ConsensusClient: consensusClient,
 		ExecutionClient: executionClient,
+		closeChan:       make(chan struct{}),
 	}
 
 	go pool.processConsensusBlockNotification(poolClient)
@@ -108,6 +167,69 @@ func (pool *ClientPool) AddClient(config *ClientConfig) error {
 	return nil
 }
 
+// RemoveClient stops and removes the client with the given name from the pool.
+// Tasks that still hold a reference to the client see it as offline.
+func (pool *ClientPool) RemoveClient(name string) bool {
+	pool.clientsMutex.Lock()
+	defer pool.clientsMutex.Unlock()
+
+	var poolClient *PoolClient
+
+	clients := make([]*PoolClient, 0, len(pool.clients))
+
+	for _, client := range pool.clients {
+		if poolClient == nil && client.Config.Name == name {
+			poolClient = client
+			continue
+		}
+
+		clients = append(clients, client)
+	}
+
+	if poolClient == nil {
+		return false
+	}
+
+	pool.clients = clients
+
+	close(poolClient.closeChan)
+	pool.consensusPool.RemoveEndpoint(poolClient.ConsensusClient)
+	pool.executionPool.RemoveEndpoint(poolClient.ExecutionClient)
+
+	return true
+}
+
+// SetClientDisabled drains or re-enables the client with the given name.
+// Drained clients stay connected, but are not used for new requests or selected by client patterns.
+func (pool *ClientPool) SetClientDisabled(name string, disabled bool) bool {
+	poolClient := pool.GetClient(name)
+	if poolClient == nil {
+		return false
+	}
+
+	poolClient.ConsensusClient.SetDisabled(disabled)
+	poolClient.ExecutionClient.SetDisabled(disabled)
+
+	return true
+}
+
+func (pool *ClientPool) GetClient(name string) *PoolClient {
+	pool.clientsMutex.RLock()
+	defer pool.clientsMutex.RUnlock()
+
+	for _, client := range pool.clients {
+		if client.Config.Name == name {
+			return client
+		}
+	}
+
+	return nil
+}
+
+func (poolClient *PoolClient) IsDisabled() bool {
+	return poolClient.ConsensusClient.IsDisabled()
+}
+
 func (pool *ClientPool) processConsensusBlockNotification(poolClient *PoolClient) {
 	defer func() {
 		if err := recover(); err != nil {
@@ -127,6 +249,8 @@ func (pool *ClientPool) processConsensusBlockNotification(poolClient *PoolClient
 		select {
 		case <-pool.ctx.Done():
 			return
+		case <-poolClient.closeChan:
+			return
 		case block := <-subscription.Channel():
 			versionedBlock := block.AwaitBlock(context.Background(), 2*time.Second)
 			if versionedBlock == nil {
@@ -151,6 +275,12 @@ func (pool *ClientPool) processConsensusBlockNotification(poolClient *PoolClient
 	}
 }
 
+// ReleaseTestClients removes the sticky client assignments of a finished test run.
+func (pool *ClientPool) ReleaseTestClients(testRunID uint64) {
+	pool.consensusPool.ReleaseTestClient(testRunID)
+	pool.executionPool.ReleaseTestClient(testRunID)
+}
+
 func (pool *ClientPool) GetConsensusPool() *consensus.Pool {
 	return pool.consensusPool
 }
@@ -160,6 +290,9 @@ func (pool *ClientPool) GetExecutionPool() *execution.Pool {
 }
 
 func (pool *ClientPool) GetAllClients() []*PoolClient {
+	pool.clientsMutex.RLock()
+	defer pool.clientsMutex.RUnlock()
+
 	clients := make([]*PoolClient, len(pool.clients))
 	copy(clients, pool.clients)
 
@@ -167,8 +300,16 @@ func (pool *ClientPool) GetAllClients() []*PoolClient {
 }
 
 func (pool *ClientPool) GetClientsByNamePatterns(includePattern, excludePattern string) []*PoolClient {
+	pool.clientsMutex.RLock()
+	defer pool.clientsMutex.RUnlock()
+
 	clients := []*PoolClient{}
+
 	for _, client := range pool.clients {
+		if client.IsDisabled() {
+			continue
+		}
+
 		if includePattern != "" {
 			matched, _ := regexp.MatchString(includePattern, client.Config.Name)
 			if !matched {
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"math"
 	"runtime/debug"
 	"sort"
 	"strings"
@@ -35,10 +34,6 @@ type BlockCache struct {
 	finalizedEpoch phase0.Epoch
 	finalizedRoot  phase0.Root
 
-	valsetMutex sync.Mutex
-	valsetEpoch phase0.Epoch
-	valsetMap   map[phase0.ValidatorIndex]*v1.Validator
-
 	blockMutex   sync.RWMutex
 	blockSlotMap map[phase0.Slot][]*Block
 	blockRootMap map[phase0.Root]*Block
@@ -171,6 +166,13 @@ func (cache *BlockCache) GetSpecValues() map[string]interface{} {
 	return cache.fullSpecs
 }
 
+func (cache *BlockCache) GetForkSchedule() []*ForkVersion {
+	cache.specMutex.RLock()
+	defer cache.specMutex.RUnlock()
+
+	return GetForkSchedule(cache.fullSpecs)
+}
+
 func (cache *BlockCache) InitWallclock() {
 	cache.wallclockMutex.Lock()
 	defer cache.wallclockMutex.Unlock()
@@ -224,33 +226,6 @@ func (cache *BlockCache) GetFinalizedCheckpoint() (phase0.Epoch, phase0.Root) {
 	return cache.finalizedEpoch, cache.finalizedRoot
 }
 
-func (cache *BlockCache) getCachedValidatorSet(loadFn func() map[phase0.ValidatorIndex]*v1.Validator) map[phase0.ValidatorIndex]*v1.Validator {
-	wallclock := cache.GetWallclock()
-
-	cache.valsetMutex.Lock()
-	defer cache.valsetMutex.Unlock()
-
-	epoch := phase0.Epoch(0)
-
-	if wallclock != nil {
-		_, e, _ := wallclock.Now()
-		if e.Number() < math.MaxInt64 {
-			epoch = phase0.Epoch(e.Number())
-		}
-	}
-
-	if cache.valsetMap == nil || cache.valsetEpoch < epoch {
-		// refresh cached validator set
-		valsetMap := loadFn()
-		if valsetMap != nil {
-			cache.valsetMap = valsetMap
-			cache.valsetEpoch = epoch
-		}
-	}
-
-	return cache.valsetMap
-}
-
 func (cache *BlockCache) AddBlock(root phase0.Root, slot phase0.Slot) (*Block, bool) {
 	cache.blockMutex.Lock()
 	defer cache.blockMutex.Unlock()
@@ -338,7 +313,6 @@ func (cache *BlockCache) runCacheCleanup(ctx context.Context) {
 		}
 
 		cache.cleanupBlockCache()
-		cache.cleanupValsetCache()
 	}
 }
 
@@ -364,29 +338,6 @@ func (cache *BlockCache) cleanupBlockCache() {
 	}
 }
 
-func (cache *BlockCache) cleanupValsetCache() {
-	if cache.valsetMap == nil {
-		return
-	}
-
-	wallclock := cache.GetWallclock()
-	epoch := phase0.Epoch(0)
-
-	if wallclock != nil {
-		_, e, _ := wallclock.Now()
-		if e.Number() < math.MaxInt64 {
-			epoch = phase0.Epoch(e.Number())
-		}
-	}
-
-	cache.valsetMutex.Lock()
-	defer cache.valsetMutex.Unlock()
-
-	if epoch-cache.valsetEpoch >= 2 {
-		cache.valsetMap = nil
-	}
-}
-
 func (cache *BlockCache) IsCanonicalBlock(blockRoot, headRoot phase0.Root) bool {
 	res, _ := cache.GetBlockDistance(blockRoot, headRoot)
 	return res
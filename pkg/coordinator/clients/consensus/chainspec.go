@@ -1,18 +1,26 @@
 package consensus
 
 import (
+	"bytes"
+	"math"
 	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 )
 
 type ForkVersion struct {
+	Name            string
 	Epoch           uint64
-	CurrentVersion  []byte
-	PreviousVersion []byte
+	CurrentVersion  phase0.Version
+	PreviousVersion phase0.Version
 }
 
+// FarFutureEpoch is used as fork epoch for forks that are not scheduled yet.
+const FarFutureEpoch = math.MaxUint64
+
 // https://github.com/ethereum/consensus-specs/blob/dev/configs/mainnet.yaml
 type ChainSpec struct {
 	PresetBase           string         `yaml:"PRESET_BASE"`
@@ -44,3 +52,54 @@ func (chain *ChainSpec) CheckMismatch(chain2 *ChainSpec) []string {
 
 	return mismatches
 }
+
+// GetForkSchedule builds the fork schedule from the `<FORK>_FORK_VERSION` & `<FORK>_FORK_EPOCH` spec values.
+// The returned forks are ordered by activation epoch, unscheduled forks have FarFutureEpoch as epoch.
+func GetForkSchedule(specValues map[string]interface{}) []*ForkVersion {
+	forks := []*ForkVersion{}
+
+	for key, value := range specValues {
+		if !strings.HasSuffix(key, "_FORK_VERSION") {
+			continue
+		}
+
+		version, isVersion := value.(phase0.Version)
+		if !isVersion {
+			continue
+		}
+
+		fork := &ForkVersion{
+			Name:           strings.ToLower(strings.TrimSuffix(key, "_FORK_VERSION")),
+			CurrentVersion: version,
+		}
+
+		if fork.Name != "genesis" {
+			epoch, isEpoch := specValues[strings.TrimSuffix(key, "_VERSION")+"_EPOCH"].(uint64)
+			if !isEpoch {
+				continue
+			}
+
+			fork.Epoch = epoch
+		}
+
+		forks = append(forks, fork)
+	}
+
+	sort.Slice(forks, func(a, b int) bool {
+		if forks[a].Epoch != forks[b].Epoch {
+			return forks[a].Epoch < forks[b].Epoch
+		}
+
+		return bytes.Compare(forks[a].CurrentVersion[:], forks[b].CurrentVersion[:]) < 0
+	})
+
+	for idx, fork := range forks {
+		if idx == 0 {
+			fork.PreviousVersion = fork.CurrentVersion
+		} else {
+			fork.PreviousVersion = forks[idx-1].CurrentVersion
+		}
+	}
+
+	return forks
+}
@@ -3,10 +3,12 @@ package consensus
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus/rpc"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/rpcstats"
 	"github.com/sirupsen/logrus"
 )
 
@@ -19,10 +21,28 @@ var (
 	ClientStatusOptimistic    ClientStatus = 4
 )
 
+func (status ClientStatus) String() string {
+	switch status {
+	case ClientStatusOnline:
+		return "online"
+	case ClientStatusOffline:
+		return "offline"
+	case ClientStatusSynchronizing:
+		return "synchronizing"
+	case ClientStatusOptimistic:
+		return "optimistic"
+	default:
+		return "unknown"
+	}
+}
+
 type ClientConfig struct {
-	URL     string
-	Name    string
-	Headers map[string]string
+	URL        string
+	Name       string
+	Headers    map[string]string
+	Weight     uint64
+	Policy     *rpcstats.PolicyConfig
+	DisableSSZ bool
 }
 
 type Client struct {
@@ -31,11 +51,13 @@ type Client struct {
 	endpointConfig       *ClientConfig
 	clientCtx            context.Context
 	clientCtxCancel      context.CancelFunc
+	isClosed             atomic.Bool
+	isDisabled           atomic.Bool
 	rpcClient            *rpc.BeaconClient
 	logger               *logrus.Entry
-	isOnline             bool
-	isSyncing            bool
-	isOptimistic         bool
+	isOnline             atomic.Bool
+	isSyncing            atomic.Bool
+	isOptimistic         atomic.Bool
 	versionStr           string
 	clientType           ClientType
 	lastEvent            time.Time
@@ -51,7 +73,14 @@ type Client struct {
 }
 
 func (pool *Pool) newPoolClient(clientIdx uint16, endpoint *ClientConfig) (*Client, error) {
-	rpcClient, err := rpc.NewBeaconClient(endpoint.Name, endpoint.URL, endpoint.Headers)
+	trackerConfig := pool.trackerConfig
+	if endpoint.Policy != nil {
+		endpointTrackerConfig := *pool.trackerConfig
+		endpointTrackerConfig.Policy = endpoint.Policy
+		trackerConfig = &endpointTrackerConfig
+	}
+
+	rpcClient, err := rpc.NewBeaconClient(endpoint.Name, endpoint.URL, endpoint.Headers, rpcstats.NewTracker(endpoint.Name, "consensus", trackerConfig), endpoint.DisableSSZ)
 	if err != nil {
 		return nil, err
 	}
@@ -94,6 +123,51 @@ func (client *Client) UnsubscribeFinalizedEvent(subscription *Subscription[*Fina
 	client.checkpointDispatcher.Unsubscribe(subscription)
 }
 
+// Close stops the client loop, the client can not be used anymore afterwards.
+func (client *Client) Close() {
+	client.isClosed.Store(true)
+	client.clientCtxCancel()
+	client.rpcClient.GetTracker().Close()
+}
+
+func (client *Client) IsClosed() bool {
+	return client.isClosed.Load()
+}
+
+// SetDisabled drains or re-enables the client. Disabled clients stay connected, but are not selected as ready client.
+func (client *Client) SetDisabled(disabled bool) {
+	client.isDisabled.Store(disabled)
+	client.pool.resetHeadForkCache()
+}
+
+func (client *Client) IsDisabled() bool {
+	return client.isDisabled.Load()
+}
+
+// GetWeight returns the scheduling weight of the client, clients with higher weight get selected more often.
+func (client *Client) GetWeight() uint64 {
+	if client.endpointConfig.Weight == 0 {
+		return 1
+	}
+
+	return client.endpointConfig.Weight
+}
+
+// GetRequestStats returns the response time and failure stats of the rpc requests to this client.
+func (client *Client) GetRequestStats() rpcstats.Stats {
+	return client.rpcClient.GetTracker().GetStats()
+}
+
+// IsEjected returns true if the client has been ejected from scheduling because of repeated timeouts.
+func (client *Client) IsEjected() bool {
+	return client.rpcClient.GetTracker().IsEjected()
+}
+
+// IsFailing returns true if the client is ejected or its circuit breaker is open.
+func (client *Client) IsFailing() bool {
+	return client.rpcClient.GetTracker().IsFailing()
+}
+
 func (client *Client) GetIndex() uint16 {
 	return client.clientIdx
 }
@@ -131,11 +205,11 @@ func (client *Client) GetLastEventTime() time.Time {
 
 func (client *Client) GetStatus() ClientStatus {
 	switch {
-	case client.isSyncing:
+	case client.isSyncing.Load():
 		return ClientStatusSynchronizing
-	case client.isOptimistic:
+	case client.isOptimistic.Load():
 		return ClientStatusOptimistic
-	case client.isOnline:
+	case client.isOnline.Load():
 		return ClientStatusOnline
 	default:
 		return ClientStatusOffline
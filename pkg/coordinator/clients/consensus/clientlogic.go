@@ -33,12 +33,12 @@ func (client *Client) runClientLoop() {
 			err = client.runClientLogic()
 		}
 
-		if err == nil {
+		if err == nil || client.isClosed.Load() {
 			client.retryCounter = 0
 			return
 		}
 
-		client.isOnline = false
+		client.isOnline.Store(false)
 		client.lastError = err
 		client.lastEvent = time.Now()
 		client.retryCounter++
@@ -50,7 +50,11 @@ func (client *Client) runClientLoop() {
 		}
 
 		client.logger.Warnf("upstream client error: %v, retrying in %v sec...", err, waitTime)
-		time.Sleep(time.Duration(waitTime) * time.Second)
+		select {
+		case <-client.clientCtx.Done():
+			return
+		case <-time.After(time.Duration(waitTime) * time.Second):
+		}
 	}
 }
 
@@ -107,8 +111,8 @@ func (client *Client) checkClient() error {
 		return fmt.Errorf("could not get synchronization status")
 	}
 
-	client.isSyncing = syncStatus.IsSyncing
-	client.isOptimistic = syncStatus.IsOptimistic
+	client.isSyncing.Store(syncStatus.IsSyncing)
+	client.isOptimistic.Store(syncStatus.IsOptimistic)
 
 	return nil
 }
@@ -121,11 +125,11 @@ func (client *Client) runClientLogic() error {
 	}
 
 	// check latest header / sync status
-	if client.isSyncing {
+	if client.isSyncing.Load() {
 		return fmt.Errorf("beacon node is synchronizing")
 	}
 
-	if client.isOptimistic {
+	if client.isOptimistic.Load() {
 		return fmt.Errorf("beacon node is optimistic")
 	}
 
@@ -178,8 +182,8 @@ func (client *Client) runClientLogic() error {
 			client.logger.Tracef("event (%v) processing time: %v ms", evt.Event, time.Since(now).Milliseconds())
 			client.lastEvent = time.Now()
 		case ready := <-blockStream.ReadyChan:
-			if client.isOnline != ready {
-				client.isOnline = ready
+			if client.isOnline.Load() != ready {
+				client.isOnline.Store(ready)
 				if ready {
 					client.logger.Debug("RPC event stream connected")
 				} else {
@@ -191,7 +195,7 @@ func (client *Client) runClientLogic() error {
 
 			err := client.pollClientHead()
 			if err != nil {
-				client.isOnline = false
+				client.isOnline.Store(false)
 				return err
 			}
 
@@ -8,34 +8,47 @@ import (
 
 	v1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/rpcstats"
 	"github.com/sirupsen/logrus"
 )
 
 type SchedulerMode uint8
 
 var (
-	RoundRobinScheduler SchedulerMode = 1
+	RoundRobinScheduler    SchedulerMode = 1
+	LowestLatencyScheduler SchedulerMode = 2
+	LeastErrorsScheduler   SchedulerMode = 3
+	StickyScheduler        SchedulerMode = 4
 )
 
 type PoolConfig struct {
 	FollowDistance uint32 `yaml:"followDistance" envconfig:"CONSENSUS_POOL_FOLLOW_DISTANCE"`
 	ForkDistance   uint32 `yaml:"forkDistance" envconfig:"CONSENSUS_POOL_FORK_DISTANCE"`
 	SchedulerMode  string `yaml:"schedulerMode" envconfig:"CONSENSUS_POOL_SCHEDULER_MODE"`
+
+	EjectionThreshold uint64        `yaml:"ejectionThreshold" envconfig:"CONSENSUS_POOL_EJECTION_THRESHOLD"`
+	EjectionDuration  time.Duration `yaml:"ejectionDuration" envconfig:"CONSENSUS_POOL_EJECTION_DURATION"`
+
+	RPCPolicy *rpcstats.PolicyConfig `yaml:"rpcPolicy"`
 }
 
 type Pool struct {
-	config         *PoolConfig
-	ctx            context.Context
-	logger         logrus.FieldLogger
-	clientCounter  uint16
-	clients        []*Client
-	blockCache     *BlockCache
-	forkCacheMutex sync.Mutex
-	forkCache      map[int64][]*HeadFork
+	config            *PoolConfig
+	ctx               context.Context
+	logger            logrus.FieldLogger
+	clientsMutex      sync.RWMutex
+	clientCounter     uint16
+	clients           []*Client
+	blockCache        *BlockCache
+	validatorRegistry *ValidatorRegistry
+	forkCacheMutex    sync.Mutex
+	forkCache         map[int64][]*HeadFork
 
 	schedulerMode  SchedulerMode
 	schedulerMutex sync.Mutex
-	rrLastIndexes  map[ClientType]uint16
+	rrWeights      map[ClientType]map[uint16]int64
+	stickyClients  map[uint64]uint16
+	trackerConfig  *rpcstats.Config
 }
 
 func NewPool(ctx context.Context, config *PoolConfig, logger logrus.FieldLogger) (*Pool, error) {
@@ -47,12 +60,24 @@ func NewPool(ctx context.Context, config *PoolConfig, logger logrus.FieldLogger)
 		logger:        logger,
 		clients:       make([]*Client, 0),
 		forkCache:     map[int64][]*HeadFork{},
-		rrLastIndexes: map[ClientType]uint16{},
+		rrWeights:     map[ClientType]map[uint16]int64{},
+		stickyClients: map[uint64]uint16{},
+		trackerConfig: &rpcstats.Config{
+			EjectionThreshold: config.EjectionThreshold,
+			EjectionDuration:  config.EjectionDuration,
+			Policy:            config.RPCPolicy,
+		},
 	}
 
 	switch config.SchedulerMode {
 	case "", "rr", "roundrobin":
 		pool.schedulerMode = RoundRobinScheduler
+	case "latency", "lowest-latency":
+		pool.schedulerMode = LowestLatencyScheduler
+	case "errors", "least-errors":
+		pool.schedulerMode = LeastErrorsScheduler
+	case "sticky", "sticky-per-test":
+		pool.schedulerMode = StickyScheduler
 	default:
 		return nil, fmt.Errorf("unknown pool schedulerMode: %v", config.SchedulerMode)
 	}
@@ -62,6 +87,9 @@ func NewPool(ctx context.Context, config *PoolConfig, logger logrus.FieldLogger)
 		return nil, err
 	}
 
+	pool.validatorRegistry = newValidatorRegistry(&pool)
+	go pool.validatorRegistry.runCleanup(ctx)
+
 	return &pool, nil
 }
 
@@ -69,40 +97,63 @@ func (pool *Pool) GetBlockCache() *BlockCache {
 	return pool.blockCache
 }
 
-func (pool *Pool) GetValidatorSet() map[phase0.ValidatorIndex]*v1.Validator {
-	return pool.blockCache.getCachedValidatorSet(func() map[phase0.ValidatorIndex]*v1.Validator {
-		client := pool.GetReadyEndpoint(AnyClient)
-		if client == nil {
-			pool.logger.Errorf("could not load validator set: no ready client")
-			return nil
-		}
-
-		valset, err := client.GetRPCClient().GetStateValidators(client.clientCtx, "head")
-		if err != nil {
-			pool.logger.Errorf("could not load validator set: %v", err)
-			return nil
-		}
+// GetValidatorRegistry returns the indexed validator set.
+// Balances and validators that can change are refreshed once per epoch, the full set is reloaded every 16 epochs.
+func (pool *Pool) GetValidatorRegistry() *ValidatorRegistry {
+	return pool.validatorRegistry
+}
 
-		return valset
-	})
+func (pool *Pool) GetValidatorSet() map[phase0.ValidatorIndex]*v1.Validator {
+	return pool.validatorRegistry.GetValidatorSet()
 }
 
 func (pool *Pool) AddEndpoint(endpoint *ClientConfig) (*Client, error) {
+	pool.clientsMutex.Lock()
 	clientIdx := pool.clientCounter
 	pool.clientCounter++
+	pool.clientsMutex.Unlock()
 
 	client, err := pool.newPoolClient(clientIdx, endpoint)
 	if err != nil {
 		return nil, err
 	}
 
+	pool.clientsMutex.Lock()
 	pool.clients = append(pool.clients, client)
+	pool.clientsMutex.Unlock()
 
 	return client, nil
 }
 
+// RemoveEndpoint removes the client from the pool and stops its connection loop.
+func (pool *Pool) RemoveEndpoint(client *Client) {
+	pool.clientsMutex.Lock()
+
+	clients := make([]*Client, 0, len(pool.clients))
+
+	for _, poolClient := range pool.clients {
+		if poolClient != client {
+			clients = append(clients, poolClient)
+		}
+	}
+
+	pool.clients = clients
+	pool.clientsMutex.Unlock()
+
+	pool.resetHeadForkCache()
+
+	client.Close()
+}
+
+// GetAllEndpoints returns a snapshot of all clients in the pool.
 func (pool *Pool) GetAllEndpoints() []*Client {
-	return pool.clients
+	pool.clientsMutex.RLock()
+	defer pool.clientsMutex.RUnlock()
+
+	clients := make([]*Client, len(pool.clients))
+	copy(clients, pool.clients)
+
+	return clients
 }
 
 func (pool *Pool) GetReadyEndpoint(clientType ClientType) *Client {
@@ -155,37 +206,3 @@ func (pool *Pool) IsClientReady(client *Client) bool {
 
 	return false
 }
-
-func (pool *Pool) runClientScheduler(readyClients []*Client, clientType ClientType) *Client {
-	pool.schedulerMutex.Lock()
-	defer pool.schedulerMutex.Unlock()
-
-	if pool.schedulerMode == RoundRobinScheduler {
-		var firstReadyClient *Client
-
-		for _, client := range readyClients {
-			if clientType != AnyClient && clientType != client.clientType {
-				continue
-			}
-
-			if firstReadyClient == nil {
-				firstReadyClient = client
-			}
-
-			if client.clientIdx > pool.rrLastIndexes[clientType] {
-				pool.rrLastIndexes[clientType] = client.clientIdx
-				return client
-			}
-		}
-
-		if firstReadyClient == nil {
-			return nil
-		}
-
-		pool.rrLastIndexes[clientType] = firstReadyClient.clientIdx
-
-		return firstReadyClient
-	}
-
-	return readyClients[0]
-}
@@ -4,10 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"net"
 	nethttp "net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	eth2client "github.com/attestantio/go-eth2-client"
@@ -16,36 +21,107 @@ import (
 	"github.com/attestantio/go-eth2-client/http"
 	"github.com/attestantio/go-eth2-client/spec"
 	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/deneb"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/rpcstats"
 	"github.com/rs/zerolog"
 	"github.com/sirupsen/logrus"
 )
 
 var logger = logrus.StandardLogger().WithField("module", "rpc")
 
+// sszFallbackDuration is the time an endpoint uses json after a failed ssz request, before ssz is probed again.
+const sszFallbackDuration = 30 * time.Minute
+
 type BeaconClient struct {
-	name      string
-	endpoint  string
-	headers   map[string]string
-	clientSvc eth2client.Service
+	name          string
+	endpoint      string
+	headers       map[string]string
+	tracker       *rpcstats.Tracker
+	transport     nethttp.RoundTripper
+	httpClient    *nethttp.Client
+	clientSvc     eth2client.Service
+	disableSSZ    bool
+	sszFallback   atomic.Int64 // time of the last switch to json (unix nanoseconds)
+	jsonSvcMutex  sync.Mutex
+	jsonClientSvc eth2client.Service
 }
 
 // NewBeaconClient is used to create a new beacon client
-func NewBeaconClient(name, url string, headers map[string]string) (*BeaconClient, error) {
+// States, blocks & blob sidecars are requested as ssz with a fallback to json, unless disableSSZ is set.
+func NewBeaconClient(name, url string, headers map[string]string, tracker *rpcstats.Tracker, disableSSZ bool) (*BeaconClient, error) {
+	if tracker == nil {
+		tracker = rpcstats.NewTracker(name, "consensus", nil)
+	}
+
 	client := &BeaconClient{
-		name:     name,
-		endpoint: url,
-		headers:  headers,
+		name:       name,
+		endpoint:   url,
+		headers:    headers,
+		tracker:    tracker,
+		disableSSZ: disableSSZ,
+	}
+
+	client.transport = &rpcstats.Transport{
+		Base: &nethttp.Transport{
+			Proxy: nethttp.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			MaxIdleConns:        64,
+			MaxConnsPerHost:     64,
+			MaxIdleConnsPerHost: 64,
+			IdleConnTimeout:     600 * time.Second,
+		},
+		Tracker: tracker,
+	}
+	client.httpClient = &nethttp.Client{
+		Timeout:   time.Second * 300,
+		Transport: client.transport,
 	}
 
 	return client, nil
 }
 
+// GetTracker returns the tracker that records response times and failures of all requests to this client.
+func (bc *BeaconClient) GetTracker() *rpcstats.Tracker {
+	return bc.tracker
+}
+
+// IsSSZEnabled returns true if states, blocks & blob sidecars are requested as ssz.
+// This is false if ssz has been disabled for the endpoint or the endpoint failed to serve a ssz response.
+func (bc *BeaconClient) IsSSZEnabled() bool {
+	return !bc.disableSSZ && !bc.isSSZFallback()
+}
+
+// isSSZFallback returns true while the endpoint is switched to json after a failed ssz request.
+// The fallback expires after sszFallbackDuration, so the endpoint gets probed with ssz again.
+func (bc *BeaconClient) isSSZFallback() bool {
+	fallbackTime := bc.sszFallback.Load()
+	if fallbackTime == 0 {
+		return false
+	}
+
+	return time.Since(time.Unix(0, fallbackTime)) < sszFallbackDuration
+}
+
 func (bc *BeaconClient) Initialize(ctx context.Context) error {
 	if bc.clientSvc != nil {
 		return nil
 	}
 
+	clientSvc, err := bc.newClientService(ctx, bc.disableSSZ)
+	if err != nil {
+		return err
+	}
+
+	bc.clientSvc = clientSvc
+
+	return nil
+}
+
+func (bc *BeaconClient) newClientService(ctx context.Context, enforceJSON bool) (eth2client.Service, error) {
 	cliParams := []http.Parameter{
 		http.WithAddress(bc.endpoint),
 		http.WithTimeout(10 * time.Minute),
@@ -53,6 +129,10 @@ func (bc *BeaconClient) Initialize(ctx context.Context) error {
 		// TODO (when upstream PR is merged)
 		// http.WithConnectionCheck(false),
 		http.WithCustomSpecSupport(true),
+		http.WithEnforceJSON(enforceJSON),
+		http.WithHTTPClient(&nethttp.Client{
+			Transport: bc.transport,
+		}),
 	}
 
 	// set extra endpoint headers
@@ -60,16 +140,80 @@ func (bc *BeaconClient) Initialize(ctx context.Context) error {
 		cliParams = append(cliParams, http.WithExtraHeaders(bc.headers))
 	}
 
-	clientSvc, err := http.New(ctx, cliParams...)
+	return http.New(ctx, cliParams...)
+}
+
+// getSSZService returns the client service to use for endpoints that support ssz responses.
+func (bc *BeaconClient) getSSZService(ctx context.Context) (eth2client.Service, error) {
+	if bc.disableSSZ || !bc.isSSZFallback() {
+		return bc.clientSvc, nil
+	}
+
+	return bc.getJSONService(ctx)
+}
+
+func (bc *BeaconClient) getJSONService(ctx context.Context) (eth2client.Service, error) {
+	if bc.disableSSZ {
+		return bc.clientSvc, nil
+	}
+
+	bc.jsonSvcMutex.Lock()
+	defer bc.jsonSvcMutex.Unlock()
+
+	if bc.jsonClientSvc == nil {
+		clientSvc, err := bc.newClientService(ctx, true)
+		if err != nil {
+			return nil, err
+		}
+
+		bc.jsonClientSvc = clientSvc
+	}
+
+	return bc.jsonClientSvc, nil
+}
+
+// withSSZFallback runs a request against the ssz enabled client service and repeats it via json if the ssz request failed.
+// Endpoints that serve the json response but reject or send an undecodable ssz response are switched to json for sszFallbackDuration.
+func (bc *BeaconClient) withSSZFallback(ctx context.Context, request func(clientSvc eth2client.Service) error) error {
+	clientSvc, err := bc.getSSZService(ctx)
 	if err != nil {
 		return err
 	}
 
-	bc.clientSvc = clientSvc
+	err = request(clientSvc)
+	if err == nil || !bc.IsSSZEnabled() || ctx.Err() != nil || !isSSZFallbackError(err) {
+		return err
+	}
+
+	jsonSvc, err2 := bc.getJSONService(ctx)
+	if err2 != nil {
+		return err
+	}
+
+	if err2 := request(jsonSvc); err2 != nil {
+		return err2
+	}
+
+	logger.WithField("client", bc.name).Warnf("ssz request failed, using json for the next %v: %v", sszFallbackDuration, err)
+	bc.sszFallback.Store(time.Now().UnixNano())
 
 	return nil
 }
 
+// isSSZFallbackError returns true for errors caused by the ssz encoding, which are resolved by repeating the request via json:
+// the endpoint does not support ssz responses (406/415) or the ssz response could not be decoded.
+// Transient errors like timeouts, connection errors or 5xx responses are returned without fallback.
+func isSSZFallbackError(err error) bool {
+	var apiErr *api.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == nethttp.StatusNotAcceptable || apiErr.StatusCode == nethttp.StatusUnsupportedMediaType
+	}
+
+	errStr := err.Error()
+
+	return strings.Contains(errStr, "failed to decode") || strings.Contains(errStr, "unhandled content type")
+}
+
 func (bc *BeaconClient) getJSON(ctx context.Context, requrl string, returnValue interface{}) error {
 	logurl := getRedactedURL(requrl)
 
@@ -78,13 +222,13 @@ func (bc *BeaconClient) getJSON(ctx context.Context, requrl string, returnValue
 		return err
 	}
 
+	req.Header.Set("Accept", "application/json")
+
 	for headerKey, headerVal := range bc.headers {
 		req.Header.Set(headerKey, headerVal)
 	}
 
-	client := &nethttp.Client{Timeout: time.Second * 300}
-
-	resp, err := client.Do(req)
+	resp, err := bc.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -137,9 +281,7 @@ func (bc *BeaconClient) postJSON(ctx context.Context, requrl string, postData, r
 		req.Header.Set(headerKey, headerVal)
 	}
 
-	client := &nethttp.Client{Timeout: time.Second * 300}
-
-	resp, err := client.Do(req)
+	resp, err := bc.httpClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -254,6 +396,80 @@ func (bc *BeaconClient) GetConfigSpecs(ctx context.Context) (map[string]interfac
 	return result.Data, nil
 }
 
+// GetRawConfigSpecs returns the spec values as reported by the client, without any type conversion.
+func (bc *BeaconClient) GetRawConfigSpecs(ctx context.Context) (map[string]interface{}, error) {
+	var specResponse struct {
+		Data map[string]interface{} `json:"data"`
+	}
+
+	err := bc.getJSON(ctx, fmt.Sprintf("%s/eth/v1/config/spec", bc.endpoint), &specResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving config spec: %v", err)
+	}
+
+	return specResponse.Data, nil
+}
+
+func (bc *BeaconClient) GetDepositContract(ctx context.Context) (*v1.DepositContract, error) {
+	provider, isProvider := bc.clientSvc.(eth2client.DepositContractProvider)
+	if !isProvider {
+		return nil, fmt.Errorf("get deposit contract not supported")
+	}
+
+	result, err := provider.DepositContract(ctx, &api.DepositContractOpts{
+		Common: api.CommonOpts{
+			Timeout: 0,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+func (bc *BeaconClient) GetForkSchedule(ctx context.Context) ([]*phase0.Fork, error) {
+	provider, isProvider := bc.clientSvc.(eth2client.ForkScheduleProvider)
+	if !isProvider {
+		return nil, fmt.Errorf("get fork schedule not supported")
+	}
+
+	result, err := provider.ForkSchedule(ctx, &api.ForkScheduleOpts{
+		Common: api.CommonOpts{
+			Timeout: 0,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+type NodeIdentity struct {
+	PeerID             string   `json:"peer_id"`
+	ENR                string   `json:"enr"`
+	P2PAddresses       []string `json:"p2p_addresses"`
+	DiscoveryAddresses []string `json:"discovery_addresses"`
+}
+
+func (bc *BeaconClient) GetNodeIdentity(ctx context.Context) (*NodeIdentity, error) {
+	var identityResponse struct {
+		Data *NodeIdentity `json:"data"`
+	}
+
+	err := bc.getJSON(ctx, fmt.Sprintf("%s/eth/v1/node/identity", bc.endpoint), &identityResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving node identity: %v", err)
+	}
+
+	if identityResponse.Data == nil {
+		return nil, fmt.Errorf("empty node identity response")
+	}
+
+	return identityResponse.Data, nil
+}
+
 func (bc *BeaconClient) GetLatestBlockHead(ctx context.Context) (*v1.BeaconBlockHeader, error) {
 	provider, isProvider := bc.clientSvc.(eth2client.BeaconBlockHeadersProvider)
 	if !isProvider {
@@ -331,16 +547,24 @@ func (bc *BeaconClient) GetBlockHeaderBySlot(ctx context.Context, slot phase0.Sl
 }
 
 func (bc *BeaconClient) GetBlockBodyByBlockroot(ctx context.Context, blockroot phase0.Root) (*spec.VersionedSignedBeaconBlock, error) {
-	provider, isProvider := bc.clientSvc.(eth2client.SignedBeaconBlockProvider)
-	if !isProvider {
-		return nil, fmt.Errorf("get signed beacon block not supported")
-	}
+	var result *api.Response[*spec.VersionedSignedBeaconBlock]
 
-	result, err := provider.SignedBeaconBlock(ctx, &api.SignedBeaconBlockOpts{
-		Block: fmt.Sprintf("0x%x", blockroot),
-		Common: api.CommonOpts{
-			Timeout: 0,
-		},
+	err := bc.withSSZFallback(ctx, func(clientSvc eth2client.Service) error {
+		provider, isProvider := clientSvc.(eth2client.SignedBeaconBlockProvider)
+		if !isProvider {
+			return fmt.Errorf("get signed beacon block not supported")
+		}
+
+		var err error
+
+		result, err = provider.SignedBeaconBlock(ctx, &api.SignedBeaconBlockOpts{
+			Block: fmt.Sprintf("0x%x", blockroot),
+			Common: api.CommonOpts{
+				Timeout: 0,
+			},
+		})
+
+		return err
 	})
 	if err != nil {
 		if strings.HasPrefix(err.Error(), "GET failed with status 404") {
@@ -353,13 +577,83 @@ func (bc *BeaconClient) GetBlockBodyByBlockroot(ctx context.Context, blockroot p
 	return result.Data, nil
 }
 
+func (bc *BeaconClient) GetBlobSidecars(ctx context.Context, blockRef string) ([]*deneb.BlobSidecar, error) {
+	var result *api.Response[[]*deneb.BlobSidecar]
+
+	err := bc.withSSZFallback(ctx, func(clientSvc eth2client.Service) error {
+		provider, isProvider := clientSvc.(eth2client.BlobSidecarsProvider)
+		if !isProvider {
+			return fmt.Errorf("get blob sidecars not supported")
+		}
+
+		var err error
+
+		result, err = provider.BlobSidecars(ctx, &api.BlobSidecarsOpts{
+			Block: blockRef,
+			Common: api.CommonOpts{
+				Timeout: 0,
+			},
+		})
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
 func (bc *BeaconClient) GetState(ctx context.Context, stateRef string) (*spec.VersionedBeaconState, error) {
-	provider, isProvider := bc.clientSvc.(eth2client.BeaconStateProvider)
+	var result *api.Response[*spec.VersionedBeaconState]
+
+	err := bc.withSSZFallback(ctx, func(clientSvc eth2client.Service) error {
+		provider, isProvider := clientSvc.(eth2client.BeaconStateProvider)
+		if !isProvider {
+			return fmt.Errorf("get beacon state not supported")
+		}
+
+		var err error
+
+		result, err = provider.BeaconState(ctx, &api.BeaconStateOpts{
+			State: stateRef,
+			Common: api.CommonOpts{
+				Timeout: 0,
+			},
+		})
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// GetStateValidators returns all validators of the given state.
+// With ssz enabled the validators are taken from the ssz encoded beacon state, which is a lot faster to load than
+// the json validators list on networks with many validators.
+func (bc *BeaconClient) GetStateValidators(ctx context.Context, stateRef string) (map[phase0.ValidatorIndex]*v1.Validator, error) {
+	if bc.IsSSZEnabled() {
+		validators, err := bc.getStateValidatorsFromState(ctx, stateRef)
+		if err == nil {
+			return validators, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		logger.WithField("client", bc.name).Debugf("failed loading validators from ssz state, using json: %v", err)
+	}
+
+	provider, isProvider := bc.clientSvc.(eth2client.ValidatorsProvider)
 	if !isProvider {
-		return nil, fmt.Errorf("get beacon state not supported")
+		return nil, fmt.Errorf("get validators not supported")
 	}
 
-	result, err := provider.BeaconState(ctx, &api.BeaconStateOpts{
+	result, err := provider.Validators(ctx, &api.ValidatorsOpts{
 		State: stateRef,
 		Common: api.CommonOpts{
 			Timeout: 0,
@@ -372,13 +666,66 @@ func (bc *BeaconClient) GetState(ctx context.Context, stateRef string) (*spec.Ve
 	return result.Data, nil
 }
 
-func (bc *BeaconClient) GetStateValidators(ctx context.Context, stateRef string) (map[phase0.ValidatorIndex]*v1.Validator, error) {
+// GetStateValidatorsByIndices returns the given validators of the given state via a filtered validators query.
+func (bc *BeaconClient) GetStateValidatorsByIndices(ctx context.Context, stateRef string, indices []phase0.ValidatorIndex) (map[phase0.ValidatorIndex]*v1.Validator, error) {
 	provider, isProvider := bc.clientSvc.(eth2client.ValidatorsProvider)
 	if !isProvider {
 		return nil, fmt.Errorf("get validators not supported")
 	}
 
 	result, err := provider.Validators(ctx, &api.ValidatorsOpts{
+		State:   stateRef,
+		Indices: indices,
+		Common: api.CommonOpts{
+			Timeout: 0,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Data, nil
+}
+
+// GetStateValidatorsByStatus returns all validators with one of the given statuses via a filtered validators query.
+// The query is sent directly, as the eth2 client library loads the full state for queries without indices.
+func (bc *BeaconClient) GetStateValidatorsByStatus(ctx context.Context, stateRef string, statuses []v1.ValidatorState) (map[phase0.ValidatorIndex]*v1.Validator, error) {
+	statusStrs := make([]string, len(statuses))
+	for i, status := range statuses {
+		statusStrs[i] = status.String()
+	}
+
+	postData := struct {
+		Statuses []string `json:"statuses"`
+	}{
+		Statuses: statusStrs,
+	}
+
+	var validatorsResponse struct {
+		Data []*v1.Validator `json:"data"`
+	}
+
+	err := bc.postJSON(ctx, fmt.Sprintf("%s/eth/v1/beacon/states/%s/validators", bc.endpoint, stateRef), postData, &validatorsResponse)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving validators by status: %v", err)
+	}
+
+	validators := make(map[phase0.ValidatorIndex]*v1.Validator, len(validatorsResponse.Data))
+	for _, validator := range validatorsResponse.Data {
+		validators[validator.Index] = validator
+	}
+
+	return validators, nil
+}
+
+// GetStateValidatorBalances returns the balances of all validators of the given state.
+func (bc *BeaconClient) GetStateValidatorBalances(ctx context.Context, stateRef string) (map[phase0.ValidatorIndex]phase0.Gwei, error) {
+	provider, isProvider := bc.clientSvc.(eth2client.ValidatorBalancesProvider)
+	if !isProvider {
+		return nil, fmt.Errorf("get validator balances not supported")
+	}
+
+	result, err := provider.ValidatorBalances(ctx, &api.ValidatorBalancesOpts{
 		State: stateRef,
 		Common: api.CommonOpts{
 			Timeout: 0,
@@ -391,6 +738,74 @@ func (bc *BeaconClient) GetStateValidators(ctx context.Context, stateRef string)
 	return result.Data, nil
 }
 
+func (bc *BeaconClient) getStateValidatorsFromState(ctx context.Context, stateRef string) (map[phase0.ValidatorIndex]*v1.Validator, error) {
+	specProvider, isProvider := bc.clientSvc.(eth2client.SpecProvider)
+	if !isProvider {
+		return nil, fmt.Errorf("get spec not supported")
+	}
+
+	specs, err := specProvider.Spec(ctx, &api.SpecOpts{})
+	if err != nil {
+		return nil, err
+	}
+
+	slotsPerEpoch, isUint := specs.Data["SLOTS_PER_EPOCH"].(uint64)
+	if !isUint || slotsPerEpoch == 0 {
+		return nil, fmt.Errorf("invalid SLOTS_PER_EPOCH in spec")
+	}
+
+	stateProvider, isProvider := bc.clientSvc.(eth2client.BeaconStateProvider)
+	if !isProvider {
+		return nil, fmt.Errorf("get beacon state not supported")
+	}
+
+	state, err := stateProvider.BeaconState(ctx, &api.BeaconStateOpts{
+		State: stateRef,
+		Common: api.CommonOpts{
+			Timeout: 0,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slot, err := state.Data.Slot()
+	if err != nil {
+		return nil, err
+	}
+
+	stateValidators, err := state.Data.Validators()
+	if err != nil {
+		return nil, err
+	}
+
+	balances, err := state.Data.ValidatorBalances()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(balances) != len(stateValidators) {
+		return nil, fmt.Errorf("validator and balance count mismatch (%v != %v)", len(stateValidators), len(balances))
+	}
+
+	epoch := phase0.Epoch(uint64(slot) / slotsPerEpoch)
+	validators := make(map[phase0.ValidatorIndex]*v1.Validator, len(stateValidators))
+
+	for idx, validator := range stateValidators {
+		balance := balances[idx]
+		index := phase0.ValidatorIndex(idx)
+
+		validators[index] = &v1.Validator{
+			Index:     index,
+			Balance:   balance,
+			Status:    v1.ValidatorToState(validator, &balance, epoch, phase0.Epoch(math.MaxUint64)),
+			Validator: validator,
+		}
+	}
+
+	return validators, nil
+}
+
 func (bc *BeaconClient) GetProposerDuties(ctx context.Context, epoch uint64) ([]*v1.ProposerDuty, error) {
 	provider, isProvider := bc.clientSvc.(eth2client.ProposerDutiesProvider)
 	if !isProvider {
@@ -451,6 +866,29 @@ func (bc *BeaconClient) GetForkState(ctx context.Context, stateRef string) (*pha
 	return result.Data, nil
 }
 
+func (bc *BeaconClient) GetStateRoot(ctx context.Context, stateRef string) (phase0.Root, error) {
+	provider, isProvider := bc.clientSvc.(eth2client.BeaconStateRootProvider)
+	if !isProvider {
+		return phase0.Root{}, fmt.Errorf("get state root not supported")
+	}
+
+	result, err := provider.BeaconStateRoot(ctx, &api.BeaconStateRootOpts{
+		State: stateRef,
+		Common: api.CommonOpts{
+			Timeout: 0,
+		},
+	})
+	if err != nil {
+		return phase0.Root{}, err
+	}
+
+	if result.Data == nil {
+		return phase0.Root{}, fmt.Errorf("state root not found")
+	}
+
+	return *result.Data, nil
+}
+
 func (bc *BeaconClient) SubmitBLSToExecutionChanges(ctx context.Context, blsChanges []*capella.SignedBLSToExecutionChange) error {
 	submitter, isOk := bc.clientSvc.(eth2client.BLSToExecutionChangesSubmitter)
 	if !isOk {
@@ -0,0 +1,723 @@
+package consensus
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// validatorFullReloadInterval is the number of epochs between full reloads of the validator set.
+	validatorFullReloadInterval = 16
+
+	// validatorTouchedEpochs is the number of epochs a touched validator is included in incremental refreshes.
+	validatorTouchedEpochs = 4
+
+	// effective balance hysteresis (EFFECTIVE_BALANCE_INCREMENT / HYSTERESIS_QUOTIENT * multiplier)
+	effectiveBalanceDownwardThreshold = phase0.Gwei(250000000)
+	effectiveBalanceUpwardThreshold   = phase0.Gwei(1250000000)
+	minActivationBalance              = phase0.Gwei(32000000000)
+	maxEffectiveBalanceElectra        = phase0.Gwei(2048000000000)
+)
+
+// validatorStableStates are the states a validator can only leave through an operation included in a block
+// (exit, slashing, withdrawal or consolidation request) or through a balance change.
+var validatorStableStates = map[v1.ValidatorState]bool{
+	v1.ValidatorStateActiveOngoing:  true,
+	v1.ValidatorStateWithdrawalDone: true,
+}
+
+// ValidatorRegistry is an indexed copy of the validator set.
+// It is refreshed lazily once per epoch, entries that did not change keep their previous instance.
+// Per epoch refreshes reload the balances of all validators, but only reload the validator objects that can change:
+// validators in a transitional state, new validators, validators with a pending effective balance update
+// and validators touched by block operations or RefreshValidators.
+// The full validator set is reloaded every validatorFullReloadInterval epochs to catch all other changes (e.g. ejections).
+// Returned validators must be treated as read-only, as they are shared between all callers.
+type ValidatorRegistry struct {
+	pool      *Pool
+	loadMutex sync.Mutex
+
+	mutex           sync.RWMutex
+	loaded          bool
+	epoch           phase0.Epoch
+	fullLoadEpoch   phase0.Epoch
+	touchedIndices  map[phase0.ValidatorIndex]phase0.Epoch
+	validators      []*v1.Validator
+	pubkeyIndex     map[phase0.BLSPubKey]phase0.ValidatorIndex
+	withdrawalIndex map[common.Address][]phase0.ValidatorIndex
+	statusIndex     map[v1.ValidatorState][]phase0.ValidatorIndex
+	validatorMap    map[phase0.ValidatorIndex]*v1.Validator
+}
+
+func newValidatorRegistry(pool *Pool) *ValidatorRegistry {
+	return &ValidatorRegistry{
+		pool:            pool,
+		touchedIndices:  map[phase0.ValidatorIndex]phase0.Epoch{},
+		pubkeyIndex:     map[phase0.BLSPubKey]phase0.ValidatorIndex{},
+		withdrawalIndex: map[common.Address][]phase0.ValidatorIndex{},
+		statusIndex:     map[v1.ValidatorState][]phase0.ValidatorIndex{},
+	}
+}
+
+// GetWithdrawalAddress returns the execution address from 0x01 & 0x02 withdrawal credentials.
+func GetWithdrawalAddress(withdrawalCredentials []byte) (common.Address, bool) {
+	if len(withdrawalCredentials) != 32 || (withdrawalCredentials[0] != 0x01 && withdrawalCredentials[0] != 0x02) {
+		return common.Address{}, false
+	}
+
+	return common.BytesToAddress(withdrawalCredentials[12:]), true
+}
+
+func (registry *ValidatorRegistry) getCurrentEpoch() phase0.Epoch {
+	wallclock := registry.pool.blockCache.GetWallclock()
+	if wallclock == nil {
+		return 0
+	}
+
+	_, epoch, _ := wallclock.Now()
+	if epoch.Number() >= math.MaxInt64 {
+		return 0
+	}
+
+	return phase0.Epoch(epoch.Number())
+}
+
+// ensureLoaded refreshes the registry if it has not been loaded for the current epoch yet.
+func (registry *ValidatorRegistry) ensureLoaded() {
+	epoch := registry.getCurrentEpoch()
+
+	registry.mutex.RLock()
+	isFresh := registry.loaded && registry.epoch >= epoch
+	registry.mutex.RUnlock()
+
+	if isFresh {
+		return
+	}
+
+	registry.loadMutex.Lock()
+	defer registry.loadMutex.Unlock()
+
+	registry.mutex.RLock()
+	isFresh = registry.loaded && registry.epoch >= epoch
+	registry.mutex.RUnlock()
+
+	if isFresh {
+		return
+	}
+
+	if err := registry.refresh(registry.pool.ctx, epoch); err != nil {
+		registry.pool.logger.Errorf("could not load validator set: %v", err)
+	}
+}
+
+// refresh updates the registry for the given epoch.
+// It reloads the full validator set if the registry is not loaded or the last full reload is validatorFullReloadInterval
+// epochs ago, otherwise only the validators that can have changed are reloaded.
+func (registry *ValidatorRegistry) refresh(ctx context.Context, epoch phase0.Epoch) error {
+	client := registry.pool.GetReadyEndpoint(AnyClient)
+	if client == nil {
+		return fmt.Errorf("no ready client")
+	}
+
+	registry.mutex.RLock()
+	needsFullLoad := !registry.loaded || epoch >= registry.fullLoadEpoch+validatorFullReloadInterval
+	registry.mutex.RUnlock()
+
+	if !needsFullLoad {
+		err := registry.refreshIncremental(ctx, client, epoch)
+		if err == nil {
+			return nil
+		}
+
+		registry.pool.logger.Warnf("incremental validator set refresh failed, reloading full set: %v", err)
+	}
+
+	return registry.refreshFull(ctx, client, epoch)
+}
+
+// refreshFull loads the full validator set and merges it into the registry.
+func (registry *ValidatorRegistry) refreshFull(ctx context.Context, client *Client, epoch phase0.Epoch) error {
+	validatorSet, err := client.GetRPCClient().GetStateValidators(ctx, "head")
+	if err != nil {
+		return err
+	}
+
+	validators := make([]*v1.Validator, len(validatorSet))
+
+	for index, validator := range validatorSet {
+		if uint64(index) >= uint64(len(validators)) {
+			return fmt.Errorf("validator set is not continuous (index %v of %v)", index, len(validators))
+		}
+
+		validators[index] = validator
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	updated := registry.mergeValidators(validators)
+	registry.epoch = epoch
+	registry.fullLoadEpoch = epoch
+	registry.loaded = true
+
+	registry.pool.logger.Debugf("reloaded validator set for epoch %v: %v validators, %v updated", epoch, len(validators), updated)
+
+	return nil
+}
+
+// refreshIncremental reloads the balances of all validators and the validators that can have changed since the last refresh via filtered queries:
+// validators in a transitional state, validators with a pending effective balance update, recently touched validators
+// and pending validators, which include all new validators.
+func (registry *ValidatorRegistry) refreshIncremental(ctx context.Context, client *Client, epoch phase0.Epoch) error {
+	balances, err := client.GetRPCClient().GetStateValidatorBalances(ctx, "head")
+	if err != nil {
+		return fmt.Errorf("could not load validator balances: %w", err)
+	}
+
+	indices := registry.getChangeableIndices(epoch, balances)
+
+	changedSet := map[phase0.ValidatorIndex]*v1.Validator{}
+
+	if len(indices) > 0 {
+		validatorSet, err := client.GetRPCClient().GetStateValidatorsByIndices(ctx, "head", indices)
+		if err != nil {
+			return fmt.Errorf("could not load changeable validators: %w", err)
+		}
+
+		changedSet = validatorSet
+	}
+
+	pendingSet, err := client.GetRPCClient().GetStateValidatorsByStatus(ctx, "head", []v1.ValidatorState{
+		v1.ValidatorStatePendingInitialized,
+		v1.ValidatorStatePendingQueued,
+	})
+	if err != nil {
+		return fmt.Errorf("could not load pending validators: %w", err)
+	}
+
+	for index, validator := range pendingSet {
+		changedSet[index] = validator
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	validators, err := registry.applyValidatorUpdates(changedSet)
+	if err != nil {
+		return err
+	}
+
+	applyBalanceUpdates(validators, balances, changedSet)
+
+	updated := registry.mergeValidators(validators)
+	registry.epoch = epoch
+
+	registry.pool.logger.Debugf("refreshed validator set for epoch %v: %v validators, %v reloaded, %v updated", epoch, len(validators), len(changedSet), updated)
+
+	return nil
+}
+
+// getChangeableIndices returns the indices of all validators in a transitional state, of all validators with a pending
+// effective balance update and of all recently touched validators.
+func (registry *ValidatorRegistry) getChangeableIndices(epoch phase0.Epoch, balances map[phase0.ValidatorIndex]phase0.Gwei) []phase0.ValidatorIndex {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.collectTouchedIndices(epoch)
+
+	indices := []phase0.ValidatorIndex{}
+
+	for status, statusIndices := range registry.statusIndex {
+		if !validatorStableStates[status] {
+			indices = append(indices, statusIndices...)
+		}
+	}
+
+	for index, touchedEpoch := range registry.touchedIndices {
+		if epoch >= touchedEpoch+validatorTouchedEpochs {
+			delete(registry.touchedIndices, index)
+			continue
+		}
+
+		if validator := registry.getValidator(index); validator == nil || validatorStableStates[validator.Status] {
+			indices = append(indices, index)
+		}
+	}
+
+	for index, balance := range balances {
+		validator := registry.getValidator(index)
+		if validator == nil || validator.Validator == nil || !validatorStableStates[validator.Status] {
+			continue
+		}
+
+		if _, isTouched := registry.touchedIndices[index]; !isTouched && hasEffectiveBalanceUpdate(validator.Validator, balance) {
+			indices = append(indices, index)
+		}
+	}
+
+	return indices
+}
+
+// hasEffectiveBalanceUpdate returns true if the balance is outside the hysteresis of the effective balance,
+// so the effective balance gets updated in an epoch transition.
+func hasEffectiveBalanceUpdate(validator *phase0.Validator, balance phase0.Gwei) bool {
+	maxEffectiveBalance := minActivationBalance
+	if len(validator.WithdrawalCredentials) > 0 && validator.WithdrawalCredentials[0] == 0x02 {
+		maxEffectiveBalance = maxEffectiveBalanceElectra
+	}
+
+	if balance+effectiveBalanceDownwardThreshold < validator.EffectiveBalance {
+		return true
+	}
+
+	return validator.EffectiveBalance < maxEffectiveBalance && validator.EffectiveBalance+effectiveBalanceUpwardThreshold < balance
+}
+
+// applyBalanceUpdates sets the balances of all validators that have not been reloaded.
+// Validators are shared between callers, so changed entries are replaced with a copy.
+func applyBalanceUpdates(validators []*v1.Validator, balances map[phase0.ValidatorIndex]phase0.Gwei, reloaded map[phase0.ValidatorIndex]*v1.Validator) {
+	for index, balance := range balances {
+		if uint64(index) >= uint64(len(validators)) || reloaded[index] != nil {
+			continue
+		}
+
+		validator := validators[index]
+		if validator == nil || validator.Balance == balance {
+			continue
+		}
+
+		validatorCopy := *validator
+		validatorCopy.Balance = balance
+		validators[index] = &validatorCopy
+	}
+}
+
+// collectTouchedIndices marks all validators affected by operations in cached blocks since the last refresh as touched.
+func (registry *ValidatorRegistry) collectTouchedIndices(epoch phase0.Epoch) {
+	specs := registry.pool.blockCache.GetSpecs()
+	if specs == nil {
+		return
+	}
+
+	minSlot := phase0.Slot(uint64(registry.epoch) * specs.SlotsPerEpoch)
+
+	for _, block := range registry.pool.blockCache.GetCachedBlocks() {
+		if block.Slot < minSlot {
+			continue
+		}
+
+		blockData := block.GetBlock()
+		if blockData == nil {
+			continue
+		}
+
+		for _, index := range registry.getBlockOperationIndices(blockData) {
+			registry.touchedIndices[index] = epoch
+		}
+	}
+}
+
+// getBlockOperationIndices returns the indices of all validators affected by operations in the given block.
+func (registry *ValidatorRegistry) getBlockOperationIndices(blockData *spec.VersionedSignedBeaconBlock) []phase0.ValidatorIndex {
+	indices := []phase0.ValidatorIndex{}
+
+	addPubkey := func(pubkey phase0.BLSPubKey) {
+		if index, found := registry.pubkeyIndex[pubkey]; found {
+			indices = append(indices, index)
+		}
+	}
+
+	if exits, err := blockData.VoluntaryExits(); err == nil {
+		for _, exit := range exits {
+			indices = append(indices, exit.Message.ValidatorIndex)
+		}
+	}
+
+	if slashings, err := blockData.ProposerSlashings(); err == nil {
+		for _, slashing := range slashings {
+			indices = append(indices, slashing.SignedHeader1.Message.ProposerIndex)
+		}
+	}
+
+	if slashings, err := blockData.AttesterSlashings(); err == nil {
+		for _, slashing := range slashings {
+			for _, getAttestation := range []func() (*spec.VersionedIndexedAttestation, error){slashing.Attestation1, slashing.Attestation2} {
+				attestation, err := getAttestation()
+				if err != nil {
+					continue
+				}
+
+				attestingIndices, err := attestation.AttestingIndices()
+				if err != nil {
+					continue
+				}
+
+				for _, index := range attestingIndices {
+					indices = append(indices, phase0.ValidatorIndex(index))
+				}
+			}
+		}
+	}
+
+	if blsChanges, err := blockData.BLSToExecutionChanges(); err == nil {
+		for _, blsChange := range blsChanges {
+			indices = append(indices, blsChange.Message.ValidatorIndex)
+		}
+	}
+
+	if executionRequests, err := blockData.ExecutionRequests(); err == nil && executionRequests != nil {
+		for _, deposit := range executionRequests.Deposits {
+			addPubkey(deposit.Pubkey)
+		}
+
+		for _, withdrawal := range executionRequests.Withdrawals {
+			addPubkey(withdrawal.ValidatorPubkey)
+		}
+
+		for _, consolidation := range executionRequests.Consolidations {
+			addPubkey(consolidation.SourcePubkey)
+			addPubkey(consolidation.TargetPubkey)
+		}
+	}
+
+	return indices
+}
+
+// applyValidatorUpdates returns a copy of the validator list with the given validators replaced or appended.
+// New validators must be continuous with the validator list.
+func (registry *ValidatorRegistry) applyValidatorUpdates(validatorSet map[phase0.ValidatorIndex]*v1.Validator) ([]*v1.Validator, error) {
+	validators := make([]*v1.Validator, len(registry.validators), len(registry.validators)+len(validatorSet))
+	copy(validators, registry.validators)
+
+	newIndices := []phase0.ValidatorIndex{}
+
+	for index, validator := range validatorSet {
+		if uint64(index) < uint64(len(validators)) {
+			validators[index] = validator
+		} else {
+			newIndices = append(newIndices, index)
+		}
+	}
+
+	sort.Slice(newIndices, func(i, j int) bool {
+		return newIndices[i] < newIndices[j]
+	})
+
+	for _, index := range newIndices {
+		if uint64(index) != uint64(len(validators)) {
+			return nil, fmt.Errorf("validator %v is not continuous with the validator set", index)
+		}
+
+		validators = append(validators, validatorSet[index])
+	}
+
+	return validators, nil
+}
+
+func (registry *ValidatorRegistry) getValidator(index phase0.ValidatorIndex) *v1.Validator {
+	if uint64(index) >= uint64(len(registry.validators)) {
+		return nil
+	}
+
+	return registry.validators[index]
+}
+
+func (registry *ValidatorRegistry) runCleanup(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(30 * time.Second):
+		}
+
+		registry.cleanup()
+	}
+}
+
+// cleanup drops the registry if it hasn't been used for 2 epochs.
+func (registry *ValidatorRegistry) cleanup() {
+	epoch := registry.getCurrentEpoch()
+
+	registry.loadMutex.Lock()
+	defer registry.loadMutex.Unlock()
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if !registry.loaded || epoch < registry.epoch+2 {
+		return
+	}
+
+	registry.loaded = false
+	registry.validators = nil
+	registry.touchedIndices = map[phase0.ValidatorIndex]phase0.Epoch{}
+	registry.validatorMap = nil
+	registry.pubkeyIndex = map[phase0.BLSPubKey]phase0.ValidatorIndex{}
+	registry.withdrawalIndex = map[common.Address][]phase0.ValidatorIndex{}
+	registry.statusIndex = map[v1.ValidatorState][]phase0.ValidatorIndex{}
+}
+
+// RefreshValidators reloads the given validators via a filtered query, without waiting for the next epoch.
+// The validators are marked as touched, so they are included in the incremental refreshes of the next epochs.
+func (registry *ValidatorRegistry) RefreshValidators(ctx context.Context, indices []phase0.ValidatorIndex) error {
+	registry.ensureLoaded()
+
+	client := registry.pool.GetReadyEndpoint(AnyClient)
+	if client == nil {
+		return fmt.Errorf("no ready client")
+	}
+
+	validatorSet, err := client.GetRPCClient().GetStateValidatorsByIndices(ctx, "head", indices)
+	if err != nil {
+		return err
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	validators, err := registry.applyValidatorUpdates(validatorSet)
+	if err != nil {
+		return err
+	}
+
+	registry.mergeValidators(validators)
+
+	for _, index := range indices {
+		registry.touchedIndices[index] = registry.epoch
+	}
+
+	return nil
+}
+
+// mergeValidators replaces the registry content with the given validators and updates the indexes.
+// Unchanged entries keep their previous instance, so lookups of unchanged validators are stable across refreshes.
+func (registry *ValidatorRegistry) mergeValidators(validators []*v1.Validator) int {
+	updated := 0
+	statusIndex := make(map[v1.ValidatorState][]phase0.ValidatorIndex, len(registry.statusIndex))
+
+	for idx, validator := range validators {
+		index := phase0.ValidatorIndex(idx)
+
+		var oldValidator *v1.Validator
+		if idx < len(registry.validators) {
+			oldValidator = registry.validators[idx]
+		}
+
+		if oldValidator != nil && isValidatorUnchanged(oldValidator, validator) {
+			validators[idx] = oldValidator
+		} else {
+			updated++
+
+			if oldValidator == nil || oldValidator.Validator.PublicKey != validator.Validator.PublicKey {
+				registry.pubkeyIndex[validator.Validator.PublicKey] = index
+			}
+
+			if oldValidator != nil && !bytes.Equal(oldValidator.Validator.WithdrawalCredentials, validator.Validator.WithdrawalCredentials) {
+				registry.removeWithdrawalIndex(oldValidator)
+			}
+
+			if oldValidator == nil || !bytes.Equal(oldValidator.Validator.WithdrawalCredentials, validator.Validator.WithdrawalCredentials) {
+				if address, ok := GetWithdrawalAddress(validator.Validator.WithdrawalCredentials); ok {
+					registry.withdrawalIndex[address] = append(registry.withdrawalIndex[address], index)
+				}
+			}
+		}
+
+		statusIndex[validator.Status] = append(statusIndex[validator.Status], index)
+	}
+
+	if updated > 0 || len(validators) != len(registry.validators) {
+		registry.validatorMap = nil
+	}
+
+	registry.validators = validators
+	registry.statusIndex = statusIndex
+
+	return updated
+}
+
+func (registry *ValidatorRegistry) removeWithdrawalIndex(validator *v1.Validator) {
+	address, ok := GetWithdrawalAddress(validator.Validator.WithdrawalCredentials)
+	if !ok {
+		return
+	}
+
+	indices := registry.withdrawalIndex[address]
+	for i, index := range indices {
+		if index == validator.Index {
+			indices = append(indices[:i:i], indices[i+1:]...)
+			break
+		}
+	}
+
+	if len(indices) == 0 {
+		delete(registry.withdrawalIndex, address)
+	} else {
+		registry.withdrawalIndex[address] = indices
+	}
+}
+
+func isValidatorUnchanged(a, b *v1.Validator) bool {
+	if a.Balance != b.Balance || a.Status != b.Status {
+		return false
+	}
+
+	if a.Validator == nil || b.Validator == nil {
+		return a.Validator == b.Validator
+	}
+
+	return a.Validator.PublicKey == b.Validator.PublicKey &&
+		a.Validator.EffectiveBalance == b.Validator.EffectiveBalance &&
+		a.Validator.Slashed == b.Validator.Slashed &&
+		a.Validator.ActivationEligibilityEpoch == b.Validator.ActivationEligibilityEpoch &&
+		a.Validator.ActivationEpoch == b.Validator.ActivationEpoch &&
+		a.Validator.ExitEpoch == b.Validator.ExitEpoch &&
+		a.Validator.WithdrawableEpoch == b.Validator.WithdrawableEpoch &&
+		bytes.Equal(a.Validator.WithdrawalCredentials, b.Validator.WithdrawalCredentials)
+}
+
+// GetEpoch returns the epoch the registry has been loaded for.
+func (registry *ValidatorRegistry) GetEpoch() phase0.Epoch {
+	registry.ensureLoaded()
+
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	return registry.epoch
+}
+
+// GetValidatorCount returns the number of validators in the registry.
+func (registry *ValidatorRegistry) GetValidatorCount() uint64 {
+	registry.ensureLoaded()
+
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	return uint64(len(registry.validators))
+}
+
+// GetValidatorByIndex returns the validator with the given index or nil if it does not exist.
+func (registry *ValidatorRegistry) GetValidatorByIndex(index phase0.ValidatorIndex) *v1.Validator {
+	registry.ensureLoaded()
+
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	return registry.getValidator(index)
+}
+
+// GetValidatorByPubkey returns the validator with the given public key or nil if it does not exist.
+func (registry *ValidatorRegistry) GetValidatorByPubkey(pubkey phase0.BLSPubKey) *v1.Validator {
+	registry.ensureLoaded()
+
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	index, found := registry.pubkeyIndex[pubkey]
+	if !found || uint64(index) >= uint64(len(registry.validators)) {
+		return nil
+	}
+
+	return registry.validators[index]
+}
+
+// GetValidatorsByWithdrawalAddress returns all validators with 0x01 or 0x02 withdrawal credentials for the given address.
+func (registry *ValidatorRegistry) GetValidatorsByWithdrawalAddress(address common.Address) []*v1.Validator {
+	registry.ensureLoaded()
+
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	return registry.getValidatorsByIndices(registry.withdrawalIndex[address])
+}
+
+// GetValidatorsByStatus returns all validators with one of the given statuses, ordered by status & index.
+func (registry *ValidatorRegistry) GetValidatorsByStatus(statuses ...v1.ValidatorState) []*v1.Validator {
+	registry.ensureLoaded()
+
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	validators := []*v1.Validator{}
+	for _, status := range statuses {
+		validators = append(validators, registry.getValidatorsByIndices(registry.statusIndex[status])...)
+	}
+
+	return validators
+}
+
+// GetStatusCounts returns the number of validators per status.
+func (registry *ValidatorRegistry) GetStatusCounts() map[v1.ValidatorState]uint64 {
+	registry.ensureLoaded()
+
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	counts := make(map[v1.ValidatorState]uint64, len(registry.statusIndex))
+	for status, indices := range registry.statusIndex {
+		counts[status] = uint64(len(indices))
+	}
+
+	return counts
+}
+
+// GetValidators returns all validators ordered by index.
+func (registry *ValidatorRegistry) GetValidators() []*v1.Validator {
+	registry.ensureLoaded()
+
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	return registry.validators
+}
+
+// GetValidatorSet returns all validators as map by index.
+// The map is built on first use after each change, prefer the indexed lookups where possible.
+func (registry *ValidatorRegistry) GetValidatorSet() map[phase0.ValidatorIndex]*v1.Validator {
+	registry.ensureLoaded()
+
+	registry.mutex.RLock()
+	validatorMap := registry.validatorMap
+	isLoaded := registry.loaded
+	registry.mutex.RUnlock()
+
+	if validatorMap != nil || !isLoaded {
+		return validatorMap
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if registry.validatorMap == nil {
+		validatorMap = make(map[phase0.ValidatorIndex]*v1.Validator, len(registry.validators))
+		for idx, validator := range registry.validators {
+			validatorMap[phase0.ValidatorIndex(idx)] = validator
+		}
+
+		registry.validatorMap = validatorMap
+	}
+
+	return registry.validatorMap
+}
+
+func (registry *ValidatorRegistry) getValidatorsByIndices(indices []phase0.ValidatorIndex) []*v1.Validator {
+	validators := make([]*v1.Validator, 0, len(indices))
+
+	for _, index := range indices {
+		if uint64(index) < uint64(len(registry.validators)) {
+			validators = append(validators, registry.validators[index])
+		}
+	}
+
+	return validators
+}
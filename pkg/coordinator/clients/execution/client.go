@@ -3,9 +3,11 @@ package execution
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/erigontech/assertoor/pkg/coordinator/clients/execution/rpc"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/rpcstats"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/sirupsen/logrus"
 )
@@ -18,10 +20,25 @@ var (
 	ClientStatusSynchronizing ClientStatus = 3
 )
 
+func (status ClientStatus) String() string {
+	switch status {
+	case ClientStatusOnline:
+		return "online"
+	case ClientStatusOffline:
+		return "offline"
+	case ClientStatusSynchronizing:
+		return "synchronizing"
+	default:
+		return "unknown"
+	}
+}
+
 type ClientConfig struct {
 	URL     string
 	Name    string
 	Headers map[string]string
+	Weight  uint64
+	Policy  *rpcstats.PolicyConfig
 }
 
 type Client struct {
@@ -30,11 +47,13 @@ type Client struct {
 	endpointConfig  *ClientConfig
 	clientCtx       context.Context
 	clientCtxCancel context.CancelFunc
+	isClosed        atomic.Bool
+	isDisabled      atomic.Bool
 	rpcClient       *rpc.ExecutionClient
 	updateChan      chan *clientBlockNotification
 	logger          *logrus.Entry
-	isOnline        bool
-	isSyncing       bool
+	isOnline        atomic.Bool
+	isSyncing       atomic.Bool
 	versionStr      string
 	clientType      ClientType
 	lastEvent       time.Time
@@ -51,7 +70,14 @@ type clientBlockNotification struct {
 }
 
 func (pool *Pool) newPoolClient(clientIdx uint16, endpoint *ClientConfig) (*Client, error) {
-	rpcClient, err := rpc.NewExecutionClient(endpoint.Name, endpoint.URL, endpoint.Headers)
+	trackerConfig := pool.trackerConfig
+	if endpoint.Policy != nil {
+		endpointTrackerConfig := *pool.trackerConfig
+		endpointTrackerConfig.Policy = endpoint.Policy
+		trackerConfig = &endpointTrackerConfig
+	}
+
+	rpcClient, err := rpc.NewExecutionClient(endpoint.Name, endpoint.URL, endpoint.Headers, rpcstats.NewTracker(endpoint.Name, "execution", trackerConfig))
 	if err != nil {
 		return nil, err
 	}
@@ -79,6 +105,51 @@ func (client *Client) resetContext() {
 	client.clientCtx, client.clientCtxCancel = context.WithCancel(context.Background())
 }
 
+// Close stops the client loop, the client can not be used anymore afterwards.
+func (client *Client) Close() {
+	client.isClosed.Store(true)
+	client.clientCtxCancel()
+	client.rpcClient.GetTracker().Close()
+}
+
+func (client *Client) IsClosed() bool {
+	return client.isClosed.Load()
+}
+
+// SetDisabled drains or re-enables the client. Disabled clients stay connected, but are not selected as ready client.
+func (client *Client) SetDisabled(disabled bool) {
+	client.isDisabled.Store(disabled)
+	client.pool.resetHeadForkCache()
+}
+
+func (client *Client) IsDisabled() bool {
+	return client.isDisabled.Load()
+}
+
+// GetWeight returns the scheduling weight of the client, clients with higher weight get selected more often.
+func (client *Client) GetWeight() uint64 {
+	if client.endpointConfig.Weight == 0 {
+		return 1
+	}
+
+	return client.endpointConfig.Weight
+}
+
+// GetRequestStats returns the response time and failure stats of the rpc requests to this client.
+func (client *Client) GetRequestStats() rpcstats.Stats {
+	return client.rpcClient.GetTracker().GetStats()
+}
+
+// IsEjected returns true if the client has been ejected from scheduling because of repeated timeouts.
+func (client *Client) IsEjected() bool {
+	return client.rpcClient.GetTracker().IsEjected()
+}
+
+// IsFailing returns true if the client is ejected or its circuit breaker is open.
+func (client *Client) IsFailing() bool {
+	return client.rpcClient.GetTracker().IsFailing()
+}
+
 func (client *Client) GetIndex() uint16 {
 	return client.clientIdx
 }
@@ -116,9 +187,9 @@ func (client *Client) GetRPCClient() *rpc.ExecutionClient {
 
 func (client *Client) GetStatus() ClientStatus {
 	switch {
-	case client.isSyncing:
+	case client.isSyncing.Load():
 		return ClientStatusSynchronizing
-	case client.isOnline:
+	case client.isOnline.Load():
 		return ClientStatusOnline
 	default:
 		return ClientStatusOffline
@@ -126,7 +197,7 @@ func (client *Client) GetStatus() ClientStatus {
 }
 
 func (client *Client) NotifyNewBlock(hash common.Hash, number uint64) {
-	if client.isOnline {
+	if client.isOnline.Load() {
 		client.updateChan <- &clientBlockNotification{
 			hash:   hash,
 			number: number,
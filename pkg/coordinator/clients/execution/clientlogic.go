@@ -30,12 +30,12 @@ func (client *Client) runClientLoop() {
 			err = client.runClientLogic()
 		}
 
-		if err == nil {
+		if err == nil || client.isClosed.Load() {
 			client.retryCounter = 0
 			return
 		}
 
-		client.isOnline = false
+		client.isOnline.Store(false)
 		client.lastError = err
 		client.lastEvent = time.Now()
 		client.retryCounter++
@@ -48,7 +48,11 @@ func (client *Client) runClientLoop() {
 		}
 
 		client.logger.Warnf("execution client error: %v, retrying in %v sec...", err, waitTime)
-		time.Sleep(time.Duration(waitTime) * time.Second)
+		select {
+		case <-client.clientCtx.Done():
+			return
+		case <-time.After(time.Duration(waitTime) * time.Second):
+		}
 	}
 }
 
@@ -91,7 +95,7 @@ func (client *Client) checkClient() error {
 		return fmt.Errorf("could not get synchronization status")
 	}
 
-	client.isSyncing = syncStatus.IsSyncing
+	client.isSyncing.Store(syncStatus.IsSyncing)
 
 	return nil
 }
@@ -104,13 +108,13 @@ func (client *Client) runClientLogic() error {
 	}
 
 	// check latest header / sync status
-	if client.isSyncing {
+	if client.isSyncing.Load() {
 		return fmt.Errorf("beacon node is synchronizing")
 	}
 
 	// process events
 	client.lastEvent = time.Now()
-	client.isOnline = true
+	client.isOnline.Store(true)
 	client.updateChan = make(chan *clientBlockNotification, 10)
 
 	for {
@@ -148,7 +152,7 @@ func (client *Client) runClientLogic() error {
 		case <-time.After(eventTimeout):
 			err := client.pollClientHead()
 			if err != nil {
-				client.isOnline = false
+				client.isOnline.Store(false)
 				return err
 			}
 
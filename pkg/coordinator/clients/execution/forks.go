@@ -43,7 +43,7 @@ func (pool *Pool) GetHeadForks(forkDistance int64) []*HeadFork {
 
 	headForks := []*HeadFork{}
 
-	for _, client := range pool.clients {
+	for _, client := range pool.GetAllEndpoints() {
 		var matchingFork *HeadFork
 
 		cHeadSlot, cHeadRoot := client.GetLastHead()
@@ -78,7 +78,7 @@ func (pool *Pool) GetHeadForks(forkDistance int64) []*HeadFork {
 	for _, fork := range headForks {
 		fork.ReadyClients = make([]*Client, 0)
 		for _, client := range fork.AllClients {
-			if client.GetStatus() != ClientStatusOnline {
+			if client.GetStatus() != ClientStatusOnline || client.isDisabled.Load() {
 				continue
 			}
 
@@ -3,29 +3,38 @@ package execution
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/rpcstats"
 	"github.com/sirupsen/logrus"
 )
 
 type SchedulerMode uint8
 
 var (
-	RoundRobinScheduler SchedulerMode = 1
+	RoundRobinScheduler    SchedulerMode = 1
+	LowestLatencyScheduler SchedulerMode = 2
+	LeastErrorsScheduler   SchedulerMode = 3
+	StickyScheduler        SchedulerMode = 4
 )
 
 type PoolConfig struct {
 	FollowDistance uint32 `yaml:"followDistance" envconfig:"EXECUTION_POOL_FOLLOW_DISTANCE"`
 	ForkDistance   uint32 `yaml:"forkDistance" envconfig:"EXECUTION_POOL_FORK_DISTANCE"`
 	SchedulerMode  string `yaml:"schedulerMode" envconfig:"EXECUTION_POOL_SCHEDULER_MODE"`
+
+	EjectionThreshold uint64        `yaml:"ejectionThreshold" envconfig:"EXECUTION_POOL_EJECTION_THRESHOLD"`
+	EjectionDuration  time.Duration `yaml:"ejectionDuration" envconfig:"EXECUTION_POOL_EJECTION_DURATION"`
+
+	RPCPolicy *rpcstats.PolicyConfig `yaml:"rpcPolicy"`
 }
 
 type Pool struct {
 	config         *PoolConfig
 	ctx            context.Context
 	logger         logrus.FieldLogger
+	clientsMutex   sync.RWMutex
 	clientCounter  uint16
 	clients        []*Client
 	blockCache     *BlockCache
@@ -34,7 +43,9 @@ type Pool struct {
 
 	schedulerMode  SchedulerMode
 	schedulerMutex sync.Mutex
-	rrLastIndexes  map[ClientType]uint16
+	rrWeights      map[ClientType]map[uint16]int64
+	stickyClients  map[uint64]uint16
+	trackerConfig  *rpcstats.Config
 }
 
 func NewPool(ctx context.Context, config *PoolConfig, logger logrus.FieldLogger) (*Pool, error) {
@@ -44,7 +55,13 @@ func NewPool(ctx context.Context, config *PoolConfig, logger logrus.FieldLogger)
 		logger:        logger,
 		clients:       make([]*Client, 0),
 		forkCache:     map[int64][]*HeadFork{},
-		rrLastIndexes: map[ClientType]uint16{},
+		rrWeights:     map[ClientType]map[uint16]int64{},
+		stickyClients: map[uint64]uint16{},
+		trackerConfig: &rpcstats.Config{
+			EjectionThreshold: config.EjectionThreshold,
+			EjectionDuration:  config.EjectionDuration,
+			Policy:            config.RPCPolicy,
+		},
 	}
 
 	var err error
@@ -52,6 +69,12 @@ func NewPool(ctx context.Context, config *PoolConfig, logger logrus.FieldLogger)
 	switch config.SchedulerMode {
 	case "", "rr", "roundrobin":
 		pool.schedulerMode = RoundRobinScheduler
+	case "latency", "lowest-latency":
+		pool.schedulerMode = LowestLatencyScheduler
+	case "errors", "least-errors":
+		pool.schedulerMode = LeastErrorsScheduler
+	case "sticky", "sticky-per-test":
+		pool.schedulerMode = StickyScheduler
 	default:
 		return nil, fmt.Errorf("unknown pool schedulerMode: %v", config.SchedulerMode)
 	}
@@ -69,25 +92,56 @@ func (pool *Pool) GetBlockCache() *BlockCache {
 }
 
 func (pool *Pool) AddEndpoint(endpoint *ClientConfig) (*Client, error) {
+	pool.clientsMutex.Lock()
 	clientIdx := pool.clientCounter
 	pool.clientCounter++
-	client, err := pool.newPoolClient(clientIdx, endpoint)
+	pool.clientsMutex.Unlock()
 
+	client, err := pool.newPoolClient(clientIdx, endpoint)
 	if err != nil {
 		return nil, err
 	}
 
+	pool.clientsMutex.Lock()
 	pool.clients = append(pool.clients, client)
+	pool.clientsMutex.Unlock()
 
 	return client, nil
 }
 
+// RemoveEndpoint removes the client from the pool and stops its connection loop.
+func (pool *Pool) RemoveEndpoint(client *Client) {
+	pool.clientsMutex.Lock()
+
+	clients := make([]*Client, 0, len(pool.clients))
+
+	for _, poolClient := range pool.clients {
+		if poolClient != client {
+			clients = append(clients, poolClient)
+		}
+	}
+
+	pool.clients = clients
+	pool.clientsMutex.Unlock()
+
+	pool.resetHeadForkCache()
+
+	client.Close()
+}
+
+// GetAllEndpoints returns a snapshot of all clients in the pool.
 func (pool *Pool) GetAllEndpoints() []*Client {
-	return pool.clients
+	pool.clientsMutex.RLock()
+	defer pool.clientsMutex.RUnlock()
+
+	clients := make([]*Client, len(pool.clients))
+	copy(clients, pool.clients)
+
+	return clients
 }
 
 func (pool *Pool) GetReadyEndpoint(clientType ClientType) *Client {
-	readyClients := pool.GetReadyEndpoints(true)
+	readyClients := pool.GetReadyEndpoints(false)
 	selectedClient := pool.runClientScheduler(readyClients, clientType)
 
 	return selectedClient
@@ -120,14 +174,7 @@ func (pool *Pool) GetReadyEndpoints(shuffle bool) []*Client {
 	}
 
 	if shuffle {
-		shuffledClients := make([]*Client, len(readyClients))
-		perm := rand.Perm(len(readyClients))
-
-		for i, v := range perm {
-			shuffledClients[v] = readyClients[i]
-		}
-
-		return shuffledClients
+		return pool.scheduleClients(readyClients, AnyClient, 0, true)
 	}
 
 	return readyClients
@@ -152,37 +199,3 @@ func (pool *Pool) IsClientReady(client *Client) bool {
 
 	return false
 }
-
-func (pool *Pool) runClientScheduler(readyClients []*Client, clientType ClientType) *Client {
-	pool.schedulerMutex.Lock()
-	defer pool.schedulerMutex.Unlock()
-
-	if pool.schedulerMode == RoundRobinScheduler {
-		var firstReadyClient *Client
-
-		for _, client := range readyClients {
-			if clientType != AnyClient && clientType != client.clientType {
-				continue
-			}
-
-			if firstReadyClient == nil {
-				firstReadyClient = client
-			}
-
-			if client.clientIdx > pool.rrLastIndexes[clientType] {
-				pool.rrLastIndexes[clientType] = client.clientIdx
-				return client
-			}
-		}
-
-		if firstReadyClient == nil {
-			return nil
-		}
-
-		pool.rrLastIndexes[clientType] = firstReadyClient.clientIdx
-
-		return firstReadyClient
-	}
-
-	return readyClients[0]
-}
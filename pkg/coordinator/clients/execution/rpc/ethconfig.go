@@ -0,0 +1,55 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// EthConfig is the fork configuration returned by `eth_config` (EIP-7910).
+type EthConfig struct {
+	Current *EthForkConfig `json:"current"`
+	Next    *EthForkConfig `json:"next"`
+	Last    *EthForkConfig `json:"last"`
+}
+
+type EthForkConfig struct {
+	ActivationTime  uint64            `json:"activationTime"`
+	BlobSchedule    *BlobSchedule     `json:"blobSchedule"`
+	ChainID         hexutil.Big       `json:"chainId"`
+	ForkID          hexutil.Bytes     `json:"forkId"`
+	Precompiles     map[string]string `json:"precompiles"`
+	SystemContracts map[string]string `json:"systemContracts"`
+}
+
+type BlobSchedule struct {
+	Target                uint64 `json:"target"`
+	Max                   uint64 `json:"max"`
+	BaseFeeUpdateFraction uint64 `json:"baseFeeUpdateFraction"`
+}
+
+func (ec *ExecutionClient) GetEthConfig(ctx context.Context) (*EthConfig, error) {
+	closeFn := ec.enforceConcurrencyLimit(ctx)
+	if closeFn == nil {
+		return nil, fmt.Errorf("client busy")
+	}
+
+	defer closeFn()
+
+	reqCtx, reqCtxCancel := context.WithTimeout(ctx, ec.requestTimeout)
+	defer reqCtxCancel()
+
+	var result EthConfig
+
+	err := ec.rpcClient.CallContext(reqCtx, &result, "eth_config")
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Current == nil {
+		return nil, fmt.Errorf("empty eth_config response")
+	}
+
+	return &result, nil
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"net/http"
 	"time"
 
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/rpcstats"
 	"github.com/ethereum/go-ethereum"
-
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -18,6 +19,7 @@ type ExecutionClient struct {
 	name             string
 	endpoint         string
 	headers          map[string]string
+	tracker          *rpcstats.Tracker
 	rpcClient        *rpc.Client
 	ethClient        *ethclient.Client
 	concurrencyLimit int
@@ -26,11 +28,16 @@ type ExecutionClient struct {
 }
 
 // NewExecutionClient is used to create a new execution client
-func NewExecutionClient(name, url string, headers map[string]string) (*ExecutionClient, error) {
+func NewExecutionClient(name, url string, headers map[string]string, tracker *rpcstats.Tracker) (*ExecutionClient, error) {
+	if tracker == nil {
+		tracker = rpcstats.NewTracker(name, "execution", nil)
+	}
+
 	client := &ExecutionClient{
 		name:             name,
 		endpoint:         url,
 		headers:          headers,
+		tracker:          tracker,
 		concurrencyLimit: 50,
 		requestTimeout:   30 * time.Second,
 	}
@@ -45,7 +52,11 @@ func (ec *ExecutionClient) Initialize(ctx context.Context) error {
 		return nil
 	}
 
-	rpcClient, err := rpc.DialContext(ctx, ec.endpoint)
+	rpcClient, err := rpc.DialOptions(ctx, ec.endpoint, rpc.WithHTTPClient(&http.Client{
+		Transport: &rpcstats.Transport{
+			Tracker: ec.tracker,
+		},
+	}))
 	if err != nil {
 		return err
 	}
@@ -60,6 +71,11 @@ func (ec *ExecutionClient) Initialize(ctx context.Context) error {
 	return nil
 }
 
+// GetTracker returns the tracker that records response times and failures of all requests to this client.
+func (ec *ExecutionClient) GetTracker() *rpcstats.Tracker {
+	return ec.tracker
+}
+
 func (ec *ExecutionClient) enforceConcurrencyLimit(ctx context.Context) func() {
 	select {
 	case <-ctx.Done():
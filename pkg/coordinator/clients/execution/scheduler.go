@@ -0,0 +1,152 @@
+package execution
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// GetReadyEndpointsForTest returns the ready clients for the given test run in scheduling order.
+// In sticky scheduling mode, only the client assigned to the test run is returned, as long as the client is ready.
+func (pool *Pool) GetReadyEndpointsForTest(testRunID uint64) []*Client {
+	canonicalFork := pool.GetCanonicalFork(-1)
+	if canonicalFork == nil {
+		return nil
+	}
+
+	return pool.scheduleClients(canonicalFork.ReadyClients, AnyClient, testRunID, true)
+}
+
+// ReleaseTestClient removes the sticky client assignment of the given test run.
+func (pool *Pool) ReleaseTestClient(testRunID uint64) {
+	pool.schedulerMutex.Lock()
+	defer pool.schedulerMutex.Unlock()
+
+	delete(pool.stickyClients, testRunID)
+}
+
+func (pool *Pool) runClientScheduler(readyClients []*Client, clientType ClientType) *Client {
+	clients := pool.scheduleClients(readyClients, clientType, 0, false)
+	if len(clients) == 0 {
+		return nil
+	}
+
+	return clients[0]
+}
+
+// scheduleClients returns the ready clients of the given type in the order they should be used.
+// Failing clients (ejected or with open circuit breaker) are only returned if all matching clients are failing.
+func (pool *Pool) scheduleClients(readyClients []*Client, clientType ClientType, testRunID uint64, shuffle bool) []*Client {
+	candidates := make([]*Client, 0, len(readyClients))
+	failingCandidates := make([]*Client, 0)
+
+	for _, client := range readyClients {
+		if clientType != AnyClient && clientType != client.clientType {
+			continue
+		}
+
+		if client.IsFailing() {
+			failingCandidates = append(failingCandidates, client)
+		} else {
+			candidates = append(candidates, client)
+		}
+	}
+
+	if len(candidates) == 0 {
+		// better use a struggling client than none at all
+		candidates = failingCandidates
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	pool.schedulerMutex.Lock()
+	defer pool.schedulerMutex.Unlock()
+
+	if pool.schedulerMode == StickyScheduler && testRunID != 0 {
+		if clientIdx, found := pool.stickyClients[testRunID]; found {
+			for _, client := range candidates {
+				if client.clientIdx == clientIdx {
+					return []*Client{client}
+				}
+			}
+		}
+
+		client := pool.getRoundRobinOrder(candidates, clientType)[0]
+		pool.stickyClients[testRunID] = client.clientIdx
+
+		return []*Client{client}
+	}
+
+	if shuffle {
+		candidates = getWeightedShuffle(candidates)
+	} else {
+		candidates = pool.getRoundRobinOrder(candidates, clientType)
+	}
+
+	switch pool.schedulerMode {
+	case LowestLatencyScheduler:
+		sort.SliceStable(candidates, func(a, b int) bool {
+			latencyA := float64(candidates[a].rpcClient.GetTracker().GetAvgLatency()) / float64(candidates[a].GetWeight())
+			latencyB := float64(candidates[b].rpcClient.GetTracker().GetAvgLatency()) / float64(candidates[b].GetWeight())
+
+			return latencyA < latencyB
+		})
+	case LeastErrorsScheduler:
+		sort.SliceStable(candidates, func(a, b int) bool {
+			return candidates[a].rpcClient.GetTracker().GetErrorRate() < candidates[b].rpcClient.GetTracker().GetErrorRate()
+		})
+	}
+
+	return candidates
+}
+
+// getRoundRobinOrder selects the next client via smooth weighted round robin and returns it as first client,
+// followed by the remaining clients in pool order.
+func (pool *Pool) getRoundRobinOrder(candidates []*Client, clientType ClientType) []*Client {
+	rrWeights := pool.rrWeights[clientType]
+	if rrWeights == nil {
+		rrWeights = map[uint16]int64{}
+		pool.rrWeights[clientType] = rrWeights
+	}
+
+	totalWeight := int64(0)
+	selectedPos := 0
+
+	for pos, client := range candidates {
+		weight := int64(client.GetWeight()) //nolint:gosec // no overflow possible
+		totalWeight += weight
+		rrWeights[client.clientIdx] += weight
+
+		if rrWeights[client.clientIdx] > rrWeights[candidates[selectedPos].clientIdx] {
+			selectedPos = pos
+		}
+	}
+
+	rrWeights[candidates[selectedPos].clientIdx] -= totalWeight
+
+	ordered := make([]*Client, 0, len(candidates))
+	for i := range candidates {
+		ordered = append(ordered, candidates[(selectedPos+i)%len(candidates)])
+	}
+
+	return ordered
+}
+
+// getWeightedShuffle returns the clients in random order, clients with higher weight tend to be placed first.
+func getWeightedShuffle(candidates []*Client) []*Client {
+	keys := make(map[*Client]float64, len(candidates))
+	for _, client := range candidates {
+		keys[client] = math.Pow(rand.Float64(), 1/float64(client.GetWeight())) //nolint:gosec // no need for secure randomness
+	}
+
+	shuffled := make([]*Client, len(candidates))
+	copy(shuffled, candidates)
+
+	sort.Slice(shuffled, func(a, b int) bool {
+		return keys[shuffled[a]] > keys[shuffled[b]]
+	})
+
+	return shuffled
+}
@@ -0,0 +1,79 @@
+package rpcstats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	trackersMutex sync.RWMutex
+	trackers      = map[*Tracker]bool{}
+
+	metricLabels = []string{"client", "layer"}
+
+	requestsDesc = prometheus.NewDesc("assertoor_rpc_requests_total", "Number of rpc requests sent to the endpoint.", metricLabels, nil)
+	errorsDesc   = prometheus.NewDesc("assertoor_rpc_errors_total", "Number of failed rpc requests.", metricLabels, nil)
+	timeoutsDesc = prometheus.NewDesc("assertoor_rpc_timeouts_total", "Number of timed out rpc requests.", metricLabels, nil)
+	retriesDesc  = prometheus.NewDesc("assertoor_rpc_retries_total", "Number of retried rpc requests.", metricLabels, nil)
+	latencyDesc  = prometheus.NewDesc("assertoor_rpc_latency_seconds", "Moving average of the rpc response time.", metricLabels, nil)
+	errRateDesc  = prometheus.NewDesc("assertoor_rpc_error_rate", "Moving average of the rpc error rate.", metricLabels, nil)
+	ejectedDesc  = prometheus.NewDesc("assertoor_rpc_ejected", "Whether the endpoint is ejected from scheduling (1) or not (0).", metricLabels, nil)
+	circuitDesc  = prometheus.NewDesc("assertoor_rpc_circuit_state", "Circuit breaker state (0 = closed, 1 = open, 2 = half-open).", metricLabels, nil)
+)
+
+// trackerCollector exports the stats of all active trackers.
+type trackerCollector struct{}
+
+func init() {
+	prometheus.MustRegister(&trackerCollector{})
+}
+
+func registerTracker(tracker *Tracker) {
+	trackersMutex.Lock()
+	defer trackersMutex.Unlock()
+
+	trackers[tracker] = true
+}
+
+func unregisterTracker(tracker *Tracker) {
+	trackersMutex.Lock()
+	defer trackersMutex.Unlock()
+
+	delete(trackers, tracker)
+}
+
+func (c *trackerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- requestsDesc
+	ch <- errorsDesc
+	ch <- timeoutsDesc
+	ch <- retriesDesc
+	ch <- latencyDesc
+	ch <- errRateDesc
+	ch <- ejectedDesc
+	ch <- circuitDesc
+}
+
+func (c *trackerCollector) Collect(ch chan<- prometheus.Metric) {
+	trackersMutex.RLock()
+	defer trackersMutex.RUnlock()
+
+	for tracker := range trackers {
+		stats := tracker.GetStats()
+		ejected := 0.0
+
+		if time.Now().Before(stats.EjectedUntil) {
+			ejected = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(requestsDesc, prometheus.CounterValue, float64(stats.Requests), tracker.name, tracker.layer)
+		ch <- prometheus.MustNewConstMetric(errorsDesc, prometheus.CounterValue, float64(stats.Errors), tracker.name, tracker.layer)
+		ch <- prometheus.MustNewConstMetric(timeoutsDesc, prometheus.CounterValue, float64(stats.Timeouts), tracker.name, tracker.layer)
+		ch <- prometheus.MustNewConstMetric(retriesDesc, prometheus.CounterValue, float64(stats.Retries), tracker.name, tracker.layer)
+		ch <- prometheus.MustNewConstMetric(latencyDesc, prometheus.GaugeValue, stats.AvgLatency.Seconds(), tracker.name, tracker.layer)
+		ch <- prometheus.MustNewConstMetric(errRateDesc, prometheus.GaugeValue, stats.ErrorRate, tracker.name, tracker.layer)
+		ch <- prometheus.MustNewConstMetric(ejectedDesc, prometheus.GaugeValue, ejected, tracker.name, tracker.layer)
+		ch <- prometheus.MustNewConstMetric(circuitDesc, prometheus.GaugeValue, float64(stats.CircuitState), tracker.name, tracker.layer)
+	}
+}
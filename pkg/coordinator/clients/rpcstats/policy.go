@@ -0,0 +1,119 @@
+package rpcstats
+
+import (
+	"strings"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+)
+
+const (
+	defaultRetryBackoff           = 250 * time.Millisecond
+	defaultMaxRetryBackoff        = 5 * time.Second
+	defaultCircuitBreakerDuration = 30 * time.Second
+)
+
+// PolicyConfig defines how requests to an endpoint are retried, timed out and cut off when the endpoint keeps failing.
+type PolicyConfig struct {
+	// Number of retries for failed idempotent requests
+	MaxRetries uint64 `yaml:"maxRetries" json:"maxRetries"`
+
+	// Delay before the first retry, doubled for each further retry
+	RetryBackoff helper.Duration `yaml:"retryBackoff" json:"retryBackoff"`
+
+	// Upper limit for the retry delay
+	MaxRetryBackoff helper.Duration `yaml:"maxRetryBackoff" json:"maxRetryBackoff"`
+
+	// Timeout for a single request attempt, 0 keeps the timeout of the caller
+	Timeout helper.Duration `yaml:"timeout" json:"timeout"`
+
+	// Timeouts for specific methods (json-rpc method names or beacon api path prefixes)
+	MethodTimeouts map[string]helper.Duration `yaml:"methodTimeouts" json:"methodTimeouts"`
+
+	// Number of consecutive failures after which the circuit breaker opens, 0 disables the circuit breaker
+	CircuitBreakerThreshold uint64 `yaml:"circuitBreakerThreshold" json:"circuitBreakerThreshold"`
+
+	// Duration the circuit breaker stays open before a probe request is let through
+	CircuitBreakerDuration helper.Duration `yaml:"circuitBreakerDuration" json:"circuitBreakerDuration"`
+}
+
+func (p *PolicyConfig) getTimeout(method string) time.Duration {
+	if p == nil {
+		return 0
+	}
+
+	if timeout, found := p.MethodTimeouts[method]; found {
+		return timeout.Duration
+	}
+
+	// beacon api methods are matched by the longest path prefix
+	matchLen := 0
+	timeout := p.Timeout.Duration
+
+	for prefix, prefixTimeout := range p.MethodTimeouts {
+		if strings.HasPrefix(prefix, "/") && strings.HasPrefix(method, prefix) && len(prefix) > matchLen {
+			matchLen = len(prefix)
+			timeout = prefixTimeout.Duration
+		}
+	}
+
+	return timeout
+}
+
+func (p *PolicyConfig) getMaxRetries() uint64 {
+	if p == nil {
+		return 0
+	}
+
+	return p.MaxRetries
+}
+
+func (p *PolicyConfig) getRetryBackoff(retry uint64) time.Duration {
+	backoff := defaultRetryBackoff
+	maxBackoff := defaultMaxRetryBackoff
+
+	if p != nil && p.RetryBackoff.Duration > 0 {
+		backoff = p.RetryBackoff.Duration
+	}
+
+	if p != nil && p.MaxRetryBackoff.Duration > 0 {
+		maxBackoff = p.MaxRetryBackoff.Duration
+	}
+
+	for i := uint64(0); i < retry && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+
+	return backoff
+}
+
+func (p *PolicyConfig) getCircuitBreakerThreshold() uint64 {
+	if p == nil {
+		return 0
+	}
+
+	return p.CircuitBreakerThreshold
+}
+
+func (p *PolicyConfig) getCircuitBreakerDuration() time.Duration {
+	if p == nil || p.CircuitBreakerDuration.Duration == 0 {
+		return defaultCircuitBreakerDuration
+	}
+
+	return p.CircuitBreakerDuration.Duration
+}
+
+// isIdempotentMethod returns false for json-rpc methods that change state and must not be sent twice.
+func isIdempotentMethod(method string) bool {
+	for _, prefix := range []string{"eth_send", "eth_submit", "engine_", "admin_", "miner_", "personal_", "debug_set"} {
+		if strings.HasPrefix(method, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
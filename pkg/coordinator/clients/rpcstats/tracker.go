@@ -0,0 +1,282 @@
+package rpcstats
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	latencySmoothing   = 0.2
+	errorRateSmoothing = 0.1
+	maxEjectionBackoff = 8
+)
+
+type CircuitState uint8
+
+var (
+	CircuitClosed   CircuitState
+	CircuitOpen     CircuitState = 1
+	CircuitHalfOpen CircuitState = 2
+)
+
+func (state CircuitState) String() string {
+	switch state {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrCircuitOpen is returned for requests to an endpoint with open circuit breaker.
+var ErrCircuitOpen = errors.New("circuit breaker open, endpoint is failing")
+
+type Config struct {
+	// Number of consecutive timeouts after which the client is ejected, 0 disables ejection
+	EjectionThreshold uint64
+
+	// Duration of the first ejection, repeated ejections double the duration
+	EjectionDuration time.Duration
+
+	// Retry, timeout & circuit breaker policy
+	Policy *PolicyConfig
+}
+
+// Tracker records response times and failures of the rpc requests to a single endpoint.
+type Tracker struct {
+	name                string
+	layer               string
+	config              *Config
+	mutex               sync.RWMutex
+	requests            uint64
+	errors              uint64
+	timeouts            uint64
+	retries             uint64
+	avgLatency          time.Duration
+	errorRate           float64
+	consecutiveTimeouts uint64
+	consecutiveFailures uint64
+	ejections           uint64
+	ejectionBackoff     uint64
+	ejectedUntil        time.Time
+	circuitState        CircuitState
+	circuitOpenUntil    time.Time
+	circuitProbing      bool
+	circuitOpenings     uint64
+}
+
+type Stats struct {
+	Requests        uint64
+	Errors          uint64
+	Timeouts        uint64
+	Retries         uint64
+	AvgLatency      time.Duration
+	ErrorRate       float64
+	Ejections       uint64
+	EjectedUntil    time.Time
+	CircuitState    CircuitState
+	CircuitOpenings uint64
+}
+
+// NewTracker creates a tracker for the given endpoint. The layer (consensus or execution) is used as metrics label.
+func NewTracker(name, layer string, config *Config) *Tracker {
+	if config == nil {
+		config = &Config{}
+	}
+
+	tracker := &Tracker{
+		name:   name,
+		layer:  layer,
+		config: config,
+	}
+
+	registerTracker(tracker)
+
+	return tracker
+}
+
+// Close removes the tracker from the metrics.
+func (t *Tracker) Close() {
+	unregisterTracker(t)
+}
+
+func (t *Tracker) GetPolicy() *PolicyConfig {
+	return t.config.Policy
+}
+
+// RecordRequest adds the result of a request to the stats.
+// Requests that were cancelled by the caller are ignored.
+func (t *Tracker) RecordRequest(latency time.Duration, err error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if errors.Is(err, context.Canceled) {
+		t.circuitProbing = false
+		return
+	}
+
+	t.requests++
+
+	if t.requests == 1 {
+		t.avgLatency = latency
+	} else {
+		t.avgLatency = time.Duration(float64(t.avgLatency)*(1-latencySmoothing) + float64(latency)*latencySmoothing)
+	}
+
+	errorValue := 0.0
+
+	switch {
+	case err == nil:
+		t.consecutiveTimeouts = 0
+		t.consecutiveFailures = 0
+		t.ejectionBackoff = 0
+	case isTimeout(err):
+		errorValue = 1
+		t.errors++
+		t.timeouts++
+		t.consecutiveTimeouts++
+		t.consecutiveFailures++
+
+		if t.config.EjectionThreshold > 0 && t.consecutiveTimeouts >= t.config.EjectionThreshold && !t.isEjected() {
+			backoff := uint64(1) << t.ejectionBackoff
+			if backoff < maxEjectionBackoff {
+				t.ejectionBackoff++
+			}
+
+			t.ejections++
+			t.ejectedUntil = time.Now().Add(t.config.EjectionDuration * time.Duration(backoff))
+			t.consecutiveTimeouts = 0
+		}
+	default:
+		errorValue = 1
+		t.errors++
+		t.consecutiveFailures++
+	}
+
+	t.errorRate = t.errorRate*(1-errorRateSmoothing) + errorValue*errorRateSmoothing
+
+	t.updateCircuitState(err == nil)
+}
+
+func (t *Tracker) updateCircuitState(success bool) {
+	threshold := t.config.Policy.getCircuitBreakerThreshold()
+	if threshold == 0 {
+		return
+	}
+
+	t.circuitProbing = false
+
+	switch {
+	case success:
+		t.circuitState = CircuitClosed
+	case t.circuitState == CircuitHalfOpen || (t.circuitState == CircuitClosed && t.consecutiveFailures >= threshold):
+		t.circuitState = CircuitOpen
+		t.circuitOpenUntil = time.Now().Add(t.config.Policy.getCircuitBreakerDuration())
+		t.circuitOpenings++
+	}
+}
+
+// AllowRequest checks the circuit breaker and returns ErrCircuitOpen if the request should not be sent.
+// After the circuit breaker duration, a single probe request is let through to check whether the endpoint recovered.
+func (t *Tracker) AllowRequest() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	switch t.circuitState {
+	case CircuitOpen:
+		if time.Now().Before(t.circuitOpenUntil) {
+			return ErrCircuitOpen
+		}
+
+		t.circuitState = CircuitHalfOpen
+		t.circuitProbing = true
+	case CircuitHalfOpen:
+		if t.circuitProbing {
+			return ErrCircuitOpen
+		}
+
+		t.circuitProbing = true
+	}
+
+	return nil
+}
+
+// RecordRetry counts a retried request.
+func (t *Tracker) RecordRetry() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.retries++
+}
+
+func (t *Tracker) isEjected() bool {
+	return time.Now().Before(t.ejectedUntil)
+}
+
+// IsEjected returns true if the endpoint timed out repeatedly and should not be scheduled for a while.
+func (t *Tracker) IsEjected() bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.isEjected()
+}
+
+// IsFailing returns true if the endpoint is ejected or its circuit breaker is open.
+func (t *Tracker) IsFailing() bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.isEjected() || t.circuitState != CircuitClosed
+}
+
+func (t *Tracker) GetAvgLatency() time.Duration {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.avgLatency
+}
+
+func (t *Tracker) GetErrorRate() float64 {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return t.errorRate
+}
+
+func (t *Tracker) GetStats() Stats {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	return Stats{
+		Requests:        t.requests,
+		Errors:          t.errors,
+		Timeouts:        t.timeouts,
+		Retries:         t.retries,
+		AvgLatency:      t.avgLatency,
+		ErrorRate:       t.errorRate,
+		Ejections:       t.ejections,
+		EjectedUntil:    t.ejectedUntil,
+		CircuitState:    t.circuitState,
+		CircuitOpenings: t.circuitOpenings,
+	}
+}
+
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,157 @@
+package rpcstats
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Transport is a http.RoundTripper that applies the request policy of the tracker and records all requests.
+// Server errors (5xx) and rate limit responses (429) are counted as failed requests.
+type Transport struct {
+	Base    http.RoundTripper
+	Tracker *Tracker
+}
+
+type jsonRPCRequest struct {
+	Method string `json:"method"`
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	method, idempotent, body, err := getRequestMethod(req)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := t.Tracker.GetPolicy()
+	maxRetries := uint64(0)
+
+	if idempotent {
+		maxRetries = policy.getMaxRetries()
+	}
+
+	for retry := uint64(0); ; retry++ {
+		if err := t.Tracker.AllowRequest(); err != nil {
+			return nil, err
+		}
+
+		attemptReq := req
+		cancelFn := func() {}
+
+		if timeout := policy.getTimeout(method); timeout > 0 {
+			var attemptCtx context.Context
+
+			attemptCtx, cancelFn = context.WithTimeout(req.Context(), timeout)
+			attemptReq = req.WithContext(attemptCtx)
+		}
+
+		if body != nil {
+			attemptReq = attemptReq.Clone(attemptReq.Context())
+			attemptReq.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		startTime := time.Now()
+		resp, err := base.RoundTrip(attemptReq)
+
+		var failure error
+
+		switch {
+		case err != nil:
+			failure = err
+		case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+			failure = fmt.Errorf("http status %v", resp.StatusCode)
+		}
+
+		t.Tracker.RecordRequest(time.Since(startTime), failure)
+
+		if failure == nil || retry >= maxRetries || req.Context().Err() != nil {
+			if resp != nil {
+				// the attempt context must stay active until the response body has been read
+				resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancelFn: cancelFn}
+			} else {
+				cancelFn()
+			}
+
+			return resp, err
+		}
+
+		if resp != nil {
+			//nolint:errcheck // ignore
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		cancelFn()
+		t.Tracker.RecordRetry()
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(policy.getRetryBackoff(retry)):
+		}
+	}
+}
+
+// getRequestMethod returns the json-rpc method or the beacon api path of the request, whether it can be retried safely
+// and the buffered request body.
+func getRequestMethod(req *http.Request) (method string, idempotent bool, body []byte, err error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req.URL.Path, req.Method == http.MethodGet || req.Method == http.MethodHead, nil, nil
+	}
+
+	body, err = io.ReadAll(req.Body)
+	if err != nil {
+		return "", false, nil, err
+	}
+
+	if err := req.Body.Close(); err != nil {
+		return "", false, nil, err
+	}
+
+	trimmedBody := bytes.TrimSpace(body)
+
+	switch {
+	case len(trimmedBody) > 0 && trimmedBody[0] == '{':
+		rpcReq := &jsonRPCRequest{}
+		if json.Unmarshal(trimmedBody, rpcReq) == nil && rpcReq.Method != "" {
+			return rpcReq.Method, isIdempotentMethod(rpcReq.Method), body, nil
+		}
+	case len(trimmedBody) > 0 && trimmedBody[0] == '[':
+		// batch requests are only idempotent if all methods are
+		rpcReqs := []*jsonRPCRequest{}
+		if json.Unmarshal(trimmedBody, &rpcReqs) == nil && len(rpcReqs) > 0 && rpcReqs[0] != nil && rpcReqs[0].Method != "" {
+			idempotent = true
+
+			for _, rpcReq := range rpcReqs {
+				if rpcReq == nil || rpcReq.Method == "" || !isIdempotentMethod(rpcReq.Method) {
+					idempotent = false
+				}
+			}
+
+			return rpcReqs[0].Method, idempotent, body, nil
+		}
+	}
+
+	return req.URL.Path, false, body, nil
+}
+
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancelFn context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancelFn()
+
+	return err
+}
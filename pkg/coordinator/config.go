@@ -5,11 +5,12 @@ import (
 
 	"github.com/erigontech/assertoor/pkg/coordinator/clients"
 	"github.com/erigontech/assertoor/pkg/coordinator/db"
+	"github.com/erigontech/assertoor/pkg/coordinator/discovery"
 	"github.com/erigontech/assertoor/pkg/coordinator/helper"
 	"github.com/erigontech/assertoor/pkg/coordinator/names"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	web_types "github.com/erigontech/assertoor/pkg/coordinator/web/types"
-	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
@@ -19,6 +20,12 @@ type Config struct {
 	// List of execution & consensus clients to use.
 	Endpoints []clients.ClientConfig `yaml:"endpoints" json:"endpoints"`
 
+	// Client pool scheduling config
+	ClientPool *clients.PoolConfig `yaml:"clientPool" json:"clientPool"`
+
+	// Automatic endpoint discovery
+	Discovery *discovery.Config `yaml:"discovery" json:"discovery"`
+
 	// WebServer config
 	Web *web_types.WebConfig `yaml:"web" json:"web"`
 
@@ -28,6 +35,12 @@ type Config struct {
 	// Global variables
 	GlobalVars map[string]interface{} `yaml:"globalVars" json:"globalVars"`
 
+	// Global variables with secret values, which are masked in logs, database and api responses
+	Secrets map[string]interface{} `yaml:"secrets" json:"secrets"`
+
+	// External secret providers, that can be referenced in variables via `<name>:<path>`
+	SecretProviders []*secrets.ProviderConfig `yaml:"secretProviders" json:"secretProviders"`
+
 	// Coordinator config
 	Coordinator *CoordinatorConfig `yaml:"coordinator" json:"coordinator"`
 
@@ -43,6 +56,9 @@ type CoordinatorConfig struct {
 	// Maximum number of tests executed concurrently
 	MaxConcurrentTests uint64 `yaml:"maxConcurrentTests" json:"maxConcurrentTests"`
 
+	// Maximum number of tests executed concurrently per concurrency group
+	ConcurrencyGroups map[string]uint64 `yaml:"concurrencyGroups" json:"concurrencyGroups"`
+
 	// Test history cleanup delay
 	TestRetentionTime helper.Duration `yaml:"testRetentionTime" json:"testRetentionTime"`
 }
@@ -76,7 +92,7 @@ func NewConfig(path string) (*Config, error) {
 		return nil, err
 	}
 
-	if err := yaml.Unmarshal(yamlFile, &config); err != nil {
+	if err := secrets.UnmarshalYaml(yamlFile, &config); err != nil {
 		return nil, err
 	}
 
@@ -9,14 +9,18 @@ import (
 	"runtime"
 	"runtime/debug"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/erigontech/assertoor/pkg/coordinator/buildinfo"
 	"github.com/erigontech/assertoor/pkg/coordinator/clients"
 	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus"
 	"github.com/erigontech/assertoor/pkg/coordinator/db"
+	"github.com/erigontech/assertoor/pkg/coordinator/discovery"
+	"github.com/erigontech/assertoor/pkg/coordinator/locks"
 	"github.com/erigontech/assertoor/pkg/coordinator/logger"
 	"github.com/erigontech/assertoor/pkg/coordinator/names"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/test"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/erigontech/assertoor/pkg/coordinator/vars"
@@ -31,28 +35,36 @@ import (
 
 type Coordinator struct {
 	// Config is the coordinator configuration.
-	Config          *Config
-	log             *logger.LogScope
-	database        *db.Database
-	clientPool      *clients.ClientPool
-	walletManager   *wallet.Manager
-	webserver       *web.Server
-	publicWebserver *web.Server
-	validatorNames  *names.ValidatorNames
-	globalVars      types.Variables
-	metricsPort     int
+	Config           *Config
+	configPath       string
+	log              *logger.LogScope
+	database         *db.Database
+	clientPool       *clients.ClientPool
+	walletManager    *wallet.Manager
+	lockManager      *locks.Manager
+	secretResolver   *secrets.Resolver
+	discovery        *discovery.Manager
+	webserver        *web.Server
+	publicWebserver  *web.Server
+	validatorNames   *names.ValidatorNames
+	globalVars       types.Variables
+	globalVarsMutex  sync.RWMutex
+	reloadMutex      sync.Mutex
+	pendingEndpoints []clients.ClientConfig
+	metricsPort      int
 
 	registry *TestRegistry
 	runner   *TestRunner
 }
 
-func NewCoordinator(config *Config, log logrus.FieldLogger, metricsPort int) *Coordinator {
+func NewCoordinator(config *Config, configPath string, log logrus.FieldLogger, metricsPort int) *Coordinator {
 	return &Coordinator{
 		log: logger.NewLogger(&logger.ScopeOptions{
 			Parent:     log,
 			BufferSize: 5000,
 		}),
 		Config:      config,
+		configPath:  configPath,
 		metricsPort: metricsPort,
 	}
 }
@@ -125,27 +137,45 @@ func (c *Coordinator) Run(ctx context.Context) error {
 	}
 
 	// init client pool
-	clientPool, err := clients.NewClientPool(c.log.GetLogger())
+	clientPool, err := clients.NewClientPool(c.Config.ClientPool, c.log.GetLogger())
 	if err != nil {
 		return err
 	}
 
 	c.clientPool = clientPool
 	c.walletManager = wallet.NewManager(clientPool.GetExecutionPool(), c.log.GetLogger().WithField("module", "wallet"))
+	c.lockManager = locks.NewManager(c.log.GetLogger().WithField("module", "locks"))
+
+	c.secretResolver, err = secrets.NewResolver(c.Config.SecretProviders)
+	if err != nil {
+		return err
+	}
 
 	for idx := range c.Config.Endpoints {
+		err = c.resolveEndpointSecrets(ctx, &c.Config.Endpoints[idx])
+		if err != nil {
+			return err
+		}
+
 		err = clientPool.AddClient(&c.Config.Endpoints[idx])
 		if err != nil {
 			return err
 		}
 	}
 
-	// init global variables
-	c.globalVars = vars.NewVariables(nil)
-	for name, value := range c.Config.GlobalVars {
-		c.globalVars.SetVar(name, value)
+	// start endpoint discovery
+	if c.Config.Discovery != nil && len(c.Config.Discovery.Providers) > 0 {
+		c.discovery, err = discovery.NewManager(ctx, c.Config.Discovery, clientPool, c.secretResolver, c.log.GetLogger().WithField("module", "discovery"))
+		if err != nil {
+			return err
+		}
+
+		go c.discovery.Run(ctx)
 	}
 
+	// init global variables
+	c.globalVars = c.newGlobalVariables(c.Config)
+
 	// init webserver
 	if c.Config.Web != nil {
 		if c.Config.Web.Server != nil {
@@ -180,6 +210,9 @@ func (c *Coordinator) Run(ctx context.Context) error {
 	c.validatorNames = names.NewValidatorNames(c.Config.ValidatorNames, c.log.GetLogger())
 	c.validatorNames.LoadValidatorNames()
 
+	// register chain aware functions for variable queries
+	c.registerJqFunctions()
+
 	// init test registry
 	c.registry = NewTestRegistry(c)
 	c.registry.LoadTests(ctx, c.Config.Tests, c.Config.ExternalTests)
@@ -196,11 +229,14 @@ func (c *Coordinator) Run(ctx context.Context) error {
 	// start per epoch GC routine
 	go c.runEpochGC(ctx)
 
+	// start config reload routine
+	go c.runConfigReloader(ctx)
+
 	// start off queue test execution loop
 	go c.runner.RunOffQueueTestExecutionLoop(ctx)
 
 	// run test execution loop for queued tests
-	c.runner.RunTestExecutionLoop(ctx, c.Config.Coordinator.MaxConcurrentTests)
+	c.runner.RunTestExecutionLoop(ctx, c.Config.Coordinator.MaxConcurrentTests, c.Config.Coordinator.ConcurrencyGroups)
 
 	return nil
 }
@@ -225,14 +261,39 @@ func (c *Coordinator) WalletManager() *wallet.Manager {
 	return c.walletManager
 }
 
+func (c *Coordinator) LockManager() *locks.Manager {
+	return c.lockManager
+}
+
+func (c *Coordinator) SecretResolver() *secrets.Resolver {
+	return c.secretResolver
+}
+
 func (c *Coordinator) ValidatorNames() *names.ValidatorNames {
 	return c.validatorNames
 }
 
 func (c *Coordinator) GlobalVariables() types.Variables {
+	c.globalVarsMutex.RLock()
+	defer c.globalVarsMutex.RUnlock()
+
 	return c.globalVars
 }
 
+func (c *Coordinator) newGlobalVariables(config *Config) types.Variables {
+	globalVars := vars.NewVariables(nil)
+	for name, value := range config.GlobalVars {
+		globalVars.SetVar(name, value)
+	}
+
+	for name, value := range config.Secrets {
+		secrets.AddSecretData(value)
+		globalVars.SetVar(name, value)
+	}
+
+	return globalVars
+}
+
 func (c *Coordinator) TestRegistry() types.TestRegistry {
 	return c.registry
 }
@@ -259,6 +320,14 @@ func (c *Coordinator) GetTestQueue() []types.Test {
 	return c.runner.GetTestQueue()
 }
 
+func (c *Coordinator) GetTestQueueEntries() []*types.TestQueueEntry {
+	return c.runner.GetTestQueueEntries()
+}
+
+func (c *Coordinator) UpdateTestQueue(runID uint64, update *types.TestQueueUpdate) error {
+	return c.runner.UpdateTestQueue(runID, update)
+}
+
 func (c *Coordinator) GetTestHistory(testID string, firstRunID, offset, limit uint64) (tests []types.Test, totalTests uint64) {
 	dbTests, totalTests, err := c.database.GetTestRunRange(testID, firstRunID, offset, limit)
 	if err != nil {
@@ -278,6 +347,25 @@ func (c *Coordinator) GetTestHistory(testID string, firstRunID, offset, limit ui
 	return tests, totalTests
 }
 
+func (c *Coordinator) GetTestRunGroup(parentRunID uint64) []types.Test {
+	dbTests, err := c.database.GetTestRunsByParentRunID(parentRunID)
+	if err != nil {
+		return nil
+	}
+
+	tests := make([]types.Test, len(dbTests))
+
+	for idx, dbTest := range dbTests {
+		if testRef := c.runner.GetTestByRunID(dbTest.RunID); testRef != nil {
+			tests[idx] = testRef
+		} else {
+			tests[idx] = test.WrapDBTestRun(c.database, dbTest)
+		}
+	}
+
+	return tests
+}
+
 func (c *Coordinator) DeleteTestRun(runID uint64) error {
 	testRef := c.runner.GetTestByRunID(runID)
 	if testRef != nil {
@@ -427,3 +515,27 @@ func (c *Coordinator) testBlsMath() error {
 
 	return nil
 }
+
+// resolveEndpointSecrets resolves secret references in endpoint urls & headers.
+// Endpoints are connected once, so these references are resolved on startup.
+func (c *Coordinator) resolveEndpointSecrets(ctx context.Context, endpoint *clients.ClientConfig) error {
+	var err error
+
+	if endpoint.ConsensusURL, err = c.secretResolver.Resolve(ctx, endpoint.ConsensusURL); err != nil {
+		return fmt.Errorf("endpoint %v: %w", endpoint.Name, err)
+	}
+
+	if endpoint.ExecutionURL, err = c.secretResolver.Resolve(ctx, endpoint.ExecutionURL); err != nil {
+		return fmt.Errorf("endpoint %v: %w", endpoint.Name, err)
+	}
+
+	for _, headers := range []map[string]string{endpoint.ConsensusHeaders, endpoint.ExecutionHeaders} {
+		for key, value := range headers {
+			if headers[key], err = c.secretResolver.Resolve(ctx, value); err != nil {
+				return fmt.Errorf("endpoint %v: %w", endpoint.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE "test_runs" ADD COLUMN "parent_run_id" INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE "test_runs" ADD COLUMN "matrix_values" TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS "test_runs_parent_run_id_idx" ON "test_runs" ("parent_run_id");
+
+ALTER TABLE "test_configs" ADD COLUMN "matrix_yaml" TEXT NOT NULL DEFAULT '';
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'NOT SUPPORTED';
+-- +goose StatementEnd
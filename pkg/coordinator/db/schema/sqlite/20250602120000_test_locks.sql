@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE "test_configs" ADD COLUMN "locks_yaml" TEXT NOT NULL DEFAULT '';
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'NOT SUPPORTED';
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+
+ALTER TABLE "test_configs" ADD COLUMN "priority" INTEGER NULL;
+ALTER TABLE "test_configs" ADD COLUMN "concurrency_group" TEXT NOT NULL DEFAULT '';
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'NOT SUPPORTED';
+-- +goose StatementEnd
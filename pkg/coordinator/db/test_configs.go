@@ -13,6 +13,10 @@ type TestConfig struct {
 	ConfigVars       string `db:"config_vars"`
 	ScheduleStartup  bool   `db:"schedule_startup"`
 	ScheduleCronYaml string `db:"schedule_cron_yaml"`
+	MatrixYaml       string `db:"matrix_yaml"`
+	LocksYaml        string `db:"locks_yaml"`
+	Priority         *int   `db:"priority"`
+	ConcurrencyGroup string `db:"concurrency_group"`
 }
 
 // InsertTestConfig inserts a test config into the database.
@@ -20,8 +24,9 @@ func (db *Database) InsertTestConfig(tx *sqlx.Tx, config *TestConfig) error {
 	_, err := tx.Exec(db.EngineQuery(map[EngineType]string{
 		EnginePgsql: `
 			INSERT INTO test_configs (
-				test_id, source, name, timeout, config, config_vars, schedule_startup, schedule_cron_yaml
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				test_id, source, name, timeout, config, config_vars, schedule_startup, schedule_cron_yaml, matrix_yaml, locks_yaml,
+				priority, concurrency_group
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 			ON CONFLICT (test_id) DO UPDATE SET
 				source = excluded.source,
 				name = excluded.name,
@@ -29,14 +34,20 @@ func (db *Database) InsertTestConfig(tx *sqlx.Tx, config *TestConfig) error {
 				config = excluded.config,
 				config_vars = excluded.config_vars,
 				schedule_startup = excluded.schedule_startup,
-				schedule_cron_yaml = excluded.schedule_cron_yaml`,
+				schedule_cron_yaml = excluded.schedule_cron_yaml,
+				matrix_yaml = excluded.matrix_yaml,
+				locks_yaml = excluded.locks_yaml,
+				priority = excluded.priority,
+				concurrency_group = excluded.concurrency_group`,
 		EngineSqlite: `
 			INSERT OR REPLACE INTO test_configs (
-				test_id, source, name, timeout, config, config_vars, schedule_startup, schedule_cron_yaml
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+				test_id, source, name, timeout, config, config_vars, schedule_startup, schedule_cron_yaml, matrix_yaml, locks_yaml,
+				priority, concurrency_group
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
 	}),
 		config.TestID, config.Source, config.Name, config.Timeout, config.Config, config.ConfigVars,
-		config.ScheduleStartup, config.ScheduleCronYaml)
+		config.ScheduleStartup, config.ScheduleCronYaml, config.MatrixYaml, config.LocksYaml,
+		config.Priority, config.ConcurrencyGroup)
 	if err != nil {
 		return err
 	}
@@ -17,6 +17,9 @@ type TestRun struct {
 	StopTime  int64  `db:"stop_time"`
 	Timeout   int32  `db:"timeout"`
 	Status    string `db:"status"`
+
+	ParentRunID  uint64 `db:"parent_run_id"`
+	MatrixValues string `db:"matrix_values"`
 }
 
 // InsertTestRun inserts a test run into the database.
@@ -24,8 +27,8 @@ func (db *Database) InsertTestRun(tx *sqlx.Tx, run *TestRun) error {
 	_, err := tx.Exec(db.EngineQuery(map[EngineType]string{
 		EnginePgsql: `
 			INSERT INTO test_runs (
-				run_id, test_id, name, source, config, start_time, stop_time, timeout, status
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				run_id, test_id, name, source, config, start_time, stop_time, timeout, status, parent_run_id, matrix_values
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 			ON CONFLICT (run_id) DO UPDATE SET
 				test_id = excluded.test_id,
 				name = excluded.name,
@@ -33,13 +36,16 @@ func (db *Database) InsertTestRun(tx *sqlx.Tx, run *TestRun) error {
 				start_time = excluded.start_time,
 				stop_time = excluded.stop_time,
 				timeout = excluded.timeout,
-				status = excluded.status`,
+				status = excluded.status,
+				parent_run_id = excluded.parent_run_id,
+				matrix_values = excluded.matrix_values`,
 		EngineSqlite: `
 			INSERT OR REPLACE INTO test_runs (
-				run_id, test_id, name, source, config, start_time, stop_time, timeout, status
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+				run_id, test_id, name, source, config, start_time, stop_time, timeout, status, parent_run_id, matrix_values
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
 	}),
-		run.RunID, run.TestID, run.Name, run.Source, run.Config, run.StartTime, run.StopTime, run.Timeout, run.Status)
+		run.RunID, run.TestID, run.Name, run.Source, run.Config, run.StartTime, run.StopTime, run.Timeout, run.Status,
+		run.ParentRunID, run.MatrixValues)
 	if err != nil {
 		return err
 	}
@@ -85,7 +91,7 @@ func (db *Database) GetTestRunRange(testID string, firstRunID, offset, limit uin
 	fmt.Fprint(&sql, `
 	WITH cte AS (
 		SELECT
-			run_id, test_id, name, source, config, start_time, stop_time, timeout, status
+			run_id, test_id, name, source, config, start_time, stop_time, timeout, status, parent_run_id, matrix_values
 		FROM test_runs
 	`)
 
@@ -113,7 +119,9 @@ func (db *Database) GetTestRunRange(testID string, firstRunID, offset, limit uin
 		0 AS start_time, 
 		0 AS stop_time, 
 		0 AS timeout, 
-		"" AS status
+		"" AS status,
+		0 AS parent_run_id,
+		"" AS matrix_values
 	FROM cte
 	UNION ALL SELECT * FROM (
 	SELECT * FROM cte
@@ -140,6 +148,22 @@ func (db *Database) GetTestRunRange(testID string, firstRunID, offset, limit uin
 	return runs[1:], runs[0].RunID, nil
 }
 
+// GetTestRunsByParentRunID returns all test runs that belong to the matrix group with the given parent run ID.
+func (db *Database) GetTestRunsByParentRunID(parentRunID uint64) ([]*TestRun, error) {
+	var runs []*TestRun
+
+	err := db.reader.Select(&runs, `
+		SELECT * FROM test_runs
+		WHERE parent_run_id = $1
+		ORDER BY run_id ASC`,
+		parentRunID)
+	if err != nil {
+		return nil, err
+	}
+
+	return runs, nil
+}
+
 // DeleteTestRun deletes a test run and all associated task states and logs.
 func (db *Database) DeleteTestRun(tx *sqlx.Tx, runID uint64) error {
 	_, err := tx.Exec(`
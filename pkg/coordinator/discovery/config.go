@@ -0,0 +1,38 @@
+package discovery
+
+import (
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+)
+
+type Config struct {
+	// Interval between two discovery runs
+	Interval helper.Duration `yaml:"interval" json:"interval"`
+
+	// List of discovery providers
+	Providers []*ProviderConfig `yaml:"providers" json:"providers"`
+}
+
+type ProviderConfig struct {
+	// Provider name, used in logs
+	Name string `yaml:"name" json:"name"`
+
+	// Provider type (inventory, dns)
+	Type string `yaml:"type" json:"type"`
+
+	// Inventory url or local file (json or yaml)
+	URL  string `yaml:"url" json:"url"`
+	File string `yaml:"file" json:"file"`
+
+	// Headers for inventory url requests
+	Headers map[string]string `yaml:"headers" json:"headers"`
+
+	// DNS SRV records of the consensus & execution endpoints
+	ConsensusSRV string `yaml:"consensusSrv" json:"consensusSrv"`
+	ExecutionSRV string `yaml:"executionSrv" json:"executionSrv"`
+	Scheme       string `yaml:"scheme" json:"scheme"`
+
+	// Settings applied to all discovered endpoints
+	NamePrefix       string            `yaml:"namePrefix" json:"namePrefix"`
+	ConsensusHeaders map[string]string `yaml:"consensusHeaders" json:"consensusHeaders"`
+	ExecutionHeaders map[string]string `yaml:"executionHeaders" json:"executionHeaders"`
+}
@@ -0,0 +1,97 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+	"github.com/sirupsen/logrus"
+)
+
+// dnsProvider discovers endpoints via DNS SRV records.
+// Consensus and execution records that point to the same host are paired into one endpoint,
+// which is named after the first label of the host name.
+type dnsProvider struct {
+	config   *ProviderConfig
+	logger   logrus.FieldLogger
+	resolver *net.Resolver
+}
+
+func newDNSProvider(config *ProviderConfig, logger logrus.FieldLogger) (Provider, error) {
+	if config.ConsensusSRV == "" || config.ExecutionSRV == "" {
+		return nil, fmt.Errorf("dns discovery provider requires consensusSrv and executionSrv")
+	}
+
+	return &dnsProvider{
+		config:   config,
+		logger:   logger,
+		resolver: net.DefaultResolver,
+	}, nil
+}
+
+func (p *dnsProvider) Name() string {
+	if p.config.Name != "" {
+		return p.config.Name
+	}
+
+	return p.config.ConsensusSRV
+}
+
+func (p *dnsProvider) Discover(ctx context.Context) ([]*clients.ClientConfig, error) {
+	consensusURLs, err := p.lookupSRV(ctx, p.config.ConsensusSRV)
+	if err != nil {
+		return nil, err
+	}
+
+	executionURLs, err := p.lookupSRV(ctx, p.config.ExecutionSRV)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := []*clients.ClientConfig{}
+
+	for host, consensusURL := range consensusURLs {
+		executionURL, found := executionURLs[host]
+		if !found {
+			p.logger.Debugf("no execution srv record for host %v, skipping", host)
+			continue
+		}
+
+		endpoints = append(endpoints, &clients.ClientConfig{
+			Name:             p.config.NamePrefix + strings.Split(host, ".")[0],
+			ConsensusURL:     consensusURL,
+			ConsensusHeaders: mergeHeaders(p.config.ConsensusHeaders, nil),
+			ExecutionURL:     executionURL,
+			ExecutionHeaders: mergeHeaders(p.config.ExecutionHeaders, nil),
+		})
+	}
+
+	return endpoints, nil
+}
+
+func (p *dnsProvider) lookupSRV(ctx context.Context, name string) (map[string]string, error) {
+	_, records, err := p.resolver.LookupSRV(ctx, "", "", name)
+	if err != nil {
+		return nil, fmt.Errorf("srv lookup for %v failed: %w", name, err)
+	}
+
+	scheme := p.config.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	urls := map[string]string{}
+
+	for _, record := range records {
+		host := strings.TrimSuffix(record.Target, ".")
+		if _, exists := urls[host]; exists {
+			continue
+		}
+
+		urls[host] = fmt.Sprintf("%v://%v", scheme, net.JoinHostPort(host, fmt.Sprintf("%d", record.Port)))
+	}
+
+	return urls, nil
+}
@@ -0,0 +1,139 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// inventoryProvider loads endpoints from a json or yaml inventory, served via http or from a local file.
+// The inventory is either a list of endpoints or an object with an `endpoints` list.
+type inventoryProvider struct {
+	config *ProviderConfig
+	logger logrus.FieldLogger
+	client *http.Client
+}
+
+type inventoryResponse struct {
+	Endpoints []*clients.ClientConfig `yaml:"endpoints"`
+}
+
+func newInventoryProvider(config *ProviderConfig, logger logrus.FieldLogger) (Provider, error) {
+	if config.URL == "" && config.File == "" {
+		return nil, fmt.Errorf("inventory discovery provider requires url or file")
+	}
+
+	return &inventoryProvider{
+		config: config,
+		logger: logger,
+		client: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+func (p *inventoryProvider) Name() string {
+	if p.config.Name != "" {
+		return p.config.Name
+	}
+
+	if p.config.File != "" {
+		return p.config.File
+	}
+
+	return p.config.URL
+}
+
+func (p *inventoryProvider) Discover(ctx context.Context) ([]*clients.ClientConfig, error) {
+	var inventoryData []byte
+
+	var err error
+
+	if p.config.File != "" {
+		inventoryData, err = os.ReadFile(p.config.File)
+		if err != nil {
+			return nil, fmt.Errorf("could not read inventory file: %w", err)
+		}
+	} else {
+		inventoryData, err = p.fetchInventory(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	endpoints := []*clients.ClientConfig{}
+
+	// yaml is a superset of json, so this works for both formats
+	if err := yaml.Unmarshal(inventoryData, &endpoints); err != nil {
+		inventory := &inventoryResponse{}
+		if err2 := yaml.Unmarshal(inventoryData, inventory); err2 != nil {
+			return nil, fmt.Errorf("could not parse inventory: %w", err2)
+		}
+
+		endpoints = inventory.Endpoints
+	}
+
+	for _, endpoint := range endpoints {
+		endpoint.Name = p.config.NamePrefix + endpoint.Name
+		endpoint.ConsensusHeaders = mergeHeaders(p.config.ConsensusHeaders, endpoint.ConsensusHeaders)
+		endpoint.ExecutionHeaders = mergeHeaders(p.config.ExecutionHeaders, endpoint.ExecutionHeaders)
+	}
+
+	return endpoints, nil
+}
+
+func (p *inventoryProvider) fetchInventory(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.URL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, value := range p.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch inventory: %w", err)
+	}
+
+	defer func() {
+		if err2 := resp.Body.Close(); err2 != nil {
+			p.logger.WithError(err2).Warn("failed to close response body")
+		}
+	}()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read inventory: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("inventory returned status %v: %s", resp.StatusCode, data)
+	}
+
+	return data, nil
+}
+
+func mergeHeaders(defaults, headers map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return headers
+	}
+
+	merged := make(map[string]string, len(defaults)+len(headers))
+
+	for key, value := range defaults {
+		merged[key] = value
+	}
+
+	for key, value := range headers {
+		merged[key] = value
+	}
+
+	return merged
+}
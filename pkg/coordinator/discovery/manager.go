@@ -0,0 +1,246 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultDiscoveryInterval = 1 * time.Minute
+
+// Manager periodically discovers endpoints and reconciles them with the client pool.
+// Only endpoints that have been added by the manager are updated or removed.
+type Manager struct {
+	config     *Config
+	clientPool *clients.ClientPool
+	logger     logrus.FieldLogger
+	providers  []Provider
+
+	endpointsMutex sync.Mutex
+	endpoints      map[string]*clients.ClientConfig
+}
+
+// NewManager creates the discovery manager with all configured providers.
+// Secret references are only resolved in the headers of the local provider configs, never in discovered endpoints,
+// as these come from external sources that must not be able to read local secrets.
+func NewManager(ctx context.Context, config *Config, clientPool *clients.ClientPool, resolver *secrets.Resolver, logger logrus.FieldLogger) (*Manager, error) {
+	manager := &Manager{
+		config:     config,
+		clientPool: clientPool,
+		logger:     logger,
+		providers:  []Provider{},
+		endpoints:  map[string]*clients.ClientConfig{},
+	}
+
+	for idx, providerConfig := range config.Providers {
+		providerConfig, err := resolveProviderSecrets(ctx, resolver, providerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid discovery provider %v: %w", idx, err)
+		}
+
+		provider, err := newProvider(providerConfig, logger.WithField("provider", providerConfig.Name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid discovery provider %v: %w", idx, err)
+		}
+
+		manager.providers = append(manager.providers, provider)
+	}
+
+	return manager, nil
+}
+
+// Run reconciles the discovered endpoints until the context is cancelled.
+func (m *Manager) Run(ctx context.Context) {
+	defer func() {
+		if err := recover(); err != nil {
+			var err2 error
+			if errval, errok := err.(error); errok {
+				err2 = errval
+			}
+
+			m.logger.WithError(err2).Errorf("uncaught panic in discovery.Manager.Run: %v, stack: %v", err, string(debug.Stack()))
+		}
+	}()
+
+	interval := m.config.Interval.Duration
+	if interval == 0 {
+		interval = defaultDiscoveryInterval
+	}
+
+	for {
+		m.Reconcile(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Reconcile runs all providers once and applies the differences to the client pool.
+// Endpoints of a failing provider are kept until the provider succeeds again.
+func (m *Manager) Reconcile(ctx context.Context) {
+	m.endpointsMutex.Lock()
+	defer m.endpointsMutex.Unlock()
+
+	discovered := map[string]*clients.ClientConfig{}
+	failedProviders := false
+
+	for _, provider := range m.providers {
+		endpoints, err := provider.Discover(ctx)
+		if err != nil {
+			m.logger.Warnf("discovery provider %v failed: %v", provider.Name(), err)
+
+			failedProviders = true
+
+			continue
+		}
+
+		for _, endpoint := range endpoints {
+			if endpoint.Name == "" || endpoint.ConsensusURL == "" || endpoint.ExecutionURL == "" {
+				m.logger.Warnf("discovery provider %v returned incomplete endpoint (name: %v)", provider.Name(), endpoint.Name)
+				continue
+			}
+
+			if discovered[endpoint.Name] != nil {
+				m.logger.Warnf("discovery provider %v returned duplicate endpoint %v", provider.Name(), endpoint.Name)
+				continue
+			}
+
+			discovered[endpoint.Name] = endpoint
+		}
+	}
+
+	summary := []string{}
+
+	// remove vanished endpoints, unless a provider failed and we can't tell whether they are gone
+	if !failedProviders {
+		for name := range m.endpoints {
+			if discovered[name] != nil {
+				continue
+			}
+
+			m.clientPool.RemoveClient(name)
+			delete(m.endpoints, name)
+
+			summary = append(summary, fmt.Sprintf("removed endpoint %v", name))
+		}
+	}
+
+	names := make([]string, 0, len(discovered))
+	for name := range discovered {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		endpoint := discovered[name]
+		oldEndpoint := m.endpoints[name]
+
+		if oldEndpoint != nil && reflect.DeepEqual(oldEndpoint, endpoint) {
+			continue
+		}
+
+		if oldEndpoint == nil && m.clientPool.GetClient(name) != nil {
+			m.logger.Warnf("discovered endpoint %v conflicts with existing endpoint, skipping", name)
+			continue
+		}
+
+		// the pool gets its own copy, so the discovered config can be compared in the next run
+		poolEndpoint := copyClientConfig(endpoint)
+
+		action := "added"
+
+		if oldEndpoint != nil {
+			m.clientPool.RemoveClient(name)
+			delete(m.endpoints, name)
+
+			action = "updated"
+		}
+
+		if err := m.clientPool.AddClient(poolEndpoint); err != nil {
+			m.logger.Errorf("could not add discovered endpoint %v: %v", name, err)
+			continue
+		}
+
+		m.endpoints[name] = endpoint
+
+		summary = append(summary, fmt.Sprintf("%v endpoint %v", action, name))
+	}
+
+	if len(summary) > 0 {
+		m.logger.Infof("endpoint discovery: %v", strings.Join(summary, "; "))
+	}
+}
+
+// GetEndpointNames returns the names of all endpoints managed by the discovery.
+func (m *Manager) GetEndpointNames() []string {
+	m.endpointsMutex.Lock()
+	defer m.endpointsMutex.Unlock()
+
+	names := make([]string, 0, len(m.endpoints))
+	for name := range m.endpoints {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// resolveProviderSecrets returns a copy of the provider config with secret references in the headers resolved.
+func resolveProviderSecrets(ctx context.Context, resolver *secrets.Resolver, config *ProviderConfig) (*ProviderConfig, error) {
+	configCopy := *config
+	if resolver == nil {
+		return &configCopy, nil
+	}
+
+	for _, headers := range []*map[string]string{&configCopy.Headers, &configCopy.ConsensusHeaders, &configCopy.ExecutionHeaders} {
+		resolvedHeaders := copyHeaders(*headers)
+
+		for key, value := range resolvedHeaders {
+			resolvedValue, err := resolver.Resolve(ctx, value)
+			if err != nil {
+				return nil, fmt.Errorf("header %v: %w", key, err)
+			}
+
+			resolvedHeaders[key] = resolvedValue
+		}
+
+		*headers = resolvedHeaders
+	}
+
+	return &configCopy, nil
+}
+
+func copyClientConfig(config *clients.ClientConfig) *clients.ClientConfig {
+	configCopy := *config
+	configCopy.ConsensusHeaders = copyHeaders(config.ConsensusHeaders)
+	configCopy.ExecutionHeaders = copyHeaders(config.ExecutionHeaders)
+
+	return &configCopy
+}
+
+func copyHeaders(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+
+	headersCopy := make(map[string]string, len(headers))
+	for key, value := range headers {
+		headersCopy[key] = value
+	}
+
+	return headersCopy
+}
@@ -0,0 +1,201 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/execution"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
+	"github.com/sirupsen/logrus"
+)
+
+// rotatingProvider returns a different endpoint set on each discovery run.
+type rotatingProvider struct {
+	runs atomic.Uint64
+}
+
+func (p *rotatingProvider) Name() string {
+	return "rotating"
+}
+
+func (p *rotatingProvider) Discover(_ context.Context) ([]*clients.ClientConfig, error) {
+	run := p.runs.Add(1)
+	endpoints := []*clients.ClientConfig{}
+
+	for idx := uint64(0); idx < 4; idx++ {
+		if (run+idx)%3 == 0 {
+			continue
+		}
+
+		endpoints = append(endpoints, &clients.ClientConfig{
+			Name:         fmt.Sprintf("node-%v", idx),
+			ConsensusURL: fmt.Sprintf("http://127.0.0.1:1/cl/%v/%v", idx, run%2),
+			ExecutionURL: fmt.Sprintf("http://127.0.0.1:1/el/%v", idx),
+		})
+	}
+
+	return endpoints, nil
+}
+
+// TestReconcileWithHeadProcessing reconciles endpoints while the pools are read and modified concurrently.
+// Run with `go test -race` to detect unsynchronized access to the client lists.
+func TestReconcileWithHeadProcessing(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clientPool, err := clients.NewClientPoolWithContext(ctx, nil, logger)
+	if err != nil {
+		t.Fatalf("failed creating client pool: %v", err)
+	}
+	defer clientPool.Close()
+
+	if err := clientPool.AddClient(&clients.ClientConfig{
+		Name:         "static",
+		ConsensusURL: "http://127.0.0.1:1/cl",
+		ExecutionURL: "http://127.0.0.1:1/el",
+	}); err != nil {
+		t.Fatalf("failed adding static client: %v", err)
+	}
+
+	manager := &Manager{
+		config:     &Config{},
+		clientPool: clientPool,
+		logger:     logger,
+		providers:  []Provider{&rotatingProvider{}},
+		endpoints:  map[string]*clients.ClientConfig{},
+	}
+
+	readerCtx, readerCancel := context.WithCancel(ctx)
+	waitGroup := sync.WaitGroup{}
+
+	for readerIdx := 0; readerIdx < 4; readerIdx++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for readerCtx.Err() == nil {
+				clientPool.GetConsensusPool().GetHeadForks(-1)
+				clientPool.GetExecutionPool().GetHeadForks(-1)
+				clientPool.GetConsensusPool().GetReadyEndpoint(consensus.AnyClient)
+				clientPool.GetExecutionPool().GetReadyEndpoint(execution.AnyClient)
+
+				// toggling the disabled state resets the head fork caches, so the next head fork lookup iterates the clients again
+				for _, client := range clientPool.GetConsensusPool().GetAllEndpoints() {
+					if !client.IsClosed() {
+						client.SetDisabled(!client.IsDisabled())
+					}
+				}
+
+				for _, client := range clientPool.GetExecutionPool().GetAllEndpoints() {
+					if !client.IsClosed() {
+						client.SetDisabled(!client.IsDisabled())
+					}
+				}
+			}
+		}()
+	}
+
+	for run := 0; run < 200; run++ {
+		manager.Reconcile(ctx)
+	}
+
+	readerCancel()
+	waitGroup.Wait()
+
+	names := map[string]bool{}
+	for _, client := range clientPool.GetAllClients() {
+		if names[client.Config.Name] {
+			t.Errorf("duplicate client %v in pool", client.Config.Name)
+		}
+
+		names[client.Config.Name] = true
+	}
+
+	if !names["static"] {
+		t.Errorf("static client has been removed by discovery")
+	}
+
+	if len(clientPool.GetConsensusPool().GetAllEndpoints()) != len(names) || len(clientPool.GetExecutionPool().GetAllEndpoints()) != len(names) {
+		t.Errorf("pool endpoints out of sync with clients")
+	}
+}
+
+// TestReconcileDoesNotResolveDiscoveredSecrets checks that secret references are only resolved in the local provider config.
+func TestReconcileDoesNotResolveDiscoveredSecrets(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Setenv("ASSERTOOR_DISCOVERY_TEST_SECRET", "local-secret-value")
+
+	inventoryFile := filepath.Join(t.TempDir(), "inventory.yaml")
+	inventoryData := `- name: node-1
+  consensusUrl: env:ASSERTOOR_DISCOVERY_TEST_SECRET
+  executionUrl: http://127.0.0.1:1/el
+  executionHeaders:
+    X-Inventory: env:ASSERTOOR_DISCOVERY_TEST_SECRET
+`
+
+	if err := os.WriteFile(inventoryFile, []byte(inventoryData), 0o600); err != nil {
+		t.Fatalf("failed writing inventory: %v", err)
+	}
+
+	clientPool, err := clients.NewClientPoolWithContext(ctx, nil, logger)
+	if err != nil {
+		t.Fatalf("failed creating client pool: %v", err)
+	}
+	defer clientPool.Close()
+
+	resolver, err := secrets.NewResolver(nil)
+	if err != nil {
+		t.Fatalf("failed creating secret resolver: %v", err)
+	}
+
+	manager, err := NewManager(ctx, &Config{
+		Providers: []*ProviderConfig{
+			{
+				Name: "inventory",
+				Type: "inventory",
+				File: inventoryFile,
+				ConsensusHeaders: map[string]string{
+					"Authorization": "env:ASSERTOOR_DISCOVERY_TEST_SECRET",
+				},
+			},
+		},
+	}, clientPool, resolver, logger)
+	if err != nil {
+		t.Fatalf("failed creating discovery manager: %v", err)
+	}
+
+	manager.Reconcile(ctx)
+
+	client := clientPool.GetClient("node-1")
+	if client == nil {
+		t.Fatalf("discovered endpoint has not been added")
+	}
+
+	if client.Config.ConsensusHeaders["Authorization"] != "local-secret-value" {
+		t.Errorf("secret reference in provider config has not been resolved")
+	}
+
+	if client.Config.ConsensusURL != "env:ASSERTOOR_DISCOVERY_TEST_SECRET" {
+		t.Errorf("secret reference in discovered url has been resolved")
+	}
+
+	if client.Config.ExecutionHeaders["X-Inventory"] != "env:ASSERTOOR_DISCOVERY_TEST_SECRET" {
+		t.Errorf("secret reference in discovered header has been resolved")
+	}
+}
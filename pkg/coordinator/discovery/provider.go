@@ -0,0 +1,47 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+	"github.com/sirupsen/logrus"
+)
+
+// Provider discovers the endpoints of a network from an external source.
+type Provider interface {
+	Name() string
+	Discover(ctx context.Context) ([]*clients.ClientConfig, error)
+}
+
+// ProviderFactory creates a provider from its config.
+type ProviderFactory func(config *ProviderConfig, logger logrus.FieldLogger) (Provider, error)
+
+var (
+	providerTypesMutex sync.RWMutex
+	providerTypes      = map[string]ProviderFactory{
+		"inventory": newInventoryProvider,
+		"dns":       newDNSProvider,
+	}
+)
+
+// RegisterProviderType adds a provider type, that can be used via `type: <name>` in the discovery config.
+func RegisterProviderType(name string, factory ProviderFactory) {
+	providerTypesMutex.Lock()
+	defer providerTypesMutex.Unlock()
+
+	providerTypes[name] = factory
+}
+
+func newProvider(config *ProviderConfig, logger logrus.FieldLogger) (Provider, error) {
+	providerTypesMutex.RLock()
+	factory := providerTypes[config.Type]
+	providerTypesMutex.RUnlock()
+
+	if factory == nil {
+		return nil, fmt.Errorf("unknown discovery provider type: %v", config.Type)
+	}
+
+	return factory(config, logger)
+}
@@ -0,0 +1,206 @@
+package coordinator
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/erigontech/assertoor/pkg/coordinator/vars"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// registerJqFunctions adds chain aware functions to the variable queries.
+// These functions depend on the chain spec & validator set of the consensus client pool.
+func (c *Coordinator) registerJqFunctions() {
+	chainFunctions := []*vars.JqFunction{
+		{Name: "slot_to_epoch", MinArity: 0, MaxArity: 0, Callback: c.jqSlotToEpoch},
+		{Name: "epoch_to_slot", MinArity: 0, MaxArity: 0, Callback: c.jqEpochToSlot},
+		{Name: "slot_to_time", MinArity: 0, MaxArity: 0, Callback: c.jqSlotToTime},
+		{Name: "time_to_slot", MinArity: 0, MaxArity: 0, Callback: c.jqTimeToSlot},
+		{Name: "current_slot", MinArity: 0, MaxArity: 0, Callback: c.jqCurrentSlot},
+		{Name: "current_epoch", MinArity: 0, MaxArity: 0, Callback: c.jqCurrentEpoch},
+		{Name: "validator", MinArity: 0, MaxArity: 0, Callback: c.jqValidator},
+	}
+
+	for _, fn := range chainFunctions {
+		vars.RegisterJqFunction(fn)
+	}
+}
+
+func (c *Coordinator) getJqSlotsPerEpoch() (uint64, error) {
+	specs := c.clientPool.GetConsensusPool().GetBlockCache().GetSpecs()
+	if specs == nil || specs.SlotsPerEpoch == 0 {
+		return 0, fmt.Errorf("chain specs not loaded")
+	}
+
+	return specs.SlotsPerEpoch, nil
+}
+
+func (c *Coordinator) getJqUint64(input any) (uint64, error) {
+	value, err := vars.ParseBigInt(input)
+	if err != nil {
+		return 0, err
+	}
+
+	if !value.IsUint64() {
+		return 0, fmt.Errorf("value out of range: %v", value.String())
+	}
+
+	return value.Uint64(), nil
+}
+
+// jqInt converts the value to a jq number, values above the int range are returned as big int.
+func jqInt(value uint64) any {
+	if value > math.MaxInt {
+		return new(big.Int).SetUint64(value)
+	}
+
+	return int(value) //nolint:gosec // bounds checked above
+}
+
+func (c *Coordinator) jqSlotToEpoch(input any, _ []any) any {
+	slotsPerEpoch, err := c.getJqSlotsPerEpoch()
+	if err != nil {
+		return err
+	}
+
+	slot, err := c.getJqUint64(input)
+	if err != nil {
+		return err
+	}
+
+	return jqInt(slot / slotsPerEpoch)
+}
+
+func (c *Coordinator) jqEpochToSlot(input any, _ []any) any {
+	slotsPerEpoch, err := c.getJqSlotsPerEpoch()
+	if err != nil {
+		return err
+	}
+
+	epoch, err := c.getJqUint64(input)
+	if err != nil {
+		return err
+	}
+
+	if epoch > math.MaxUint64/slotsPerEpoch {
+		return fmt.Errorf("epoch out of range: %v", epoch)
+	}
+
+	return jqInt(epoch * slotsPerEpoch)
+}
+
+func (c *Coordinator) jqSlotToTime(input any, _ []any) any {
+	wallclock := c.clientPool.GetConsensusPool().GetBlockCache().GetWallclock()
+	if wallclock == nil {
+		return fmt.Errorf("wallclock not initialized")
+	}
+
+	slot, err := c.getJqUint64(input)
+	if err != nil {
+		return err
+	}
+
+	slotInfo := wallclock.Slots().FromNumber(slot)
+
+	return int(slotInfo.TimeWindow().Start().Unix())
+}
+
+func (c *Coordinator) jqTimeToSlot(input any, _ []any) any {
+	wallclock := c.clientPool.GetConsensusPool().GetBlockCache().GetWallclock()
+	if wallclock == nil {
+		return fmt.Errorf("wallclock not initialized")
+	}
+
+	timestamp, err := c.getJqUint64(input)
+	if err != nil {
+		return err
+	}
+
+	if timestamp > math.MaxInt64 {
+		return fmt.Errorf("timestamp out of range: %v", timestamp)
+	}
+
+	slot, _, err := wallclock.FromTime(time.Unix(int64(timestamp), 0))
+	if err != nil {
+		return err
+	}
+
+	return jqInt(slot.Number())
+}
+
+func (c *Coordinator) jqCurrentSlot(_ any, _ []any) any {
+	wallclock := c.clientPool.GetConsensusPool().GetBlockCache().GetWallclock()
+	if wallclock == nil {
+		return fmt.Errorf("wallclock not initialized")
+	}
+
+	slot, _, err := wallclock.Now()
+	if err != nil {
+		return err
+	}
+
+	return jqInt(slot.Number())
+}
+
+func (c *Coordinator) jqCurrentEpoch(_ any, _ []any) any {
+	wallclock := c.clientPool.GetConsensusPool().GetBlockCache().GetWallclock()
+	if wallclock == nil {
+		return fmt.Errorf("wallclock not initialized")
+	}
+
+	_, epoch, err := wallclock.Now()
+	if err != nil {
+		return err
+	}
+
+	return jqInt(epoch.Number())
+}
+
+// jqValidator looks up a validator by index or pubkey, returns null if the validator is unknown.
+func (c *Coordinator) jqValidator(input any, _ []any) any {
+	validatorRegistry := c.clientPool.GetConsensusPool().GetValidatorRegistry()
+	if validatorRegistry.GetValidatorCount() == 0 {
+		return fmt.Errorf("validator set not loaded")
+	}
+
+	var validator *v1.Validator
+
+	if pubkeyStr, ok := input.(string); ok && len(pubkeyStr) == 98 && strings.HasPrefix(pubkeyStr, "0x") {
+		pubkey, err := hexutil.Decode(pubkeyStr)
+		if err != nil {
+			return err
+		}
+
+		validator = validatorRegistry.GetValidatorByPubkey(phase0.BLSPubKey(pubkey))
+	} else {
+		index, err := c.getJqUint64(input)
+		if err != nil {
+			return err
+		}
+
+		validator = validatorRegistry.GetValidatorByIndex(phase0.ValidatorIndex(index))
+	}
+
+	if validator == nil {
+		return nil
+	}
+
+	return map[string]any{
+		"index":                  jqInt(uint64(validator.Index)),
+		"name":                   c.validatorNames.GetValidatorName(uint64(validator.Index)),
+		"pubkey":                 validator.Validator.PublicKey.String(),
+		"status":                 validator.Status.String(),
+		"balance":                fmt.Sprintf("%d", validator.Balance),
+		"effective_balance":      fmt.Sprintf("%d", validator.Validator.EffectiveBalance),
+		"withdrawal_credentials": hexutil.Encode(validator.Validator.WithdrawalCredentials),
+		"slashed":                validator.Validator.Slashed,
+		"activation_epoch":       fmt.Sprintf("%d", validator.Validator.ActivationEpoch),
+		"exit_epoch":             fmt.Sprintf("%d", validator.Validator.ExitEpoch),
+		"withdrawable_epoch":     fmt.Sprintf("%d", validator.Validator.WithdrawableEpoch),
+	}
+}
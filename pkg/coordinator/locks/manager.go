@@ -0,0 +1,224 @@
+package locks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LockHolder identifies the owner of a named lock.
+// Locks are owned by test runs, a TaskIndex of 0 means the lock has been declared by the test itself.
+type LockHolder struct {
+	RunID     uint64
+	TaskIndex uint64
+}
+
+type LockInfo struct {
+	Name        string
+	Holder      LockHolder
+	AcquireTime time.Time
+	Waiting     []uint64
+}
+
+type lockEntry struct {
+	holder      LockHolder
+	acquireTime time.Time
+}
+
+type Manager struct {
+	logger       logrus.FieldLogger
+	locksMutex   sync.Mutex
+	locks        map[string]*lockEntry
+	waiting      map[string]map[uint64]bool
+	releaseChan  chan struct{}
+	releaseMutex sync.Mutex
+}
+
+func NewManager(logger logrus.FieldLogger) *Manager {
+	return &Manager{
+		logger:      logger,
+		locks:       map[string]*lockEntry{},
+		waiting:     map[string]map[uint64]bool{},
+		releaseChan: make(chan struct{}),
+	}
+}
+
+// ReleaseNotify returns a channel that gets closed the next time a lock is released.
+func (m *Manager) ReleaseNotify() <-chan struct{} {
+	m.releaseMutex.Lock()
+	defer m.releaseMutex.Unlock()
+
+	return m.releaseChan
+}
+
+func (m *Manager) notifyRelease() {
+	m.releaseMutex.Lock()
+	defer m.releaseMutex.Unlock()
+
+	close(m.releaseChan)
+	m.releaseChan = make(chan struct{})
+}
+
+// TryAcquire acquires all given locks for the holder or none of them.
+// Locks already held by the same test run are considered acquired.
+func (m *Manager) TryAcquire(names []string, holder LockHolder) bool {
+	m.locksMutex.Lock()
+	defer m.locksMutex.Unlock()
+
+	for _, name := range names {
+		if lock := m.locks[name]; lock != nil && lock.holder.RunID != holder.RunID {
+			return false
+		}
+	}
+
+	now := time.Now()
+
+	for _, name := range names {
+		if m.locks[name] != nil {
+			continue
+		}
+
+		m.locks[name] = &lockEntry{
+			holder:      holder,
+			acquireTime: now,
+		}
+
+		m.logger.Debugf("lock '%v' acquired by test run %v", name, holder.RunID)
+	}
+
+	return true
+}
+
+// Acquire waits until all given locks could be acquired for the holder or the context is cancelled.
+func (m *Manager) Acquire(ctx context.Context, names []string, holder LockHolder) error {
+	if m.TryAcquire(names, holder) {
+		return nil
+	}
+
+	m.SetWaiting(names, holder.RunID, true)
+	defer m.SetWaiting(names, holder.RunID, false)
+
+	for {
+		releaseChan := m.ReleaseNotify()
+
+		if m.TryAcquire(names, holder) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-releaseChan:
+		}
+	}
+}
+
+// SetWaiting marks a test run as waiting for the given locks, so it shows up in the lock overview.
+func (m *Manager) SetWaiting(names []string, runID uint64, waiting bool) {
+	m.locksMutex.Lock()
+	defer m.locksMutex.Unlock()
+
+	for _, name := range names {
+		if waiting {
+			if m.waiting[name] == nil {
+				m.waiting[name] = map[uint64]bool{}
+			}
+
+			m.waiting[name][runID] = true
+		} else if m.waiting[name] != nil {
+			delete(m.waiting[name], runID)
+
+			if len(m.waiting[name]) == 0 {
+				delete(m.waiting, name)
+			}
+		}
+	}
+}
+
+// GetBlockingLocks returns the subset of the given locks that is currently held by other test runs.
+func (m *Manager) GetBlockingLocks(names []string, runID uint64) []*LockInfo {
+	m.locksMutex.Lock()
+	defer m.locksMutex.Unlock()
+
+	blocking := []*LockInfo{}
+
+	for _, name := range names {
+		if lock := m.locks[name]; lock != nil && lock.holder.RunID != runID {
+			blocking = append(blocking, &LockInfo{
+				Name:        name,
+				Holder:      lock.holder,
+				AcquireTime: lock.acquireTime,
+			})
+		}
+	}
+
+	return blocking
+}
+
+// ReleaseAll releases all locks held by the given test run.
+func (m *Manager) ReleaseAll(runID uint64) {
+	released := 0
+
+	m.locksMutex.Lock()
+	for name, lock := range m.locks {
+		if lock.holder.RunID == runID {
+			delete(m.locks, name)
+			m.logger.Debugf("lock '%v' released by test run %v", name, runID)
+
+			released++
+		}
+	}
+	m.locksMutex.Unlock()
+
+	if released > 0 {
+		m.notifyRelease()
+	}
+}
+
+// GetLocks returns all currently held or awaited locks sorted by name.
+func (m *Manager) GetLocks() []*LockInfo {
+	m.locksMutex.Lock()
+	defer m.locksMutex.Unlock()
+
+	lockMap := map[string]*LockInfo{}
+
+	for name, lock := range m.locks {
+		lockMap[name] = &LockInfo{
+			Name:        name,
+			Holder:      lock.holder,
+			AcquireTime: lock.acquireTime,
+		}
+	}
+
+	for name, runIDs := range m.waiting {
+		info := lockMap[name]
+		if info == nil {
+			info = &LockInfo{
+				Name: name,
+			}
+			lockMap[name] = info
+		}
+
+		for runID := range runIDs {
+			info.Waiting = append(info.Waiting, runID)
+		}
+
+		sort.Slice(info.Waiting, func(i, j int) bool {
+			return info.Waiting[i] < info.Waiting[j]
+		})
+	}
+
+	lockList := make([]*LockInfo, 0, len(lockMap))
+	for _, info := range lockMap {
+		lockList = append(lockList, info)
+	}
+
+	sort.Slice(lockList, func(i, j int) bool {
+		return lockList[i].Name < lockList[j].Name
+	})
+
+	return lockList
+}
@@ -46,6 +46,7 @@ func NewLogger(options *ScopeOptions) *LogScope {
 
 	logger.logger.SetOutput(io.Discard)
 	logger.logger.SetLevel(logrus.DebugLevel)
+	logger.logger.AddHook(&logRedactor{})
 
 	if options.Parent != nil {
 		tmpEntry := options.Parent.WithFields(logrus.Fields{})
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"fmt"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
+	"github.com/sirupsen/logrus"
+)
+
+// logRedactor masks secret values in log messages and fields before they get forwarded or persisted.
+type logRedactor struct{}
+
+func (lr *logRedactor) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (lr *logRedactor) Fire(entry *logrus.Entry) error {
+	entry.Message = secrets.Redact(entry.Message)
+
+	for key, value := range entry.Data {
+		switch fieldValue := value.(type) {
+		case string:
+			entry.Data[key] = secrets.Redact(fieldValue)
+		case error:
+			if redacted := secrets.Redact(fieldValue.Error()); redacted != fieldValue.Error() {
+				entry.Data[key] = redacted
+			}
+		case fmt.Stringer:
+			if str := fieldValue.String(); secrets.Redact(str) != str {
+				entry.Data[key] = secrets.Redact(str)
+			}
+		default:
+			entry.Data[key] = secrets.RedactData(value)
+		}
+	}
+
+	return nil
+}
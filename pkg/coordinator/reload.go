@@ -0,0 +1,368 @@
+package coordinator
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+)
+
+const configReloadInterval = 10 * time.Second
+
+// runConfigReloader reloads the configuration when the config file changes or on SIGHUP.
+func (c *Coordinator) runConfigReloader(ctx context.Context) {
+	defer func() {
+		if err := recover(); err != nil {
+			var err2 error
+			if errval, errok := err.(error); errok {
+				err2 = errval
+			}
+
+			c.log.GetLogger().WithError(err2).Errorf("uncaught panic in coordinator.runConfigReloader: %v, stack: %v", err, string(debug.Stack()))
+		}
+	}()
+
+	if c.configPath == "" {
+		return
+	}
+
+	sighupChan := make(chan os.Signal, 1)
+	signal.Notify(sighupChan, syscall.SIGHUP)
+
+	defer signal.Stop(sighupChan)
+
+	lastConfigHash := c.getConfigFileHash()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sighupChan:
+			c.log.GetLogger().Info("received SIGHUP, reloading config")
+		case <-time.After(configReloadInterval):
+			c.applyPendingEndpoints()
+
+			configHash := c.getConfigFileHash()
+			if configHash == "" || configHash == lastConfigHash {
+				continue
+			}
+
+			c.log.GetLogger().Info("config file changed, reloading config")
+		}
+
+		lastConfigHash = c.getConfigFileHash()
+
+		if err := c.ReloadConfig(ctx); err != nil {
+			c.log.GetLogger().Errorf("failed reloading config: %v", err)
+		}
+	}
+}
+
+func (c *Coordinator) getConfigFileHash() string {
+	configData, err := os.ReadFile(c.configPath)
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(configData))
+}
+
+// ReloadConfig loads the config file and applies changes to endpoints, global variables and tests.
+// Running tests are not interrupted: they keep using the variables they were started with, and endpoints are only
+// removed or reconnected once no test is running.
+func (c *Coordinator) ReloadConfig(ctx context.Context) error {
+	c.reloadMutex.Lock()
+	defer c.reloadMutex.Unlock()
+
+	newConfig, err := NewConfig(c.configPath)
+	if err != nil {
+		return fmt.Errorf("could not load config: %w", err)
+	}
+
+	for idx := range newConfig.Endpoints {
+		err = c.resolveEndpointSecrets(ctx, &newConfig.Endpoints[idx])
+		if err != nil {
+			return err
+		}
+	}
+
+	summary := []string{}
+
+	// sync endpoints
+	summary = append(summary, c.reloadEndpoints(newConfig.Endpoints)...)
+
+	// sync global variables
+	if changedVars := c.getChangedGlobalVars(newConfig); len(changedVars) > 0 {
+		globalVars := c.newGlobalVariables(newConfig)
+
+		c.globalVarsMutex.Lock()
+		c.globalVars = globalVars
+		c.globalVarsMutex.Unlock()
+
+		summary = append(summary, fmt.Sprintf("global variables changed: %v", strings.Join(changedVars, ", ")))
+	}
+
+	c.Config.GlobalVars = newConfig.GlobalVars
+	c.Config.Secrets = newConfig.Secrets
+
+	// sync tests
+	summary = append(summary, c.reloadTests(ctx, newConfig)...)
+	c.Config.Tests = newConfig.Tests
+	c.Config.ExternalTests = newConfig.ExternalTests
+
+	// sections that can not be applied at runtime
+	restartSections := []string{}
+
+	if newConfig.Database != nil && !reflect.DeepEqual(newConfig.Database, c.Config.Database) {
+		restartSections = append(restartSections, "database")
+	}
+
+	if !reflect.DeepEqual(newConfig.Web, c.Config.Web) {
+		restartSections = append(restartSections, "web")
+	}
+
+	if !reflect.DeepEqual(newConfig.Coordinator, c.Config.Coordinator) {
+		restartSections = append(restartSections, "coordinator")
+	}
+
+	if !reflect.DeepEqual(newConfig.ValidatorNames, c.Config.ValidatorNames) {
+		restartSections = append(restartSections, "validatorNames")
+	}
+
+	if !reflect.DeepEqual(newConfig.ClientPool, c.Config.ClientPool) {
+		restartSections = append(restartSections, "clientPool")
+	}
+
+	if !reflect.DeepEqual(newConfig.Discovery, c.Config.Discovery) {
+		restartSections = append(restartSections, "discovery")
+	}
+
+	if !reflect.DeepEqual(newConfig.SecretProviders, c.Config.SecretProviders) {
+		restartSections = append(restartSections, "secretProviders")
+	}
+
+	if len(restartSections) > 0 {
+		c.log.GetLogger().Warnf("config reload: changes to %v require a restart", strings.Join(restartSections, ", "))
+	}
+
+	if len(summary) == 0 {
+		c.log.GetLogger().Info("config reloaded, no changes")
+	} else {
+		c.log.GetLogger().Infof("config reloaded: %v", strings.Join(summary, "; "))
+	}
+
+	return nil
+}
+
+// applyPendingEndpoints applies endpoint changes that were deferred because tests were running during the last reload.
+func (c *Coordinator) applyPendingEndpoints() {
+	c.reloadMutex.Lock()
+	defer c.reloadMutex.Unlock()
+
+	if c.pendingEndpoints == nil || c.runner.HasRunningTests() {
+		return
+	}
+
+	if summary := c.reloadEndpoints(c.pendingEndpoints); len(summary) > 0 {
+		c.log.GetLogger().Infof("applied deferred endpoint changes: %v", strings.Join(summary, "; "))
+	}
+}
+
+// reloadEndpoints applies the differences between the endpoints of the current and the new config.
+// Only endpoints from the current config are removed or reconnected, and only while no test is running,
+// as a running test may use them. Otherwise these changes are deferred until all tests are finished.
+// Endpoints that were added via api or discovery are never touched, new endpoints with a conflicting name are skipped.
+func (c *Coordinator) reloadEndpoints(newEndpoints []clients.ClientConfig) []string {
+	summary := []string{}
+	isDeferred := false
+	hasRunningTests := c.runner.HasRunningTests()
+	oldEndpoints := c.Config.Endpoints
+	appliedEndpoints := []clients.ClientConfig{}
+	oldEndpointMap := map[string]*clients.ClientConfig{}
+	newEndpointMap := map[string]*clients.ClientConfig{}
+
+	for idx := range oldEndpoints {
+		oldEndpointMap[oldEndpoints[idx].Name] = &oldEndpoints[idx]
+	}
+
+	for idx := range newEndpoints {
+		newEndpointMap[newEndpoints[idx].Name] = &newEndpoints[idx]
+	}
+
+	for idx := range oldEndpoints {
+		name := oldEndpoints[idx].Name
+		if newEndpointMap[name] != nil {
+			continue
+		}
+
+		if hasRunningTests {
+			appliedEndpoints = append(appliedEndpoints, oldEndpoints[idx])
+			isDeferred = true
+
+			continue
+		}
+
+		if c.clientPool.RemoveClient(name) {
+			summary = append(summary, fmt.Sprintf("removed endpoint %v", name))
+		}
+	}
+
+	for idx := range newEndpoints {
+		name := newEndpoints[idx].Name
+		oldEndpoint := oldEndpointMap[name]
+
+		switch {
+		case oldEndpoint != nil && reflect.DeepEqual(oldEndpoint, &newEndpoints[idx]):
+			appliedEndpoints = append(appliedEndpoints, newEndpoints[idx])
+			continue
+		case oldEndpoint != nil && hasRunningTests:
+			appliedEndpoints = append(appliedEndpoints, *oldEndpoint)
+			isDeferred = true
+
+			continue
+		case oldEndpoint == nil && c.clientPool.GetClient(name) != nil:
+			c.log.GetLogger().Errorf("could not add endpoint %v: a client with this name has been added via api or discovery", name)
+			continue
+		}
+
+		action := "added"
+		if oldEndpoint != nil && c.clientPool.RemoveClient(name) {
+			action = "updated"
+		}
+
+		if err := c.clientPool.AddClient(&newEndpoints[idx]); err != nil {
+			c.log.GetLogger().Errorf("could not add endpoint %v: %v", name, err)
+			continue
+		}
+
+		appliedEndpoints = append(appliedEndpoints, newEndpoints[idx])
+		summary = append(summary, fmt.Sprintf("%v endpoint %v", action, name))
+	}
+
+	c.Config.Endpoints = appliedEndpoints
+	c.pendingEndpoints = nil
+
+	if isDeferred {
+		c.pendingEndpoints = newEndpoints
+
+		summary = append(summary, "removing & updating endpoints deferred until running tests are finished")
+	}
+
+	return summary
+}
+
+func (c *Coordinator) getChangedGlobalVars(newConfig *Config) []string {
+	oldVars := map[string]interface{}{}
+	newVars := map[string]interface{}{}
+
+	for name, value := range c.Config.GlobalVars {
+		oldVars[name] = value
+	}
+
+	for name, value := range c.Config.Secrets {
+		oldVars[name] = value
+	}
+
+	for name, value := range newConfig.GlobalVars {
+		newVars[name] = value
+	}
+
+	for name, value := range newConfig.Secrets {
+		newVars[name] = value
+	}
+
+	changedVars := []string{}
+
+	for name, value := range newVars {
+		oldValue, found := oldVars[name]
+		if !found || !reflect.DeepEqual(oldValue, value) {
+			changedVars = append(changedVars, name)
+		}
+	}
+
+	for name := range oldVars {
+		if _, found := newVars[name]; !found {
+			changedVars = append(changedVars, name)
+		}
+	}
+
+	sort.Strings(changedVars)
+
+	return changedVars
+}
+
+func (c *Coordinator) reloadTests(ctx context.Context, newConfig *Config) []string {
+	summary := []string{}
+	oldTestIDs := map[string]bool{}
+
+	for _, descriptor := range c.registry.GetTestDescriptors() {
+		oldTestIDs[descriptor.ID()] = true
+	}
+
+	// remove external tests that have been removed from the config
+	for _, oldExtTest := range c.Config.ExternalTests {
+		found := false
+
+		for _, newExtTest := range newConfig.ExternalTests {
+			if (oldExtTest.ID != "" && oldExtTest.ID == newExtTest.ID) || oldExtTest.File == newExtTest.File {
+				found = true
+				break
+			}
+		}
+
+		if found {
+			continue
+		}
+
+		for _, descriptor := range c.registry.GetTestDescriptors() {
+			if descriptor.ID() != oldExtTest.ID && descriptor.Source() != fmt.Sprintf("external:%v", oldExtTest.File) {
+				continue
+			}
+
+			if err := c.registry.DeleteTest(descriptor.ID()); err != nil {
+				c.log.GetLogger().Errorf("could not remove test %v: %v", descriptor.ID(), err)
+			}
+		}
+	}
+
+	c.registry.LoadTests(ctx, newConfig.Tests, newConfig.ExternalTests)
+
+	newTestIDs := map[string]bool{}
+	addedTests := []string{}
+	removedTests := []string{}
+
+	for _, descriptor := range c.registry.GetTestDescriptors() {
+		newTestIDs[descriptor.ID()] = true
+
+		if !oldTestIDs[descriptor.ID()] {
+			addedTests = append(addedTests, descriptor.ID())
+		}
+	}
+
+	for testID := range oldTestIDs {
+		if !newTestIDs[testID] {
+			removedTests = append(removedTests, testID)
+		}
+	}
+
+	sort.Strings(removedTests)
+
+	if len(addedTests) > 0 {
+		summary = append(summary, fmt.Sprintf("added tests: %v", strings.Join(addedTests, ", ")))
+	}
+
+	if len(removedTests) > 0 {
+		summary = append(summary, fmt.Sprintf("removed tests: %v", strings.Join(removedTests, ", ")))
+	}
+
+	return summary
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+)
+
+type pendingApproval struct {
+	request    *types.TaskApprovalRequest
+	resultChan chan *types.TaskApproval
+}
+
+// RequestApproval registers a pending approval request for a task.
+// The returned channel receives the decision once it has been submitted via SubmitApproval.
+func (ts *TaskScheduler) RequestApproval(request *types.TaskApprovalRequest) <-chan *types.TaskApproval {
+	ts.approvalMutex.Lock()
+	defer ts.approvalMutex.Unlock()
+
+	approval := &pendingApproval{
+		request:    request,
+		resultChan: make(chan *types.TaskApproval, 1),
+	}
+	ts.pendingApprovals[request.TaskIndex] = approval
+
+	return approval.resultChan
+}
+
+// CancelApproval removes the pending approval request of a task.
+func (ts *TaskScheduler) CancelApproval(taskIndex types.TaskIndex) {
+	ts.approvalMutex.Lock()
+	defer ts.approvalMutex.Unlock()
+
+	delete(ts.pendingApprovals, taskIndex)
+}
+
+func (ts *TaskScheduler) GetPendingApprovals() []*types.TaskApprovalRequest {
+	ts.approvalMutex.Lock()
+	defer ts.approvalMutex.Unlock()
+
+	requests := make([]*types.TaskApprovalRequest, 0, len(ts.pendingApprovals))
+	for _, approval := range ts.pendingApprovals {
+		requests = append(requests, approval.request)
+	}
+
+	sort.Slice(requests, func(a, b int) bool {
+		return requests[a].TaskIndex < requests[b].TaskIndex
+	})
+
+	return requests
+}
+
+// SubmitApproval approves or rejects the pending approval request of a task.
+func (ts *TaskScheduler) SubmitApproval(taskIndex types.TaskIndex, approval *types.TaskApproval) error {
+	ts.approvalMutex.Lock()
+	defer ts.approvalMutex.Unlock()
+
+	pending := ts.pendingApprovals[taskIndex]
+	if pending == nil {
+		return fmt.Errorf("no pending approval for task %v", taskIndex)
+	}
+
+	if approval.Approved {
+		for _, inputName := range pending.request.RequiredInputs {
+			if _, ok := approval.Inputs[inputName]; !ok {
+				return fmt.Errorf("missing required input: %v", inputName)
+			}
+		}
+	}
+
+	pending.resultChan <- approval
+
+	delete(ts.pendingApprovals, taskIndex)
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+)
+
+type taskDebugger struct {
+	mutex       sync.Mutex
+	paused      bool
+	breakpoints map[types.TaskBreakpoint]bool
+	pausedTasks map[types.TaskIndex]chan bool
+}
+
+func newTaskDebugger() *taskDebugger {
+	return &taskDebugger{
+		breakpoints: map[types.TaskBreakpoint]bool{},
+		pausedTasks: map[types.TaskIndex]chan bool{},
+	}
+}
+
+// waitForDebugger blocks before a task gets executed if the scheduler is paused or a breakpoint matches the task.
+// It returns when the task gets released via ResumeTasks / StepTask or the context is cancelled.
+func (ts *TaskScheduler) waitForDebugger(ctx context.Context, taskState *taskState) error {
+	debugger := ts.debugger
+
+	debugger.mutex.Lock()
+
+	isPaused := debugger.paused
+	if !isPaused && debugger.breakpoints[types.TaskBreakpoint{Index: taskState.index}] {
+		isPaused = true
+	}
+
+	if !isPaused && taskState.options.ID != "" && debugger.breakpoints[types.TaskBreakpoint{ID: taskState.options.ID}] {
+		isPaused = true
+	}
+
+	if !isPaused {
+		debugger.mutex.Unlock()
+		return nil
+	}
+
+	// pause all following tasks too, so the test does not continue while inspecting the paused task
+	debugger.paused = true
+	releaseChan := make(chan bool)
+	debugger.pausedTasks[taskState.index] = releaseChan
+
+	debugger.mutex.Unlock()
+
+	taskState.logger.GetLogger().Infof("test paused before task execution")
+	taskState.taskStatusVars.SetVar("paused", true)
+
+	defer taskState.taskStatusVars.SetVar("paused", false)
+
+	select {
+	case <-ctx.Done():
+		debugger.mutex.Lock()
+		delete(debugger.pausedTasks, taskState.index)
+		debugger.mutex.Unlock()
+
+		return ctx.Err()
+	case <-releaseChan:
+		taskState.logger.GetLogger().Infof("test resumed")
+	}
+
+	return nil
+}
+
+func (ts *TaskScheduler) GetDebugState() *types.TaskDebugState {
+	debugger := ts.debugger
+
+	debugger.mutex.Lock()
+	defer debugger.mutex.Unlock()
+
+	state := &types.TaskDebugState{
+		Paused:      debugger.paused,
+		PausedTasks: make([]types.TaskIndex, 0, len(debugger.pausedTasks)),
+		Breakpoints: make([]types.TaskBreakpoint, 0, len(debugger.breakpoints)),
+	}
+
+	for taskIndex := range debugger.pausedTasks {
+		state.PausedTasks = append(state.PausedTasks, taskIndex)
+	}
+
+	sort.Slice(state.PausedTasks, func(a, b int) bool {
+		return state.PausedTasks[a] < state.PausedTasks[b]
+	})
+
+	for breakpoint := range debugger.breakpoints {
+		state.Breakpoints = append(state.Breakpoints, breakpoint)
+	}
+
+	sort.Slice(state.Breakpoints, func(a, b int) bool {
+		if state.Breakpoints[a].Index != state.Breakpoints[b].Index {
+			return state.Breakpoints[a].Index < state.Breakpoints[b].Index
+		}
+
+		return state.Breakpoints[a].ID < state.Breakpoints[b].ID
+	})
+
+	return state
+}
+
+// PauseTasks pauses the test before the next task gets executed.
+// Tasks that are already running are not interrupted.
+func (ts *TaskScheduler) PauseTasks() {
+	ts.debugger.mutex.Lock()
+	defer ts.debugger.mutex.Unlock()
+
+	ts.debugger.paused = true
+}
+
+// ResumeTasks releases all paused tasks and continues the test until the next breakpoint.
+func (ts *TaskScheduler) ResumeTasks() {
+	ts.debugger.mutex.Lock()
+	defer ts.debugger.mutex.Unlock()
+
+	ts.debugger.paused = false
+
+	for taskIndex, releaseChan := range ts.debugger.pausedTasks {
+		close(releaseChan)
+		delete(ts.debugger.pausedTasks, taskIndex)
+	}
+}
+
+// StepTask releases the paused task with the lowest index and pauses again before the next task.
+func (ts *TaskScheduler) StepTask() {
+	ts.debugger.mutex.Lock()
+	defer ts.debugger.mutex.Unlock()
+
+	ts.debugger.paused = true
+
+	var nextTask types.TaskIndex
+
+	for taskIndex := range ts.debugger.pausedTasks {
+		if nextTask == 0 || taskIndex < nextTask {
+			nextTask = taskIndex
+		}
+	}
+
+	if nextTask != 0 {
+		close(ts.debugger.pausedTasks[nextTask])
+		delete(ts.debugger.pausedTasks, nextTask)
+	}
+}
+
+func (ts *TaskScheduler) SetBreakpoint(breakpoint types.TaskBreakpoint, enabled bool) {
+	ts.debugger.mutex.Lock()
+	defer ts.debugger.mutex.Unlock()
+
+	if enabled {
+		ts.debugger.breakpoints[breakpoint] = true
+	} else {
+		delete(ts.debugger.breakpoints, breakpoint)
+	}
+}
+
+// GetTaskVars returns the full variable scope of a task, including all parent scopes.
+func (ts *TaskScheduler) GetTaskVars(taskIndex types.TaskIndex) (map[string]any, error) {
+	taskState := ts.getTaskState(taskIndex)
+	if taskState == nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	return taskState.taskVars.GetVarsMap(nil, false), nil
+}
+
+// SetTaskVars sets variables in the scope of a task that has not been started yet (e.g. paused at a breakpoint).
+func (ts *TaskScheduler) SetTaskVars(taskIndex types.TaskIndex, variables map[string]any) error {
+	taskState := ts.getTaskState(taskIndex)
+	if taskState == nil {
+		return fmt.Errorf("task not found")
+	}
+
+	// tasks read their variable scope while running, so only tasks waiting for execution can be modified
+	if taskState.isStarted {
+		return fmt.Errorf("task has already been started")
+	}
+
+	for name, value := range variables {
+		taskState.taskVars.SetVar(name, value)
+	}
+
+	return nil
+}
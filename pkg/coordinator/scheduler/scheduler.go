@@ -24,6 +24,9 @@ type TaskScheduler struct {
 	taskStateMap     map[types.TaskIndex]*taskState
 	cancelTaskCtx    context.CancelFunc
 	cancelCleanupCtx context.CancelFunc
+	debugger         *taskDebugger
+	approvalMutex    sync.Mutex
+	pendingApprovals map[types.TaskIndex]*pendingApproval
 }
 
 func NewTaskScheduler(log logrus.FieldLogger, services types.TaskServices, variables types.Variables, testRunID uint64) *TaskScheduler {
@@ -36,6 +39,9 @@ func NewTaskScheduler(log logrus.FieldLogger, services types.TaskServices, varia
 		allTasks:     make([]types.TaskIndex, 0),
 		taskStateMap: make(map[types.TaskIndex]*taskState),
 		services:     services,
+		debugger:     newTaskDebugger(),
+
+		pendingApprovals: map[types.TaskIndex]*pendingApproval{},
 	}
 }
 
@@ -113,6 +119,9 @@ func (ts *TaskScheduler) runCleanupTasks(ctx context.Context) {
 }
 
 func (ts *TaskScheduler) CancelTasks(cancelCleanup bool) {
+	// release paused tasks, so cleanup tasks do not wait for the debugger
+	ts.ResumeTasks()
+
 	if ts.cancelTaskCtx != nil {
 		ts.cancelTaskCtx()
 
@@ -3,7 +3,9 @@ package scheduler
 import (
 	"github.com/erigontech/assertoor/pkg/coordinator/clients"
 	"github.com/erigontech/assertoor/pkg/coordinator/db"
+	"github.com/erigontech/assertoor/pkg/coordinator/locks"
 	"github.com/erigontech/assertoor/pkg/coordinator/names"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/erigontech/assertoor/pkg/coordinator/wallet"
 )
@@ -12,14 +14,18 @@ type servicesProvider struct {
 	database       *db.Database
 	clientPool     *clients.ClientPool
 	walletManager  *wallet.Manager
+	lockManager    *locks.Manager
+	secretResolver *secrets.Resolver
 	validatorNames *names.ValidatorNames
 }
 
-func NewServicesProvider(database *db.Database, clientPool *clients.ClientPool, walletManager *wallet.Manager, validatorNames *names.ValidatorNames) types.TaskServices {
+func NewServicesProvider(database *db.Database, clientPool *clients.ClientPool, walletManager *wallet.Manager, lockManager *locks.Manager, secretResolver *secrets.Resolver, validatorNames *names.ValidatorNames) types.TaskServices {
 	return &servicesProvider{
 		database:       database,
 		clientPool:     clientPool,
 		walletManager:  walletManager,
+		lockManager:    lockManager,
+		secretResolver: secretResolver,
 		validatorNames: validatorNames,
 	}
 }
@@ -36,6 +42,14 @@ func (p *servicesProvider) WalletManager() *wallet.Manager {
 	return p.walletManager
 }
 
+func (p *servicesProvider) LockManager() *locks.Manager {
+	return p.lockManager
+}
+
+func (p *servicesProvider) SecretResolver() *secrets.Resolver {
+	return p.secretResolver
+}
+
 func (p *servicesProvider) ValidatorNames() *names.ValidatorNames {
 	return p.validatorNames
 }
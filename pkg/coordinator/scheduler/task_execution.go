@@ -24,6 +24,11 @@ func (ts *TaskScheduler) ExecuteTask(ctx context.Context, taskIndex types.TaskIn
 		return fmt.Errorf("task has already been executed")
 	}
 
+	// wait for debugger if paused or a breakpoint matches this task
+	if err := ts.waitForDebugger(ctx, taskState); err != nil {
+		return err
+	}
+
 	taskState.isStarted = true
 	taskState.startTime = time.Now()
 	taskState.isRunning = true
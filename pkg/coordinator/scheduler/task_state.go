@@ -7,6 +7,7 @@ import (
 
 	"github.com/erigontech/assertoor/pkg/coordinator/db"
 	"github.com/erigontech/assertoor/pkg/coordinator/logger"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/tasks"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/erigontech/assertoor/pkg/coordinator/vars"
@@ -189,15 +190,16 @@ func (ts *taskState) updateTaskState() error {
 		changedFields = append(changedFields, "stop_time")
 	}
 
-	taskStatusVars := ts.taskStatusVars.GetVarsMap(nil, false)
+	// secret values are masked before persisting config & status vars
+	taskStatusVars := secrets.RedactData(ts.taskStatusVars.GetVarsMap(nil, false))
 
 	configVarsYaml, err := yaml.Marshal(ts.Config())
 	if err != nil {
 		return err
 	}
 
-	if string(configVarsYaml) != ts.dbTaskState.TaskConfig {
-		ts.dbTaskState.TaskConfig = string(configVarsYaml)
+	if configYaml := secrets.Redact(string(configVarsYaml)); configYaml != ts.dbTaskState.TaskConfig {
+		ts.dbTaskState.TaskConfig = configYaml
 
 		changedFields = append(changedFields, "task_config")
 	}
@@ -219,8 +221,8 @@ func (ts *taskState) updateTaskState() error {
 		changedFields = append(changedFields, "task_result")
 	}
 
-	if ts.taskError != nil && ts.taskError.Error() != ts.dbTaskState.TaskError {
-		ts.dbTaskState.TaskError = ts.taskError.Error()
+	if ts.taskError != nil && secrets.Redact(ts.taskError.Error()) != ts.dbTaskState.TaskError {
+		ts.dbTaskState.TaskError = secrets.Redact(ts.taskError.Error())
 
 		changedFields = append(changedFields, "task_error")
 	}
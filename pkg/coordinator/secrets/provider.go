@@ -0,0 +1,69 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+)
+
+// Provider resolves secret references of the form `<provider>:<path>`.
+type Provider interface {
+	GetSecret(ctx context.Context, path string) (string, error)
+}
+
+// ProviderConfig configures an external secret provider, that can be referenced by its name.
+type ProviderConfig struct {
+	Name      string          `yaml:"name" json:"name"`
+	Type      string          `yaml:"type" json:"type"`
+	URL       string          `yaml:"url" json:"url"`
+	Token     string          `yaml:"token" json:"token"`
+	Namespace string          `yaml:"namespace" json:"namespace"`
+	Timeout   helper.Duration `yaml:"timeout" json:"timeout"`
+}
+
+// NewProvider creates a secret provider from the given config.
+func NewProvider(config *ProviderConfig) (Provider, error) {
+	switch config.Type {
+	case "vault", "":
+		if config.URL == "" {
+			return nil, fmt.Errorf("missing url for vault secret provider %v", config.Name)
+		}
+
+		timeout := config.Timeout.Duration
+		if timeout == 0 {
+			timeout = 10 * time.Second
+		}
+
+		return newVaultProvider(config.URL, config.Token, config.Namespace, timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown secret provider type: %v", config.Type)
+	}
+}
+
+// envProvider resolves `env:NAME` references from environment variables.
+type envProvider struct{}
+
+func (p *envProvider) GetSecret(_ context.Context, path string) (string, error) {
+	value, found := os.LookupEnv(path)
+	if !found {
+		return "", fmt.Errorf("environment variable %v not set", path)
+	}
+
+	return value, nil
+}
+
+// fileProvider resolves `file:/path` references from the content of local files.
+type fileProvider struct{}
+
+func (p *fileProvider) GetSecret(_ context.Context, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read secret file: %w", err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
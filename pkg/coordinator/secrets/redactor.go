@@ -0,0 +1,240 @@
+package secrets
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RedactedValue is the placeholder that replaces secret values in logs, database records and api responses.
+const RedactedValue = "<redacted>"
+
+// secrets shorter than this are not redacted, as masking them would garble unrelated output.
+const minSecretLength = 6
+
+// Redactor keeps track of secret values and masks them in strings and generic data structures.
+type Redactor struct {
+	secretsMutex sync.RWMutex
+	secrets      map[string]bool
+	replacer     *strings.Replacer
+}
+
+var defaultRedactor = NewRedactor()
+
+func NewRedactor() *Redactor {
+	return &Redactor{
+		secrets: map[string]bool{},
+	}
+}
+
+// AddSecret registers a secret value. Hex values are registered with and without 0x prefix.
+func (r *Redactor) AddSecret(value string) {
+	value = strings.TrimSpace(value)
+
+	values := []string{value}
+	if strings.HasPrefix(value, "0x") {
+		values = append(values, value[2:])
+	}
+
+	r.secretsMutex.Lock()
+	defer r.secretsMutex.Unlock()
+
+	for _, value := range values {
+		if len(value) < minSecretLength || r.secrets[value] {
+			continue
+		}
+
+		r.secrets[value] = true
+		r.replacer = nil
+	}
+}
+
+// AddSecretData registers all string values within the given data as secrets.
+func (r *Redactor) AddSecretData(data interface{}) {
+	switch value := data.(type) {
+	case string:
+		r.AddSecret(value)
+	case []interface{}:
+		for _, item := range value {
+			r.AddSecretData(item)
+		}
+	case []string:
+		for _, item := range value {
+			r.AddSecret(item)
+		}
+	case map[string]interface{}:
+		for _, item := range value {
+			r.AddSecretData(item)
+		}
+	case map[string]string:
+		for _, item := range value {
+			r.AddSecret(item)
+		}
+	case map[interface{}]interface{}:
+		for _, item := range value {
+			r.AddSecretData(item)
+		}
+	}
+}
+
+// IsSecret returns true if the given value is a registered secret.
+func (r *Redactor) IsSecret(value string) bool {
+	r.secretsMutex.RLock()
+	defer r.secretsMutex.RUnlock()
+
+	return r.secrets[value]
+}
+
+func (r *Redactor) getReplacer() *strings.Replacer {
+	r.secretsMutex.RLock()
+	replacer := r.replacer
+	r.secretsMutex.RUnlock()
+
+	if replacer != nil {
+		return replacer
+	}
+
+	r.secretsMutex.Lock()
+	defer r.secretsMutex.Unlock()
+
+	if r.replacer != nil {
+		return r.replacer
+	}
+
+	if len(r.secrets) == 0 {
+		return nil
+	}
+
+	// replace longer secrets first, so secrets that contain other secrets are masked completely
+	secretList := make([]string, 0, len(r.secrets))
+	for secret := range r.secrets {
+		secretList = append(secretList, secret)
+	}
+
+	sort.Slice(secretList, func(i, j int) bool {
+		if len(secretList[i]) != len(secretList[j]) {
+			return len(secretList[i]) > len(secretList[j])
+		}
+
+		return secretList[i] < secretList[j]
+	})
+
+	replacements := make([]string, 0, len(secretList)*2)
+	for _, secret := range secretList {
+		replacements = append(replacements, secret, RedactedValue)
+	}
+
+	r.replacer = strings.NewReplacer(replacements...)
+
+	return r.replacer
+}
+
+// Redact masks all registered secrets within the given string.
+func (r *Redactor) Redact(str string) string {
+	replacer := r.getReplacer()
+	if replacer == nil {
+		return str
+	}
+
+	return replacer.Replace(str)
+}
+
+// RedactData returns a copy of the given data with all registered secrets masked.
+// Only generic data structures (maps, slices and scalar values) are traversed, other types are returned as is.
+func (r *Redactor) RedactData(data interface{}) interface{} {
+	switch value := data.(type) {
+	case string:
+		return r.Redact(value)
+	case []interface{}:
+		res := make([]interface{}, len(value))
+		for idx, item := range value {
+			res[idx] = r.RedactData(item)
+		}
+
+		return res
+	case []string:
+		res := make([]string, len(value))
+		for idx, item := range value {
+			res[idx] = r.Redact(item)
+		}
+
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			res[key] = r.RedactData(item)
+		}
+
+		return res
+	case map[string]string:
+		res := make(map[string]string, len(value))
+		for key, item := range value {
+			res[key] = r.Redact(item)
+		}
+
+		return res
+	case map[interface{}]interface{}:
+		res := make(map[interface{}]interface{}, len(value))
+		for key, item := range value {
+			res[key] = r.RedactData(item)
+		}
+
+		return res
+	default:
+		return data
+	}
+}
+
+// ContainsRedacted returns true if any string within the given data contains the redaction placeholder.
+// This is used to ignore values that have been sent back unchanged from redacted api responses.
+func ContainsRedacted(data interface{}) bool {
+	switch value := data.(type) {
+	case string:
+		return strings.Contains(value, RedactedValue)
+	case []interface{}:
+		for _, item := range value {
+			if ContainsRedacted(item) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for _, item := range value {
+			if ContainsRedacted(item) {
+				return true
+			}
+		}
+	case map[interface{}]interface{}:
+		for _, item := range value {
+			if ContainsRedacted(item) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// AddSecret registers a secret value with the default redactor.
+func AddSecret(value string) {
+	defaultRedactor.AddSecret(value)
+}
+
+// AddSecretData registers all string values within the given data with the default redactor.
+func AddSecretData(data interface{}) {
+	defaultRedactor.AddSecretData(data)
+}
+
+// IsSecret returns true if the given value is registered with the default redactor.
+func IsSecret(value string) bool {
+	return defaultRedactor.IsSecret(value)
+}
+
+// Redact masks all secrets registered with the default redactor within the given string.
+func Redact(str string) string {
+	return defaultRedactor.Redact(str)
+}
+
+// RedactData returns a copy of the given data with all secrets of the default redactor masked.
+func RedactData(data interface{}) interface{} {
+	return defaultRedactor.RedactData(data)
+}
@@ -0,0 +1,147 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Resolver resolves secret references like `env:NAME`, `file:/path` or `<provider>:<path>`.
+// Resolved values are registered as secrets, so they get masked in logs, database and api responses.
+type Resolver struct {
+	providerMutex sync.RWMutex
+	providers     map[string]Provider
+}
+
+var localProviders = map[string]Provider{
+	"env":  &envProvider{},
+	"file": &fileProvider{},
+}
+
+func NewResolver(providerConfigs []*ProviderConfig) (*Resolver, error) {
+	resolver := &Resolver{
+		providers: map[string]Provider{},
+	}
+
+	for name, provider := range localProviders {
+		resolver.providers[name] = provider
+	}
+
+	for _, providerConfig := range providerConfigs {
+		if providerConfig.Name == "" {
+			return nil, fmt.Errorf("secret provider name must not be empty")
+		}
+
+		provider, err := NewProvider(providerConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		resolver.AddProvider(providerConfig.Name, provider)
+	}
+
+	return resolver, nil
+}
+
+// AddProvider registers a secret provider, that can be referenced via `<name>:<path>`.
+func (r *Resolver) AddProvider(name string, provider Provider) {
+	r.providerMutex.Lock()
+	defer r.providerMutex.Unlock()
+
+	r.providers[name] = provider
+}
+
+func (r *Resolver) getProvider(value string) (provider Provider, path string) {
+	name, path, found := strings.Cut(value, ":")
+	if !found {
+		return nil, ""
+	}
+
+	r.providerMutex.RLock()
+	defer r.providerMutex.RUnlock()
+
+	return r.providers[name], path
+}
+
+// IsReference returns true if the value references a registered secret provider.
+func (r *Resolver) IsReference(value string) bool {
+	provider, _ := r.getProvider(value)
+	return provider != nil
+}
+
+// Resolve returns the secret value for the given reference.
+// Values that are not a reference to a registered provider are returned as is.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	provider, path := r.getProvider(value)
+	if provider == nil {
+		return value, nil
+	}
+
+	secret, err := provider.GetSecret(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("could not resolve secret reference '%v': %w", value, err)
+	}
+
+	AddSecret(secret)
+
+	return secret, nil
+}
+
+// ResolveData returns a copy of the given data with all secret references resolved.
+// The changed flag is false if the data did not contain any reference.
+func (r *Resolver) ResolveData(ctx context.Context, data interface{}) (result interface{}, changed bool, err error) {
+	switch value := data.(type) {
+	case string:
+		if !r.IsReference(value) {
+			return value, false, nil
+		}
+
+		secret, err := r.Resolve(ctx, value)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return secret, true, nil
+	case []interface{}:
+		res := make([]interface{}, len(value))
+
+		for idx, item := range value {
+			resItem, itemChanged, err := r.ResolveData(ctx, item)
+			if err != nil {
+				return nil, false, err
+			}
+
+			res[idx] = resItem
+			changed = changed || itemChanged
+		}
+
+		return res, changed, nil
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(value))
+
+		for key, item := range value {
+			resItem, itemChanged, err := r.ResolveData(ctx, item)
+			if err != nil {
+				return nil, false, err
+			}
+
+			res[key] = resItem
+			changed = changed || itemChanged
+		}
+
+		return res, changed, nil
+	default:
+		return data, false, nil
+	}
+}
+
+// resolveLocalReference resolves `env:` and `file:` references, other values are returned as is.
+func resolveLocalReference(ctx context.Context, value string) (string, error) {
+	name, path, found := strings.Cut(value, ":")
+	if !found || localProviders[name] == nil {
+		return value, nil
+	}
+
+	return localProviders[name].GetSecret(ctx, path)
+}
@@ -0,0 +1,112 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// vaultProvider reads secrets from a HashiCorp Vault compatible HTTP API.
+// References have the form `<name>:<path>#<key>`, e.g. `vault:secret/data/assertoor#privkey`.
+// Both KV v1 and KV v2 responses are supported, the key defaults to `value`.
+type vaultProvider struct {
+	url       string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+func newVaultProvider(url, token, namespace string, timeout time.Duration) *vaultProvider {
+	return &vaultProvider{
+		url:       strings.TrimRight(url, "/"),
+		token:     token,
+		namespace: namespace,
+		client: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+type vaultSecretResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []string               `json:"errors"`
+}
+
+func (p *vaultProvider) GetSecret(ctx context.Context, path string) (string, error) {
+	key := "value"
+
+	if idx := strings.LastIndex(path, "#"); idx >= 0 {
+		key = path[idx+1:]
+		path = path[:idx]
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%v/v1/%v", p.url, strings.TrimLeft(path, "/")), http.NoBody)
+	if err != nil {
+		return "", err
+	}
+
+	if p.token != "" {
+		// the token itself may be a reference to an environment variable or file
+		token, err := resolveLocalReference(ctx, p.token)
+		if err != nil {
+			return "", fmt.Errorf("could not resolve vault token: %w", err)
+		}
+
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	rsp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer rsp.Body.Close()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	secretRsp := &vaultSecretResponse{}
+	if err := json.Unmarshal(body, secretRsp); err != nil {
+		return "", fmt.Errorf("could not decode vault response (status %v): %w", rsp.StatusCode, err)
+	}
+
+	if rsp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %v: %v", rsp.StatusCode, strings.Join(secretRsp.Errors, ", "))
+	}
+
+	secretData := secretRsp.Data
+
+	// kv v2 engines wrap the secret data in another data field
+	if innerData, ok := secretData["data"].(map[string]interface{}); ok {
+		if _, hasMetadata := secretData["metadata"]; hasMetadata {
+			secretData = innerData
+		}
+	}
+
+	value, found := secretData[key]
+	if !found {
+		return "", fmt.Errorf("key %v not found in vault secret %v", key, path)
+	}
+
+	switch value := value.(type) {
+	case string:
+		return value, nil
+	default:
+		valueJSON, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+
+		return string(valueJSON), nil
+	}
+}
@@ -0,0 +1,66 @@
+package secrets
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// SecretTag marks yaml values as secret, e.g. `walletPrivkey: !secret "0x..."`.
+const SecretTag = "!secret"
+
+// UnmarshalYaml decodes the yaml document into target and registers all values tagged with !secret.
+func UnmarshalYaml(data []byte, target interface{}) error {
+	node := &yaml.Node{}
+
+	if err := yaml.Unmarshal(data, node); err != nil {
+		return err
+	}
+
+	return DecodeYamlNode(node, target)
+}
+
+// DecodeYaml reads the next yaml document from the decoder into target and registers all values tagged with !secret.
+func DecodeYaml(decoder *yaml.Decoder, target interface{}) error {
+	node := &yaml.Node{}
+
+	if err := decoder.Decode(node); err != nil {
+		return err
+	}
+
+	return DecodeYamlNode(node, target)
+}
+
+// DecodeYamlNode decodes the yaml node into target and registers all values tagged with !secret.
+func DecodeYamlNode(node *yaml.Node, target interface{}) error {
+	collectYamlSecrets(node, false)
+
+	return node.Decode(target)
+}
+
+func collectYamlSecrets(node *yaml.Node, isSecret bool) {
+	if node.Tag == SecretTag {
+		isSecret = true
+		// drop the custom tag, so the value gets decoded like an untagged one
+		node.Tag = ""
+	}
+
+	if node.Kind == yaml.ScalarNode {
+		if isSecret {
+			// secret scalars are always decoded as strings, so they can be masked
+			node.Tag = "!!str"
+
+			AddSecret(node.Value)
+		}
+
+		return
+	}
+
+	for idx, child := range node.Content {
+		// skip mapping keys, only values are secret
+		if node.Kind == yaml.MappingNode && idx%2 == 0 {
+			collectYamlSecrets(child, false)
+			continue
+		}
+
+		collectYamlSecrets(child, isSecret)
+	}
+}
@@ -0,0 +1,30 @@
+package acquirelock
+
+import (
+	"errors"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+)
+
+type Config struct {
+	Locks       []string        `yaml:"locks" json:"locks"`
+	WaitTimeout helper.Duration `yaml:"waitTimeout" json:"waitTimeout"`
+}
+
+func DefaultConfig() Config {
+	return Config{}
+}
+
+func (c *Config) Validate() error {
+	if len(c.Locks) == 0 {
+		return errors.New("at least one lock name must be specified")
+	}
+
+	for _, lockName := range c.Locks {
+		if lockName == "" {
+			return errors.New("lock names must not be empty")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package acquirelock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/locks"
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	TaskName       = "acquire_lock"
+	TaskDescriptor = &types.TaskDescriptor{
+		Name:        TaskName,
+		Description: "Acquires named locks that are shared across test runs and held until the test run completes.",
+		Config:      DefaultConfig(),
+		NewTask:     NewTask,
+	}
+)
+
+type Task struct {
+	ctx     *types.TaskContext
+	options *types.TaskOptions
+	config  Config
+	logger  logrus.FieldLogger
+}
+
+func NewTask(ctx *types.TaskContext, options *types.TaskOptions) (types.Task, error) {
+	return &Task{
+		ctx:     ctx,
+		options: options,
+		logger:  ctx.Logger.GetLogger(),
+	}, nil
+}
+
+func (t *Task) Config() interface{} {
+	return t.config
+}
+
+func (t *Task) Timeout() time.Duration {
+	return t.options.Timeout.Duration
+}
+
+func (t *Task) LoadConfig() error {
+	config := DefaultConfig()
+
+	// parse static config
+	if t.options.Config != nil {
+		if err := t.options.Config.Unmarshal(&config); err != nil {
+			return fmt.Errorf("error parsing task config for %v: %w", TaskName, err)
+		}
+	}
+
+	// load dynamic vars
+	err := t.ctx.Vars.ConsumeVars(&config, t.options.ConfigVars)
+	if err != nil {
+		return err
+	}
+
+	// validate config
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	t.config = config
+
+	return nil
+}
+
+func (t *Task) Execute(ctx context.Context) error {
+	lockManager := t.ctx.Scheduler.GetServices().LockManager()
+	lockHolder := locks.LockHolder{
+		RunID:     t.ctx.Scheduler.GetTestRunID(),
+		TaskIndex: uint64(t.ctx.Index),
+	}
+	lockNames := strings.Join(t.config.Locks, ", ")
+	startTime := time.Now()
+
+	if !lockManager.TryAcquire(t.config.Locks, lockHolder) {
+		t.logger.Infof("waiting for locks: %v", lockNames)
+
+		waitCtx := ctx
+
+		if t.config.WaitTimeout.Duration > 0 {
+			var cancel context.CancelFunc
+
+			waitCtx, cancel = context.WithTimeout(ctx, t.config.WaitTimeout.Duration)
+			defer cancel()
+		}
+
+		if err := lockManager.Acquire(waitCtx, t.config.Locks, lockHolder); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			for _, blockingLock := range lockManager.GetBlockingLocks(t.config.Locks, lockHolder.RunID) {
+				t.logger.Warnf("lock '%v' still held by test run %v", blockingLock.Name, blockingLock.Holder.RunID)
+			}
+
+			t.ctx.SetResult(types.TaskResultFailure)
+
+			return fmt.Errorf("could not acquire locks within %v", t.config.WaitTimeout.Duration)
+		}
+	}
+
+	waitTime := time.Since(startTime)
+	t.logger.Infof("acquired locks: %v (waited %v)", lockNames, waitTime.Round(time.Millisecond))
+
+	t.ctx.Outputs.SetVar("waitTime", waitTime.Seconds())
+	t.ctx.SetResult(types.TaskResultSuccess)
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package checkbeaconapiconformance
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/utils/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed beacon-api.yaml
+var beaconAPISpecYaml []byte
+
+var (
+	beaconAPISpecMutex  sync.Mutex
+	beaconAPISpec       map[string]interface{}
+	beaconAPIValidator  *jsonschema.Validator
+	beaconAPICategories = []string{"node", "beacon", "config", "debug", "validator", "events"}
+)
+
+// apiCheck describes a single request sent to each beacon node.
+// Path is the path template from the openapi spec, placeholders are filled by the task before sending the request.
+type apiCheck struct {
+	ID            string
+	Category      string
+	Method        string
+	Path          string
+	Query         string
+	Body          string
+	ExpectStatus  []int
+	ValidateBody  bool
+	SupportsSSZ   bool
+	Versioned     bool
+	AllowEmptySSZ bool
+}
+
+var beaconAPIChecks = []*apiCheck{
+	// node
+	{ID: "getNetworkIdentity", Category: "node", Method: "GET", Path: "/eth/v1/node/identity", ValidateBody: true},
+	{ID: "getPeers", Category: "node", Method: "GET", Path: "/eth/v1/node/peers", ValidateBody: true},
+	{ID: "getPeerCount", Category: "node", Method: "GET", Path: "/eth/v1/node/peer_count", ValidateBody: true},
+	{ID: "getNodeVersion", Category: "node", Method: "GET", Path: "/eth/v1/node/version", ValidateBody: true},
+	{ID: "getSyncingStatus", Category: "node", Method: "GET", Path: "/eth/v1/node/syncing", ValidateBody: true},
+	{ID: "getHealth", Category: "node", Method: "GET", Path: "/eth/v1/node/health", ExpectStatus: []int{200, 206}},
+
+	// beacon
+	{ID: "getGenesis", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/genesis", ValidateBody: true},
+	{ID: "getStateRoot", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/states/{state_id}/root", ValidateBody: true},
+	{ID: "getStateFork", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/states/{state_id}/fork", ValidateBody: true},
+	{ID: "getStateFinalityCheckpoints", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/states/{state_id}/finality_checkpoints", ValidateBody: true},
+	{ID: "getStateValidators", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/states/{state_id}/validators", Query: "id=0,1", ValidateBody: true},
+	{ID: "getStateValidator", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/states/{state_id}/validators/{validator_id}", ValidateBody: true},
+	{ID: "getStateValidatorBalances", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/states/{state_id}/validator_balances", Query: "id=0,1", ValidateBody: true},
+	{ID: "getEpochCommittees", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/states/{state_id}/committees", ValidateBody: true},
+	{ID: "getEpochSyncCommittees", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/states/{state_id}/sync_committees", ValidateBody: true},
+	{ID: "getStateRandao", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/states/{state_id}/randao", ValidateBody: true},
+	{ID: "getBlockHeaders", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/headers", ValidateBody: true},
+	{ID: "getBlockHeader", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/headers/{block_id}", ValidateBody: true},
+	{ID: "getBlockV2", Category: "beacon", Method: "GET", Path: "/eth/v2/beacon/blocks/{block_id}", ValidateBody: true, SupportsSSZ: true, Versioned: true},
+	{ID: "getBlockRoot", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/blocks/{block_id}/root", ValidateBody: true},
+	{ID: "getBlockAttestationsV2", Category: "beacon", Method: "GET", Path: "/eth/v2/beacon/blocks/{block_id}/attestations", ValidateBody: true},
+	{ID: "getBlobSidecars", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/blob_sidecars/{block_id}", ValidateBody: true, SupportsSSZ: true, AllowEmptySSZ: true},
+	{ID: "getPoolAttestationsV2", Category: "beacon", Method: "GET", Path: "/eth/v2/beacon/pool/attestations", ValidateBody: true},
+	{ID: "getPoolAttesterSlashingsV2", Category: "beacon", Method: "GET", Path: "/eth/v2/beacon/pool/attester_slashings", ValidateBody: true},
+	{ID: "getPoolProposerSlashings", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/pool/proposer_slashings", ValidateBody: true},
+	{ID: "getPoolVoluntaryExits", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/pool/voluntary_exits", ValidateBody: true},
+	{ID: "getPoolBLSToExecutionChanges", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/pool/bls_to_execution_changes", ValidateBody: true},
+
+	// beacon: error responses
+	{ID: "getStateRoot_invalidStateId", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/states/invalid_state/root", ExpectStatus: []int{400}},
+	{ID: "getBlockHeader_invalidBlockId", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/headers/invalid_block", ExpectStatus: []int{400}},
+	{ID: "getStateValidator_unknownValidator", Category: "beacon", Method: "GET", Path: "/eth/v1/beacon/states/head/validators/0x" + strings.Repeat("ab", 48), ExpectStatus: []int{404}},
+
+	// config
+	{ID: "getForkSchedule", Category: "config", Method: "GET", Path: "/eth/v1/config/fork_schedule", ValidateBody: true},
+	{ID: "getSpec", Category: "config", Method: "GET", Path: "/eth/v1/config/spec", ValidateBody: true},
+	{ID: "getDepositContract", Category: "config", Method: "GET", Path: "/eth/v1/config/deposit_contract", ValidateBody: true},
+
+	// debug
+	{ID: "getStateV2", Category: "debug", Method: "GET", Path: "/eth/v2/debug/beacon/states/{state_id}", ValidateBody: true, SupportsSSZ: true, Versioned: true},
+	{ID: "getDebugChainHeadsV2", Category: "debug", Method: "GET", Path: "/eth/v2/debug/beacon/heads", ValidateBody: true},
+	{ID: "getDebugForkChoice", Category: "debug", Method: "GET", Path: "/eth/v1/debug/fork_choice", ValidateBody: true},
+
+	// validator (read-only)
+	{ID: "getProposerDuties", Category: "validator", Method: "GET", Path: "/eth/v1/validator/duties/proposer/{epoch}", ValidateBody: true},
+	{ID: "getAttesterDuties", Category: "validator", Method: "POST", Path: "/eth/v1/validator/duties/attester/{epoch}", Body: `["0"]`, ValidateBody: true},
+	{ID: "getSyncCommitteeDuties", Category: "validator", Method: "POST", Path: "/eth/v1/validator/duties/sync/{epoch}", Body: `["0"]`, ValidateBody: true},
+	{ID: "produceAttestationData", Category: "validator", Method: "GET", Path: "/eth/v1/validator/attestation_data", Query: "slot={slot}&committee_index=0", ValidateBody: true},
+
+	// events
+	{ID: "eventstream", Category: "events", Method: "GET", Path: "/eth/v1/events", Query: "topics=head", ValidateBody: true},
+}
+
+func isKnownCategory(category string) bool {
+	for _, known := range beaconAPICategories {
+		if known == category {
+			return true
+		}
+	}
+
+	return false
+}
+
+func loadBeaconAPISpec() (*jsonschema.Validator, error) {
+	beaconAPISpecMutex.Lock()
+	defer beaconAPISpecMutex.Unlock()
+
+	if beaconAPIValidator != nil {
+		return beaconAPIValidator, nil
+	}
+
+	spec := map[string]interface{}{}
+	if err := yaml.Unmarshal(beaconAPISpecYaml, &spec); err != nil {
+		return nil, fmt.Errorf("failed parsing embedded beacon api spec: %w", err)
+	}
+
+	beaconAPISpec = spec
+	beaconAPIValidator = jsonschema.NewValidator(spec)
+
+	return beaconAPIValidator, nil
+}
+
+// getResponseSchema returns the schema of the successful response for the given spec path & method.
+func getResponseSchema(path, method, contentType string) (interface{}, error) {
+	paths, _ := beaconAPISpec["paths"].(map[string]interface{})
+
+	pathSpec, ok := paths[path].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("path %v not found in spec", path)
+	}
+
+	methodSpec, ok := pathSpec[strings.ToLower(method)].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("method %v %v not found in spec", method, path)
+	}
+
+	responses, _ := methodSpec["responses"].(map[string]interface{})
+	response, _ := responses["200"].(map[string]interface{})
+	content, _ := response["content"].(map[string]interface{})
+	contentSpec, _ := content[contentType].(map[string]interface{})
+
+	schema := contentSpec["schema"]
+	if schema == nil {
+		return nil, fmt.Errorf("no %v response schema for %v %v", contentType, method, path)
+	}
+
+	return schema, nil
+}
@@ -0,0 +1,42 @@
+package checkbeaconapiconformance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+)
+
+type Config struct {
+	ClientPattern        string          `yaml:"clientPattern" json:"clientPattern"`
+	ExcludeClientPattern string          `yaml:"excludeClientPattern" json:"excludeClientPattern"`
+	Categories           []string        `yaml:"categories" json:"categories"`
+	Endpoints            []string        `yaml:"endpoints" json:"endpoints"`
+	ExcludeEndpoints     []string        `yaml:"excludeEndpoints" json:"excludeEndpoints"`
+	CheckSSZ             bool            `yaml:"checkSsz" json:"checkSsz"`
+	CheckStatusCodes     bool            `yaml:"checkStatusCodes" json:"checkStatusCodes"`
+	RequestTimeout       helper.Duration `yaml:"requestTimeout" json:"requestTimeout"`
+	EventsTimeout        helper.Duration `yaml:"eventsTimeout" json:"eventsTimeout"`
+	MinClientCount       int             `yaml:"minClientCount" json:"minClientCount"`
+	FailOnMismatch       bool            `yaml:"failOnMismatch" json:"failOnMismatch"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		CheckSSZ:         true,
+		CheckStatusCodes: true,
+		RequestTimeout:   helper.Duration{Duration: 30 * time.Second},
+		EventsTimeout:    helper.Duration{Duration: 30 * time.Second},
+		FailOnMismatch:   true,
+	}
+}
+
+func (c *Config) Validate() error {
+	for _, category := range c.Categories {
+		if !isKnownCategory(category) {
+			return fmt.Errorf("unknown endpoint category: %v", category)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,584 @@
+package checkbeaconapiconformance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+	"github.com/erigontech/assertoor/pkg/coordinator/db"
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/erigontech/assertoor/pkg/coordinator/utils/jsonschema"
+	"github.com/erigontech/assertoor/pkg/coordinator/vars"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	TaskName       = "check_beacon_api_conformance"
+	TaskDescriptor = &types.TaskDescriptor{
+		Name:        TaskName,
+		Description: "Checks the beacon api of consensus clients against the beacon-APIs openapi specification.",
+		Config:      DefaultConfig(),
+		NewTask:     NewTask,
+	}
+)
+
+const (
+	checkResultPass = "pass"
+	checkResultFail = "fail"
+)
+
+type Task struct {
+	ctx        *types.TaskContext
+	options    *types.TaskOptions
+	config     Config
+	logger     logrus.FieldLogger
+	validator  *jsonschema.Validator
+	httpClient *http.Client
+}
+
+type ClientResult struct {
+	Client  string         `json:"client"`
+	Version string         `json:"version"`
+	Passed  int            `json:"passed"`
+	Failed  int            `json:"failed"`
+	Checks  []*CheckResult `json:"checks"`
+}
+
+type CheckResult struct {
+	ID         string   `json:"id"`
+	Category   string   `json:"category"`
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Result     string   `json:"result"`
+	StatusCode int      `json:"statusCode"`
+	SSZResult  string   `json:"sszResult,omitempty"`
+	DurationMs int64    `json:"durationMs"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+func NewTask(ctx *types.TaskContext, options *types.TaskOptions) (types.Task, error) {
+	return &Task{
+		ctx:     ctx,
+		options: options,
+		logger:  ctx.Logger.GetLogger(),
+	}, nil
+}
+
+func (t *Task) Config() interface{} {
+	return t.config
+}
+
+func (t *Task) Timeout() time.Duration {
+	return t.options.Timeout.Duration
+}
+
+func (t *Task) LoadConfig() error {
+	config := DefaultConfig()
+
+	// parse static config
+	if t.options.Config != nil {
+		if err := t.options.Config.Unmarshal(&config); err != nil {
+			return fmt.Errorf("error parsing task config for %v: %w", TaskName, err)
+		}
+	}
+
+	// load dynamic vars
+	err := t.ctx.Vars.ConsumeVars(&config, t.options.ConfigVars)
+	if err != nil {
+		return err
+	}
+
+	// validate config
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	t.config = config
+
+	return nil
+}
+
+func (t *Task) Execute(ctx context.Context) error {
+	validator, err := loadBeaconAPISpec()
+	if err != nil {
+		return err
+	}
+
+	t.validator = validator
+	t.httpClient = &http.Client{}
+
+	checks := t.getSelectedChecks()
+	if len(checks) == 0 {
+		return fmt.Errorf("no beacon api checks selected")
+	}
+
+	poolClients := []*clients.PoolClient{}
+
+	for _, client := range t.ctx.Scheduler.GetServices().ClientPool().GetClientsByNamePatterns(t.config.ClientPattern, t.config.ExcludeClientPattern) {
+		if client.ConsensusClient != nil {
+			poolClients = append(poolClients, client)
+		}
+	}
+
+	if len(poolClients) == 0 || len(poolClients) < t.config.MinClientCount {
+		t.ctx.SetResult(types.TaskResultFailure)
+		return fmt.Errorf("not enough matching consensus clients (have: %v, want: %v)", len(poolClients), max(t.config.MinClientCount, 1))
+	}
+
+	t.logger.Infof("running %v beacon api checks against %v clients", len(checks), len(poolClients))
+
+	results := make([]*ClientResult, len(poolClients))
+	wg := sync.WaitGroup{}
+
+	for idx, client := range poolClients {
+		wg.Add(1)
+
+		go func(idx int, client *clients.PoolClient) {
+			defer wg.Done()
+
+			results[idx] = t.runClientChecks(ctx, client, checks)
+		}(idx, client)
+	}
+
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	totalFailed := 0
+	matrix := map[string]map[string]string{}
+
+	for _, clientResult := range results {
+		totalFailed += clientResult.Failed
+
+		for _, checkResult := range clientResult.Checks {
+			if matrix[checkResult.ID] == nil {
+				matrix[checkResult.ID] = map[string]string{}
+			}
+
+			matrix[checkResult.ID][clientResult.Client] = checkResult.Result
+		}
+
+		if clientResult.Failed > 0 {
+			t.logger.Warnf("client %v failed %v of %v beacon api checks", clientResult.Client, clientResult.Failed, len(clientResult.Checks))
+		} else {
+			t.logger.Infof("client %v passed all %v beacon api checks", clientResult.Client, len(clientResult.Checks))
+		}
+	}
+
+	if matrixData, err := vars.GeneralizeData(matrix); err == nil {
+		t.ctx.Outputs.SetVar("matrix", matrixData)
+	} else {
+		t.logger.Warnf("Failed setting `matrix` output: %v", err)
+	}
+
+	if resultsData, err := vars.GeneralizeData(results); err == nil {
+		t.ctx.Outputs.SetVar("results", resultsData)
+	} else {
+		t.logger.Warnf("Failed setting `results` output: %v", err)
+	}
+
+	t.ctx.Outputs.SetVar("failedCount", totalFailed)
+
+	t.storeTaskResults(checks, results)
+
+	switch {
+	case totalFailed == 0:
+		t.ctx.SetResult(types.TaskResultSuccess)
+	case t.config.FailOnMismatch:
+		t.ctx.SetResult(types.TaskResultFailure)
+	default:
+		t.ctx.SetResult(types.TaskResultNone)
+	}
+
+	return nil
+}
+
+func (t *Task) getSelectedChecks() []*apiCheck {
+	checks := []*apiCheck{}
+
+	for _, check := range beaconAPIChecks {
+		if len(t.config.Categories) > 0 && !slices.Contains(t.config.Categories, check.Category) {
+			continue
+		}
+
+		if len(t.config.Endpoints) > 0 && !slices.Contains(t.config.Endpoints, check.ID) {
+			continue
+		}
+
+		if slices.Contains(t.config.ExcludeEndpoints, check.ID) {
+			continue
+		}
+
+		if !t.config.CheckStatusCodes && len(check.ExpectStatus) > 0 && !slices.Contains(check.ExpectStatus, http.StatusOK) {
+			continue
+		}
+
+		checks = append(checks, check)
+	}
+
+	return checks
+}
+
+func (t *Task) runClientChecks(ctx context.Context, client *clients.PoolClient, checks []*apiCheck) *ClientResult {
+	result := &ClientResult{
+		Client:  client.Config.Name,
+		Version: client.ConsensusClient.GetVersion(),
+		Checks:  make([]*CheckResult, 0, len(checks)),
+	}
+
+	headSlot, _ := client.ConsensusClient.GetLastHead()
+	specs := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetBlockCache().GetSpecs()
+
+	headEpoch := uint64(0)
+	if specs != nil && specs.SlotsPerEpoch > 0 {
+		headEpoch = uint64(headSlot) / specs.SlotsPerEpoch
+	}
+
+	placeholders := strings.NewReplacer(
+		"{state_id}", "head",
+		"{block_id}", "head",
+		"{validator_id}", "0",
+		"{epoch}", fmt.Sprintf("%v", headEpoch),
+		"{slot}", fmt.Sprintf("%v", headSlot),
+	)
+
+	for _, check := range checks {
+		if ctx.Err() != nil {
+			break
+		}
+
+		checkResult := t.runCheck(ctx, client, check, placeholders)
+		result.Checks = append(result.Checks, checkResult)
+
+		if checkResult.Result == checkResultPass {
+			result.Passed++
+		} else {
+			result.Failed++
+
+			t.logger.Debugf("client %v failed check %v: %v", client.Config.Name, check.ID, strings.Join(checkResult.Errors, ", "))
+		}
+	}
+
+	return result
+}
+
+func (t *Task) runCheck(ctx context.Context, client *clients.PoolClient, check *apiCheck, placeholders *strings.Replacer) *CheckResult {
+	result := &CheckResult{
+		ID:       check.ID,
+		Category: check.Category,
+		Method:   check.Method,
+		Path:     check.Path,
+	}
+	startTime := time.Now()
+
+	requestURL := strings.TrimSuffix(client.ConsensusClient.GetEndpointConfig().URL, "/") + placeholders.Replace(check.Path)
+	if check.Query != "" {
+		requestURL += "?" + placeholders.Replace(check.Query)
+	}
+
+	if check.Category == "events" {
+		result.StatusCode, result.Errors = t.checkEventStream(ctx, client, check, requestURL)
+	} else {
+		result.StatusCode, result.Errors = t.checkJSONResponse(ctx, client, check, requestURL)
+
+		if t.config.CheckSSZ && check.SupportsSSZ {
+			sszErrors := t.checkSSZResponse(ctx, client, check, requestURL)
+			if len(sszErrors) > 0 {
+				result.SSZResult = checkResultFail
+
+				for _, sszError := range sszErrors {
+					result.Errors = append(result.Errors, "ssz: "+sszError)
+				}
+			} else {
+				result.SSZResult = checkResultPass
+			}
+		}
+	}
+
+	result.DurationMs = time.Since(startTime).Milliseconds()
+
+	if len(result.Errors) > 0 {
+		result.Result = checkResultFail
+	} else {
+		result.Result = checkResultPass
+	}
+
+	return result
+}
+
+func (t *Task) sendRequest(ctx context.Context, client *clients.PoolClient, check *apiCheck, requestURL, accept string) (*http.Response, error) {
+	var body io.Reader
+	if check.Body != "" {
+		body = bytes.NewReader([]byte(check.Body))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, check.Method, requestURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for headerName, headerValue := range client.ConsensusClient.GetEndpointConfig().Headers {
+		req.Header.Set(headerName, headerValue)
+	}
+
+	req.Header.Set("Accept", accept)
+
+	if check.Body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return t.httpClient.Do(req)
+}
+
+func (t *Task) checkJSONResponse(ctx context.Context, client *clients.PoolClient, check *apiCheck, requestURL string) (statusCode int, errs []string) {
+	reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+	defer cancel()
+
+	rsp, err := t.sendRequest(reqCtx, client, check, requestURL, "application/json")
+	if err != nil {
+		return 0, []string{fmt.Sprintf("request failed: %v", err)}
+	}
+
+	defer rsp.Body.Close()
+
+	rspData, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return rsp.StatusCode, []string{fmt.Sprintf("failed reading response: %v", err)}
+	}
+
+	expectStatus := check.ExpectStatus
+	if len(expectStatus) == 0 {
+		expectStatus = []int{http.StatusOK}
+	}
+
+	if !slices.Contains(expectStatus, rsp.StatusCode) {
+		return rsp.StatusCode, []string{fmt.Sprintf("unexpected status code %v (expected %v)", rsp.StatusCode, expectStatus)}
+	}
+
+	if !check.ValidateBody || rsp.StatusCode != http.StatusOK {
+		return rsp.StatusCode, nil
+	}
+
+	if contentType := rsp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+		errs = append(errs, fmt.Sprintf("unexpected content type %q (expected application/json)", contentType))
+	}
+
+	if check.Versioned && rsp.Header.Get("Eth-Consensus-Version") == "" {
+		errs = append(errs, "missing Eth-Consensus-Version header")
+	}
+
+	var rspBody interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(rspData))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&rspBody); err != nil {
+		return rsp.StatusCode, append(errs, fmt.Sprintf("failed decoding json response: %v", err))
+	}
+
+	schema, err := getResponseSchema(check.Path, check.Method, "application/json")
+	if err != nil {
+		return rsp.StatusCode, append(errs, err.Error())
+	}
+
+	return rsp.StatusCode, append(errs, t.validator.Validate(schema, rspBody)...)
+}
+
+func (t *Task) checkSSZResponse(ctx context.Context, client *clients.PoolClient, check *apiCheck, requestURL string) []string {
+	reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+	defer cancel()
+
+	rsp, err := t.sendRequest(reqCtx, client, check, requestURL, "application/octet-stream")
+	if err != nil {
+		return []string{fmt.Sprintf("request failed: %v", err)}
+	}
+
+	defer rsp.Body.Close()
+
+	rspData, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return []string{fmt.Sprintf("failed reading response: %v", err)}
+	}
+
+	if rsp.StatusCode != http.StatusOK {
+		return []string{fmt.Sprintf("unexpected status code %v (expected 200)", rsp.StatusCode)}
+	}
+
+	errs := []string{}
+
+	if contentType := rsp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "application/octet-stream") {
+		errs = append(errs, fmt.Sprintf("unexpected content type %q (expected application/octet-stream)", contentType))
+	}
+
+	if check.Versioned && rsp.Header.Get("Eth-Consensus-Version") == "" {
+		errs = append(errs, "missing Eth-Consensus-Version header")
+	}
+
+	if len(rspData) == 0 && !check.AllowEmptySSZ {
+		errs = append(errs, "empty response body")
+	}
+
+	return errs
+}
+
+func (t *Task) checkEventStream(ctx context.Context, client *clients.PoolClient, check *apiCheck, requestURL string) (statusCode int, errs []string) {
+	reqCtx, cancel := context.WithTimeout(ctx, t.config.EventsTimeout.Duration)
+	defer cancel()
+
+	rsp, err := t.sendRequest(reqCtx, client, check, requestURL, "text/event-stream")
+	if err != nil {
+		return 0, []string{fmt.Sprintf("request failed: %v", err)}
+	}
+
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		return rsp.StatusCode, []string{fmt.Sprintf("unexpected status code %v (expected 200)", rsp.StatusCode)}
+	}
+
+	if contentType := rsp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/event-stream") {
+		errs = append(errs, fmt.Sprintf("unexpected content type %q (expected text/event-stream)", contentType))
+	}
+
+	schema, err := getResponseSchema(check.Path, check.Method, "text/event-stream")
+	if err != nil {
+		return rsp.StatusCode, append(errs, err.Error())
+	}
+
+	scanner := bufio.NewScanner(rsp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	eventName := ""
+	eventData := []string{}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			eventName = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			eventData = append(eventData, strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		case line == "" && eventName != "":
+			if eventName != "head" {
+				errs = append(errs, fmt.Sprintf("unexpected event %q (subscribed to head)", eventName))
+				return rsp.StatusCode, errs
+			}
+
+			var eventBody interface{}
+
+			decoder := json.NewDecoder(strings.NewReader(strings.Join(eventData, "\n")))
+			decoder.UseNumber()
+
+			if err := decoder.Decode(&eventBody); err != nil {
+				return rsp.StatusCode, append(errs, fmt.Sprintf("failed decoding head event: %v", err))
+			}
+
+			return rsp.StatusCode, append(errs, t.validator.Validate(schema, eventBody)...)
+		}
+	}
+
+	return rsp.StatusCode, append(errs, fmt.Sprintf("no head event received within %v", t.config.EventsTimeout.Duration))
+}
+
+func (t *Task) storeTaskResults(checks []*apiCheck, results []*ClientResult) {
+	summary := t.buildSummary(checks, results)
+
+	resultData, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		t.logger.Errorf("failed serializing conformance results: %v", err)
+		return
+	}
+
+	database := t.ctx.Scheduler.GetServices().Database()
+	if err := database.RunTransaction(func(tx *sqlx.Tx) error {
+		if err := database.UpsertTaskResult(tx, &db.TaskResult{
+			RunID:  t.ctx.Scheduler.GetTestRunID(),
+			TaskID: uint64(t.ctx.Index),
+			Type:   "summary",
+			Index:  0,
+			Name:   "",
+			Size:   uint64(len(summary)),
+			Data:   summary,
+		}); err != nil {
+			return err
+		}
+
+		return database.UpsertTaskResult(tx, &db.TaskResult{
+			RunID:  t.ctx.Scheduler.GetTestRunID(),
+			TaskID: uint64(t.ctx.Index),
+			Type:   "result",
+			Index:  0,
+			Name:   "beacon-api-conformance.json",
+			Size:   uint64(len(resultData)),
+			Data:   resultData,
+		})
+	}); err != nil {
+		t.logger.Errorf("failed storing conformance results to db: %v", err)
+	}
+}
+
+// buildSummary renders the per-client, per-endpoint pass/fail matrix as markdown.
+func (t *Task) buildSummary(checks []*apiCheck, results []*ClientResult) []byte {
+	summary := &strings.Builder{}
+
+	fmt.Fprintf(summary, "# Beacon API conformance\n\n| Endpoint |")
+
+	for _, clientResult := range results {
+		fmt.Fprintf(summary, " %v |", clientResult.Client)
+	}
+
+	fmt.Fprintf(summary, "\n|---|%v\n", strings.Repeat("---|", len(results)))
+
+	clientChecks := make([]map[string]*CheckResult, len(results))
+
+	for idx, clientResult := range results {
+		clientChecks[idx] = map[string]*CheckResult{}
+		for _, checkResult := range clientResult.Checks {
+			clientChecks[idx][checkResult.ID] = checkResult
+		}
+	}
+
+	for _, check := range checks {
+		fmt.Fprintf(summary, "| `%v` |", check.ID)
+
+		for idx := range results {
+			checkResult := clientChecks[idx][check.ID]
+			if checkResult == nil {
+				fmt.Fprintf(summary, " - |")
+			} else {
+				fmt.Fprintf(summary, " %v |", checkResult.Result)
+			}
+		}
+
+		fmt.Fprintf(summary, "\n")
+	}
+
+	failures := []string{}
+
+	for _, clientResult := range results {
+		for _, checkResult := range clientResult.Checks {
+			for _, checkError := range checkResult.Errors {
+				failures = append(failures, fmt.Sprintf("- %v `%v`: %v", clientResult.Client, checkResult.ID, checkError))
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		fmt.Fprintf(summary, "\n## Failures\n\n%v\n", strings.Join(failures, "\n"))
+	}
+
+	return []byte(summary.String())
+}
@@ -0,0 +1,38 @@
+package checkchainspecconsistency
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+)
+
+type Config struct {
+	ClientPattern        string          `yaml:"clientPattern" json:"clientPattern"`
+	ExcludeClientPattern string          `yaml:"excludeClientPattern" json:"excludeClientPattern"`
+	IgnoreKeys           []string        `yaml:"ignoreKeys" json:"ignoreKeys"`
+	IgnoreMissingKeys    bool            `yaml:"ignoreMissingKeys" json:"ignoreMissingKeys"`
+	MinClientCount       int             `yaml:"minClientCount" json:"minClientCount"`
+	RequestTimeout       helper.Duration `yaml:"requestTimeout" json:"requestTimeout"`
+	FailOnClientError    bool            `yaml:"failOnClientError" json:"failOnClientError"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		IgnoreMissingKeys: true,
+		MinClientCount:    1,
+		RequestTimeout:    helper.Duration{Duration: 30 * time.Second},
+		FailOnClientError: true,
+	}
+}
+
+func (c *Config) Validate() error {
+	for _, pattern := range c.IgnoreKeys {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid ignoreKeys pattern %v: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
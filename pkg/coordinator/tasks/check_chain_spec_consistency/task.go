@@ -0,0 +1,228 @@
+package checkchainspecconsistency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+	"github.com/erigontech/assertoor/pkg/coordinator/db"
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/erigontech/assertoor/pkg/coordinator/vars"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	TaskName       = "check_chain_spec_consistency"
+	TaskDescriptor = &types.TaskDescriptor{
+		Name:        TaskName,
+		Description: "Checks that all clients use the same chain spec, deposit contract & fork schedule.",
+		Config:      DefaultConfig(),
+		NewTask:     NewTask,
+	}
+)
+
+type Task struct {
+	ctx     *types.TaskContext
+	options *types.TaskOptions
+	config  Config
+	logger  logrus.FieldLogger
+}
+
+type Mismatch struct {
+	Layer         string            `json:"layer"`
+	Key           string            `json:"key"`
+	MajorityValue string            `json:"majorityValue"`
+	Deviating     map[string]string `json:"deviating"`
+}
+
+func NewTask(ctx *types.TaskContext, options *types.TaskOptions) (types.Task, error) {
+	return &Task{
+		ctx:     ctx,
+		options: options,
+		logger:  ctx.Logger.GetLogger(),
+	}, nil
+}
+
+func (t *Task) Config() interface{} {
+	return t.config
+}
+
+func (t *Task) Timeout() time.Duration {
+	return t.options.Timeout.Duration
+}
+
+func (t *Task) LoadConfig() error {
+	config := DefaultConfig()
+
+	// parse static config
+	if t.options.Config != nil {
+		if err := t.options.Config.Unmarshal(&config); err != nil {
+			return fmt.Errorf("error parsing task config for %v: %w", TaskName, err)
+		}
+	}
+
+	// load dynamic vars
+	err := t.ctx.Vars.ConsumeVars(&config, t.options.ConfigVars)
+	if err != nil {
+		return err
+	}
+
+	// validate config
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	t.config = config
+
+	return nil
+}
+
+func (t *Task) Execute(ctx context.Context) error {
+	poolClients := t.ctx.Scheduler.GetServices().ClientPool().GetClientsByNamePatterns(t.config.ClientPattern, t.config.ExcludeClientPattern)
+	if len(poolClients) < t.config.MinClientCount {
+		t.ctx.SetResult(types.TaskResultFailure)
+		return fmt.Errorf("not enough matching clients (have: %v, want: %v)", len(poolClients), t.config.MinClientCount)
+	}
+
+	compareOptions := &clients.ChainSpecCompareOptions{
+		IgnoreMissing: t.config.IgnoreMissingKeys,
+		IgnoreKeys:    make([]*regexp.Regexp, len(t.config.IgnoreKeys)),
+	}
+
+	for idx, pattern := range t.config.IgnoreKeys {
+		compareOptions.IgnoreKeys[idx] = regexp.MustCompile(pattern)
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+	report := clients.CompareChainSpecs(reqCtx, poolClients, compareOptions)
+
+	cancel()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	mismatches := []*Mismatch{}
+
+	for _, field := range report.Fields {
+		if len(field.Deviating) == 0 {
+			continue
+		}
+
+		mismatch := &Mismatch{
+			Layer:         field.Layer,
+			Key:           field.Key,
+			MajorityValue: field.MajorityValue,
+			Deviating:     map[string]string{},
+		}
+
+		for _, client := range field.Deviating {
+			mismatch.Deviating[client] = field.Values[client]
+			t.logger.Errorf("%v spec mismatch on %v: %v has %v, majority has %v", field.Layer, field.Key, client, field.Values[client], field.MajorityValue)
+		}
+
+		mismatches = append(mismatches, mismatch)
+	}
+
+	for _, clientErr := range report.Errors {
+		t.logger.Warnf("failed fetching %v spec from %v: %v", clientErr.Layer, clientErr.Client, clientErr.Error)
+	}
+
+	t.ctx.Outputs.SetVar("mismatchCount", len(mismatches))
+	t.ctx.Outputs.SetVar("deviatingClients", report.DeviatingNames)
+
+	if mismatchesData, err := vars.GeneralizeData(mismatches); err == nil {
+		t.ctx.Outputs.SetVar("mismatches", mismatchesData)
+	} else {
+		t.logger.Warnf("failed setting `mismatches` output: %v", err)
+	}
+
+	t.storeTaskResults(report, mismatches)
+
+	switch {
+	case len(mismatches) > 0:
+		t.ctx.SetResult(types.TaskResultFailure)
+		return fmt.Errorf("%v chain spec mismatches found (deviating clients: %v)", len(mismatches), strings.Join(report.DeviatingNames, ", "))
+	case len(report.Errors) > 0 && t.config.FailOnClientError:
+		t.ctx.SetResult(types.TaskResultFailure)
+		return fmt.Errorf("failed fetching chain spec from %v clients", len(report.Errors))
+	}
+
+	t.logger.Infof("chain specs of %v clients are consistent (%v fields compared)", len(poolClients), report.FieldCount)
+	t.ctx.SetResult(types.TaskResultSuccess)
+
+	return nil
+}
+
+func (t *Task) storeTaskResults(report *clients.ChainSpecReport, mismatches []*Mismatch) {
+	summary := t.buildSummary(report, mismatches)
+
+	reportData, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		t.logger.Errorf("failed serializing chain spec report: %v", err)
+		return
+	}
+
+	database := t.ctx.Scheduler.GetServices().Database()
+	if err := database.RunTransaction(func(tx *sqlx.Tx) error {
+		if err := database.UpsertTaskResult(tx, &db.TaskResult{
+			RunID:  t.ctx.Scheduler.GetTestRunID(),
+			TaskID: uint64(t.ctx.Index),
+			Type:   "summary",
+			Index:  0,
+			Name:   "",
+			Size:   uint64(len(summary)),
+			Data:   summary,
+		}); err != nil {
+			return err
+		}
+
+		return database.UpsertTaskResult(tx, &db.TaskResult{
+			RunID:  t.ctx.Scheduler.GetTestRunID(),
+			TaskID: uint64(t.ctx.Index),
+			Type:   "result",
+			Index:  0,
+			Name:   "chain-spec-report.json",
+			Size:   uint64(len(reportData)),
+			Data:   reportData,
+		})
+	}); err != nil {
+		t.logger.Errorf("failed storing chain spec report to db: %v", err)
+	}
+}
+
+// buildSummary renders the deviating values per key as markdown diff.
+func (t *Task) buildSummary(report *clients.ChainSpecReport, mismatches []*Mismatch) []byte {
+	summary := &strings.Builder{}
+
+	fmt.Fprintf(summary, "# Chain spec consistency\n\n")
+	fmt.Fprintf(summary, "Compared %v fields of %v clients, found %v mismatches.\n", report.FieldCount, len(report.Clients), len(mismatches))
+
+	for _, mismatch := range mismatches {
+		fmt.Fprintf(summary, "\n### %v: `%v`\n\n```diff\n", mismatch.Layer, mismatch.Key)
+		fmt.Fprintf(summary, "  %v (majority)\n", mismatch.MajorityValue)
+
+		for _, client := range report.Clients {
+			if value, found := mismatch.Deviating[client]; found {
+				fmt.Fprintf(summary, "- %v (%v)\n", value, client)
+			}
+		}
+
+		fmt.Fprintf(summary, "```\n")
+	}
+
+	if len(report.Errors) > 0 {
+		fmt.Fprintf(summary, "\n## Errors\n\n| Client | Layer | Error |\n|---|---|---|\n")
+
+		for _, clientErr := range report.Errors {
+			fmt.Fprintf(summary, "| %v | %v | %v |\n", clientErr.Client, clientErr.Layer, strings.ReplaceAll(clientErr.Error, "|", "\\|"))
+		}
+	}
+
+	return []byte(summary.String())
+}
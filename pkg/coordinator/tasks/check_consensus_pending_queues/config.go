@@ -0,0 +1,34 @@
+package checkconsensuspendingqueues
+
+import (
+	"errors"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+)
+
+type Config struct {
+	ClientPattern               string          `yaml:"clientPattern" json:"clientPattern"`
+	DepositPubkeys              []string        `yaml:"depositPubkeys" json:"depositPubkeys"`
+	ConsolidationSources        []string        `yaml:"consolidationSources" json:"consolidationSources"`
+	PartialWithdrawalValidators []string        `yaml:"partialWithdrawalValidators" json:"partialWithdrawalValidators"`
+	MaxAppearEpochs             uint64          `yaml:"maxAppearEpochs" json:"maxAppearEpochs"`
+	EpochTolerance              uint64          `yaml:"epochTolerance" json:"epochTolerance"`
+	RequestTimeout              helper.Duration `yaml:"requestTimeout" json:"requestTimeout"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MaxAppearEpochs: 2,
+		EpochTolerance:  2,
+		RequestTimeout:  helper.Duration{Duration: 1 * time.Minute},
+	}
+}
+
+func (c *Config) Validate() error {
+	if len(c.DepositPubkeys) == 0 && len(c.ConsolidationSources) == 0 && len(c.PartialWithdrawalValidators) == 0 {
+		return errors.New("at least one of depositPubkeys, consolidationSources or partialWithdrawalValidators must be set")
+	}
+
+	return nil
+}
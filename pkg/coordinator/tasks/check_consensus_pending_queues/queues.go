@@ -0,0 +1,145 @@
+package checkconsensuspendingqueues
+
+import (
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// queueSpec holds the spec values used for the pending queue processing.
+type queueSpec struct {
+	slotsPerEpoch                       uint64
+	effectiveBalanceIncrement           uint64
+	minPerEpochChurnLimitElectra        uint64
+	churnLimitQuotient                  uint64
+	maxPerEpochActivationExitChurnLimit uint64
+	maxPendingDepositsPerEpoch          uint64
+	maxPendingPartialsPerSweep          uint64
+}
+
+func loadQueueSpec(specValues map[string]interface{}) *queueSpec {
+	getValue := func(key string, defaultValue uint64) uint64 {
+		if value, isUint := specValues[key].(uint64); isUint {
+			return value
+		}
+
+		return defaultValue
+	}
+
+	return &queueSpec{
+		slotsPerEpoch:                       getValue("SLOTS_PER_EPOCH", 32),
+		effectiveBalanceIncrement:           getValue("EFFECTIVE_BALANCE_INCREMENT", 1000000000),
+		minPerEpochChurnLimitElectra:        getValue("MIN_PER_EPOCH_CHURN_LIMIT_ELECTRA", 128000000000),
+		churnLimitQuotient:                  getValue("CHURN_LIMIT_QUOTIENT", 65536),
+		maxPerEpochActivationExitChurnLimit: getValue("MAX_PER_EPOCH_ACTIVATION_EXIT_CHURN_LIMIT", 256000000000),
+		maxPendingDepositsPerEpoch:          getValue("MAX_PENDING_DEPOSITS_PER_EPOCH", 16),
+		maxPendingPartialsPerSweep:          getValue("MAX_PENDING_PARTIALS_PER_WITHDRAWALS_SWEEP", 8),
+	}
+}
+
+type ChurnLimits struct {
+	TotalActiveBalance  uint64 `json:"totalActiveBalance"`
+	BalanceChurn        uint64 `json:"balanceChurn"`
+	ActivationExitChurn uint64 `json:"activationExitChurn"`
+	ConsolidationChurn  uint64 `json:"consolidationChurn"`
+}
+
+// computeChurnLimits computes the electra churn limits (`get_balance_churn_limit`, `get_activation_exit_churn_limit`
+// & `get_consolidation_churn_limit`) for the given epoch.
+func computeChurnLimits(validators []*phase0.Validator, epoch uint64, spec *queueSpec) *ChurnLimits {
+	totalActiveBalance := uint64(0)
+
+	for _, validator := range validators {
+		if uint64(validator.ActivationEpoch) <= epoch && epoch < uint64(validator.ExitEpoch) {
+			totalActiveBalance += uint64(validator.EffectiveBalance)
+		}
+	}
+
+	totalActiveBalance = max(spec.effectiveBalanceIncrement, totalActiveBalance)
+
+	balanceChurn := max(spec.minPerEpochChurnLimitElectra, totalActiveBalance/spec.churnLimitQuotient)
+	balanceChurn -= balanceChurn % spec.effectiveBalanceIncrement
+
+	activationExitChurn := min(spec.maxPerEpochActivationExitChurnLimit, balanceChurn)
+
+	return &ChurnLimits{
+		TotalActiveBalance:  totalActiveBalance,
+		BalanceChurn:        balanceChurn,
+		ActivationExitChurn: activationExitChurn,
+		ConsolidationChurn:  balanceChurn - activationExitChurn,
+	}
+}
+
+// estimateDepositEpoch simulates `process_pending_deposits` and returns the first epoch in which the deposit
+// at the given queue position is no longer pending. Finality is assumed to follow one epoch behind the processed epoch.
+func estimateDepositEpoch(deposits []*electra.PendingDeposit, position int, currentEpoch, finalizedEpoch, depositBalanceToConsume, churn uint64, spec *queueSpec) uint64 {
+	idx := 0
+	balanceToConsume := depositBalanceToConsume
+
+	for epoch := currentEpoch; epoch < currentEpoch+100000; epoch++ {
+		if epoch > 0 {
+			finalizedEpoch = max(finalizedEpoch, epoch-1)
+		}
+
+		finalizedSlot := finalizedEpoch * spec.slotsPerEpoch
+		availableForProcessing := balanceToConsume + churn
+		processedAmount := uint64(0)
+		processedCount := uint64(0)
+		churnLimitReached := false
+
+		for idx <= position {
+			deposit := deposits[idx]
+
+			if uint64(deposit.Slot) > finalizedSlot || processedCount >= spec.maxPendingDepositsPerEpoch {
+				break
+			}
+
+			if processedAmount+uint64(deposit.Amount) > availableForProcessing {
+				churnLimitReached = true
+				break
+			}
+
+			processedAmount += uint64(deposit.Amount)
+			processedCount++
+			idx++
+		}
+
+		if idx > position {
+			return epoch + 1
+		}
+
+		if churnLimitReached {
+			balanceToConsume = availableForProcessing - processedAmount
+		} else {
+			balanceToConsume = 0
+		}
+	}
+
+	return currentEpoch + 100000
+}
+
+// estimateConsolidationEpoch returns the first epoch in which the consolidation at the given queue position is no longer pending.
+// Consolidations are processed in order once the source validator is withdrawable.
+func estimateConsolidationEpoch(consolidations []*electra.PendingConsolidation, position int, validators []*phase0.Validator) uint64 {
+	epoch := uint64(0)
+
+	for idx := 0; idx <= position; idx++ {
+		sourceIndex := uint64(consolidations[idx].SourceIndex)
+		if sourceIndex < uint64(len(validators)) {
+			epoch = max(epoch, uint64(validators[sourceIndex].WithdrawableEpoch))
+		}
+	}
+
+	return epoch
+}
+
+// estimatePartialWithdrawalEpoch returns the first epoch in which the partial withdrawal at the given queue position is no longer pending.
+// Each block processes up to MAX_PENDING_PARTIALS_PER_WITHDRAWALS_SWEEP withdrawable entries from the front of the queue.
+func estimatePartialWithdrawalEpoch(withdrawals []*electra.PendingPartialWithdrawal, position int, spec *queueSpec) uint64 {
+	epoch := uint64(0)
+
+	for idx := 0; idx <= position; idx++ {
+		epoch = max(epoch, uint64(withdrawals[idx].WithdrawableEpoch))
+	}
+
+	return epoch + uint64(position)/(spec.maxPendingPartialsPerSweep*spec.slotsPerEpoch)
+}
@@ -0,0 +1,457 @@
+package checkconsensuspendingqueues
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus"
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/erigontech/assertoor/pkg/coordinator/vars"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	TaskName       = "check_consensus_pending_queues"
+	TaskDescriptor = &types.TaskDescriptor{
+		Name:        TaskName,
+		Description: "Tracks deposits, consolidations and partial withdrawals through the electra pending queues.",
+		Config:      DefaultConfig(),
+		NewTask:     NewTask,
+	}
+)
+
+const (
+	queueDeposits           = "deposit"
+	queueConsolidations     = "consolidation"
+	queuePartialWithdrawals = "partial_withdrawal"
+
+	statusWaiting   = "waiting"
+	statusQueued    = "queued"
+	statusProcessed = "processed"
+)
+
+type Task struct {
+	ctx        *types.TaskContext
+	options    *types.TaskOptions
+	config     Config
+	logger     logrus.FieldLogger
+	queueSpec  *queueSpec
+	entries    []*QueueEntry
+	startEpoch *uint64
+	lastEpoch  *uint64
+	stats      *QueueStats
+}
+
+type QueueEntry struct {
+	Queue          string   `json:"queue"`
+	Key            string   `json:"key"`
+	ValidatorIndex *uint64  `json:"validatorIndex,omitempty"`
+	Status         string   `json:"status"`
+	Position       int      `json:"position"`
+	FirstSeenEpoch *uint64  `json:"firstSeenEpoch,omitempty"`
+	ExpectedEpoch  *uint64  `json:"expectedEpoch,omitempty"`
+	DeadlineEpoch  *uint64  `json:"deadlineEpoch,omitempty"`
+	ProcessedEpoch *uint64  `json:"processedEpoch,omitempty"`
+	Errors         []string `json:"errors"`
+
+	pubkey    *phase0.BLSPubKey
+	lastIndex int
+}
+
+type QueueStats struct {
+	Epoch                         uint64       `json:"epoch"`
+	PendingDeposits               int          `json:"pendingDeposits"`
+	PendingConsolidations         int          `json:"pendingConsolidations"`
+	PendingPartialWithdrawals     int          `json:"pendingPartialWithdrawals"`
+	DepositBalanceToConsume       uint64       `json:"depositBalanceToConsume"`
+	ExitBalanceToConsume          uint64       `json:"exitBalanceToConsume"`
+	ConsolidationBalanceToConsume uint64       `json:"consolidationBalanceToConsume"`
+	ChurnLimits                   *ChurnLimits `json:"churnLimits"`
+}
+
+func NewTask(ctx *types.TaskContext, options *types.TaskOptions) (types.Task, error) {
+	return &Task{
+		ctx:     ctx,
+		options: options,
+		logger:  ctx.Logger.GetLogger(),
+	}, nil
+}
+
+func (t *Task) Config() interface{} {
+	return t.config
+}
+
+func (t *Task) Timeout() time.Duration {
+	return t.options.Timeout.Duration
+}
+
+func (t *Task) LoadConfig() error {
+	config := DefaultConfig()
+
+	// parse static config
+	if t.options.Config != nil {
+		if err := t.options.Config.Unmarshal(&config); err != nil {
+			return fmt.Errorf("error parsing task config for %v: %w", TaskName, err)
+		}
+	}
+
+	// load dynamic vars
+	err := t.ctx.Vars.ConsumeVars(&config, t.options.ConfigVars)
+	if err != nil {
+		return err
+	}
+
+	// validate config
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	t.config = config
+
+	return nil
+}
+
+func (t *Task) Execute(ctx context.Context) error {
+	blockCache := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetBlockCache()
+	t.queueSpec = loadQueueSpec(blockCache.GetSpecValues())
+
+	if err := t.loadEntries(); err != nil {
+		return err
+	}
+
+	slotSubscription := blockCache.SubscribeWallclockSlotEvent(10)
+	defer slotSubscription.Unsubscribe()
+
+	checkQueues := true
+
+	for {
+		if checkQueues {
+			done, err := t.checkQueues(ctx)
+			if err != nil {
+				t.ctx.SetResult(types.TaskResultFailure)
+				return err
+			}
+
+			if done {
+				t.ctx.SetResult(types.TaskResultSuccess)
+				return nil
+			}
+		}
+
+		select {
+		case slot := <-slotSubscription.Channel():
+			// check once per epoch, one slot after the epoch transition
+			checkQueues = slot.Number()%t.queueSpec.slotsPerEpoch == 1
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (t *Task) loadEntries() error {
+	for _, pubkeyStr := range t.config.DepositPubkeys {
+		pubkey, err := parsePubkey(pubkeyStr)
+		if err != nil {
+			return fmt.Errorf("invalid deposit pubkey %v: %w", pubkeyStr, err)
+		}
+
+		t.entries = append(t.entries, &QueueEntry{
+			Queue:  queueDeposits,
+			Key:    pubkeyStr,
+			pubkey: pubkey,
+		})
+	}
+
+	validatorEntries := []struct {
+		queue string
+		keys  []string
+	}{
+		{queueConsolidations, t.config.ConsolidationSources},
+		{queuePartialWithdrawals, t.config.PartialWithdrawalValidators},
+	}
+
+	for _, validatorEntry := range validatorEntries {
+		for _, key := range validatorEntry.keys {
+			entry := &QueueEntry{
+				Queue: validatorEntry.queue,
+				Key:   key,
+			}
+
+			if strings.HasPrefix(key, "0x") {
+				pubkey, err := parsePubkey(key)
+				if err != nil {
+					return fmt.Errorf("invalid %v validator pubkey %v: %w", validatorEntry.queue, key, err)
+				}
+
+				entry.pubkey = pubkey
+			} else {
+				index, err := strconv.ParseUint(key, 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid %v validator index %v: %w", validatorEntry.queue, key, err)
+				}
+
+				entry.ValidatorIndex = &index
+			}
+
+			t.entries = append(t.entries, entry)
+		}
+	}
+
+	for _, entry := range t.entries {
+		entry.Status = statusWaiting
+		entry.Position = -1
+		entry.lastIndex = -1
+		entry.Errors = []string{}
+	}
+
+	return nil
+}
+
+func parsePubkey(pubkeyStr string) (*phase0.BLSPubKey, error) {
+	pubkeyBytes := common.FromHex(pubkeyStr)
+	if len(pubkeyBytes) != len(phase0.BLSPubKey{}) {
+		return nil, fmt.Errorf("expected %v bytes, got %v", len(phase0.BLSPubKey{}), len(pubkeyBytes))
+	}
+
+	pubkey := phase0.BLSPubKey{}
+	copy(pubkey[:], pubkeyBytes)
+
+	return &pubkey, nil
+}
+
+// checkQueues loads the head state and updates the status of all tracked entries.
+// Returns true once all entries have been processed.
+func (t *Task) checkQueues(ctx context.Context) (bool, error) {
+	client := t.getStateClient(ctx)
+	if client == nil {
+		t.logger.Warnf("no ready consensus client")
+		return false, nil
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+	defer cancel()
+
+	state, err := client.GetRPCClient().GetState(reqCtx, "head")
+	if err != nil {
+		t.logger.Warnf("could not load head state from %v: %v", client.GetName(), err)
+		return false, nil
+	}
+
+	if state.Version < spec.DataVersionElectra || state.Electra == nil {
+		t.logger.Infof("head state is pre-electra (%v), waiting for electra", state.Version)
+		return false, nil
+	}
+
+	electraState := state.Electra
+	stateEpoch := uint64(electraState.Slot) / t.queueSpec.slotsPerEpoch
+
+	if t.lastEpoch != nil && *t.lastEpoch == stateEpoch {
+		return false, nil
+	}
+
+	t.lastEpoch = &stateEpoch
+
+	if t.startEpoch == nil {
+		t.startEpoch = &stateEpoch
+	}
+
+	churnLimits := computeChurnLimits(electraState.Validators, stateEpoch, t.queueSpec)
+	t.stats = &QueueStats{
+		Epoch:                         stateEpoch,
+		PendingDeposits:               len(electraState.PendingDeposits),
+		PendingConsolidations:         len(electraState.PendingConsolidations),
+		PendingPartialWithdrawals:     len(electraState.PendingPartialWithdrawals),
+		DepositBalanceToConsume:       uint64(electraState.DepositBalanceToConsume),
+		ExitBalanceToConsume:          uint64(electraState.ExitBalanceToConsume),
+		ConsolidationBalanceToConsume: uint64(electraState.ConsolidationBalanceToConsume),
+		ChurnLimits:                   churnLimits,
+	}
+
+	t.logger.Infof("epoch %v: %v pending deposits, %v pending consolidations, %v pending partial withdrawals (activation/exit churn: %v gwei, consolidation churn: %v gwei)",
+		stateEpoch, t.stats.PendingDeposits, t.stats.PendingConsolidations, t.stats.PendingPartialWithdrawals, churnLimits.ActivationExitChurn, churnLimits.ConsolidationChurn)
+
+	failedEntries := 0
+	doneEntries := 0
+
+	for _, entry := range t.entries {
+		errorCount := len(entry.Errors)
+
+		// resolve validator pubkeys to indices once the validator exists
+		if entry.Queue != queueDeposits && entry.ValidatorIndex == nil {
+			for index, validator := range electraState.Validators {
+				if bytes.Equal(validator.PublicKey[:], entry.pubkey[:]) {
+					validatorIndex := uint64(index)
+					entry.ValidatorIndex = &validatorIndex
+
+					break
+				}
+			}
+		}
+
+		position := t.findEntry(entry, state)
+		t.updateEntry(entry, state, position, stateEpoch, churnLimits)
+
+		if len(entry.Errors) > errorCount {
+			for _, entryErr := range entry.Errors[errorCount:] {
+				t.logger.Errorf("%v %v: %v", entry.Queue, entry.Key, entryErr)
+			}
+		}
+
+		if len(entry.Errors) > 0 {
+			failedEntries++
+		}
+
+		if entry.Status == statusProcessed {
+			doneEntries++
+		}
+	}
+
+	t.setOutputs()
+
+	if failedEntries > 0 {
+		return false, fmt.Errorf("%v queue entries failed", failedEntries)
+	}
+
+	return doneEntries == len(t.entries), nil
+}
+
+// findEntry returns the position of the first queue item that matches the entry, or -1 if the entry is not queued.
+func (t *Task) findEntry(entry *QueueEntry, state *spec.VersionedBeaconState) int {
+	electraState := state.Electra
+
+	switch entry.Queue {
+	case queueDeposits:
+		for idx, deposit := range electraState.PendingDeposits {
+			if bytes.Equal(deposit.Pubkey[:], entry.pubkey[:]) {
+				return idx
+			}
+		}
+	case queueConsolidations:
+		if entry.ValidatorIndex == nil {
+			return -1
+		}
+
+		for idx, consolidation := range electraState.PendingConsolidations {
+			if uint64(consolidation.SourceIndex) == *entry.ValidatorIndex {
+				return idx
+			}
+		}
+	case queuePartialWithdrawals:
+		if entry.ValidatorIndex == nil {
+			return -1
+		}
+
+		for idx, withdrawal := range electraState.PendingPartialWithdrawals {
+			if uint64(withdrawal.ValidatorIndex) == *entry.ValidatorIndex {
+				return idx
+			}
+		}
+	}
+
+	return -1
+}
+
+func (t *Task) updateEntry(entry *QueueEntry, state *spec.VersionedBeaconState, position int, epoch uint64, churnLimits *ChurnLimits) {
+	electraState := state.Electra
+
+	if position < 0 {
+		entry.Position = -1
+
+		switch entry.Status {
+		case statusQueued:
+			entry.Status = statusProcessed
+			entry.ProcessedEpoch = &epoch
+
+			t.logger.Infof("%v %v left the queue in epoch %v", entry.Queue, entry.Key, epoch)
+
+			if entry.Queue == queueDeposits && !hasValidator(state, entry.pubkey) {
+				entry.Errors = append(entry.Errors, "deposit left the queue, but no validator with this pubkey exists")
+			}
+		case statusWaiting:
+			if epoch > *t.startEpoch+t.config.MaxAppearEpochs {
+				entry.Errors = append(entry.Errors, fmt.Sprintf("did not appear in the queue within %v epochs", t.config.MaxAppearEpochs))
+			}
+		}
+
+		return
+	}
+
+	var expectedEpoch uint64
+
+	switch entry.Queue {
+	case queueDeposits:
+		expectedEpoch = estimateDepositEpoch(electraState.PendingDeposits, position, epoch, uint64(electraState.FinalizedCheckpoint.Epoch),
+			uint64(electraState.DepositBalanceToConsume), churnLimits.ActivationExitChurn, t.queueSpec)
+	case queueConsolidations:
+		expectedEpoch = estimateConsolidationEpoch(electraState.PendingConsolidations, position, electraState.Validators)
+	case queuePartialWithdrawals:
+		expectedEpoch = estimatePartialWithdrawalEpoch(electraState.PendingPartialWithdrawals, position, t.queueSpec)
+	}
+
+	entry.ExpectedEpoch = &expectedEpoch
+
+	if entry.Status == statusWaiting {
+		// the deadline is fixed to the estimation at the time the entry first appeared
+		deadlineEpoch := expectedEpoch + t.config.EpochTolerance
+		entry.Status = statusQueued
+		entry.FirstSeenEpoch = &epoch
+		entry.DeadlineEpoch = &deadlineEpoch
+
+		t.logger.Infof("%v %v appeared in the queue at position %v in epoch %v, expected to be processed in epoch %v", entry.Queue, entry.Key, position, epoch, expectedEpoch)
+	} else if entry.Queue != queueDeposits && position > entry.lastIndex {
+		// postponed deposits are re-appended to the queue, so only the other queues must strictly advance
+		entry.Errors = append(entry.Errors, fmt.Sprintf("queue position increased from %v to %v", entry.lastIndex, position))
+	}
+
+	entry.Position = position
+	entry.lastIndex = position
+
+	if epoch > *entry.DeadlineEpoch {
+		entry.Errors = append(entry.Errors, fmt.Sprintf("still queued at position %v in epoch %v, expected to be processed in epoch %v", position, epoch, *entry.DeadlineEpoch-t.config.EpochTolerance))
+	}
+}
+
+func hasValidator(state *spec.VersionedBeaconState, pubkey *phase0.BLSPubKey) bool {
+	for _, validator := range state.Electra.Validators {
+		if bytes.Equal(validator.PublicKey[:], pubkey[:]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *Task) getStateClient(ctx context.Context) *consensus.Client {
+	if t.config.ClientPattern != "" {
+		for _, client := range t.ctx.Scheduler.GetServices().ClientPool().GetClientsByNamePatterns(t.config.ClientPattern, "") {
+			if client.ConsensusClient != nil {
+				return client.ConsensusClient
+			}
+		}
+
+		return nil
+	}
+
+	return t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().AwaitReadyEndpoint(ctx, consensus.AnyClient)
+}
+
+func (t *Task) setOutputs() {
+	if statsData, err := vars.GeneralizeData(t.stats); err == nil {
+		t.ctx.Outputs.SetVar("queues", statsData)
+	} else {
+		t.logger.Warnf("failed setting `queues` output: %v", err)
+	}
+
+	if entriesData, err := vars.GeneralizeData(t.entries); err == nil {
+		t.ctx.Outputs.SetVar("entries", entriesData)
+	} else {
+		t.logger.Warnf("failed setting `entries` output: %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package checkconsensusstateconsistency
+
+import (
+	"errors"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+)
+
+type Config struct {
+	ClientPattern        string          `yaml:"clientPattern" json:"clientPattern"`
+	ExcludeClientPattern string          `yaml:"excludeClientPattern" json:"excludeClientPattern"`
+	Slots                []uint64        `yaml:"slots" json:"slots"`
+	EveryEpoch           bool            `yaml:"everyEpoch" json:"everyEpoch"`
+	CheckDelay           helper.Duration `yaml:"checkDelay" json:"checkDelay"`
+	CheckCount           uint64          `yaml:"checkCount" json:"checkCount"`
+	MinClientCount       int             `yaml:"minClientCount" json:"minClientCount"`
+	RequestTimeout       helper.Duration `yaml:"requestTimeout" json:"requestTimeout"`
+	MaxDiffEntries       int             `yaml:"maxDiffEntries" json:"maxDiffEntries"`
+	VerifyStateRoot      bool            `yaml:"verifyStateRoot" json:"verifyStateRoot"`
+	FailOnMismatch       bool            `yaml:"failOnMismatch" json:"failOnMismatch"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		EveryEpoch:      true,
+		CheckDelay:      helper.Duration{Duration: 4 * time.Second},
+		MinClientCount:  2,
+		RequestTimeout:  helper.Duration{Duration: 2 * time.Minute},
+		MaxDiffEntries:  100,
+		VerifyStateRoot: true,
+		FailOnMismatch:  true,
+	}
+}
+
+func (c *Config) Validate() error {
+	if len(c.Slots) == 0 && !c.EveryEpoch {
+		return errors.New("either slots or everyEpoch must be set")
+	}
+
+	if c.MaxDiffEntries < 0 {
+		return errors.New("maxDiffEntries must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package checkconsensusstateconsistency
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/attestantio/go-eth2-client/spec"
+)
+
+type FieldDiff struct {
+	Field           string       `json:"field"`
+	Value           interface{}  `json:"value,omitempty"`
+	ReferenceValue  interface{}  `json:"referenceValue,omitempty"`
+	Length          int          `json:"length,omitempty"`
+	ReferenceLength int          `json:"referenceLength,omitempty"`
+	DiffCount       int          `json:"diffCount,omitempty"`
+	Entries         []*EntryDiff `json:"entries,omitempty"`
+}
+
+type EntryDiff struct {
+	Index          int         `json:"index"`
+	Value          interface{} `json:"value"`
+	ReferenceValue interface{} `json:"referenceValue"`
+}
+
+// diffStates compares the top level fields of two beacon states and returns the fields that differ.
+// List fields (validators, balances, pending queues, ...) are compared element-wise, with at most
+// maxEntries differing elements being reported per field.
+func diffStates(state, reference *spec.VersionedBeaconState, maxEntries int) ([]*FieldDiff, error) {
+	if state.Version != reference.Version {
+		return []*FieldDiff{{
+			Field:          "version",
+			Value:          state.Version.String(),
+			ReferenceValue: reference.Version.String(),
+		}}, nil
+	}
+
+	stateValue, err := getStateStruct(state)
+	if err != nil {
+		return nil, err
+	}
+
+	referenceValue, err := getStateStruct(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := []*FieldDiff{}
+	stateType := stateValue.Type()
+
+	for i := 0; i < stateType.NumField(); i++ {
+		fieldType := stateType.Field(i)
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		field := stateValue.Field(i)
+		referenceField := referenceValue.Field(i)
+
+		if reflect.DeepEqual(field.Interface(), referenceField.Interface()) {
+			continue
+		}
+
+		fieldDiff := &FieldDiff{
+			Field: toSnakeCase(fieldType.Name),
+		}
+
+		if field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.Uint8 {
+			fieldDiff.Length = field.Len()
+			fieldDiff.ReferenceLength = referenceField.Len()
+			fieldDiff.Entries = []*EntryDiff{}
+
+			for idx := 0; idx < max(field.Len(), referenceField.Len()); idx++ {
+				var entry, referenceEntry interface{}
+
+				if idx < field.Len() {
+					entry = field.Index(idx).Interface()
+				}
+
+				if idx < referenceField.Len() {
+					referenceEntry = referenceField.Index(idx).Interface()
+				}
+
+				if reflect.DeepEqual(entry, referenceEntry) {
+					continue
+				}
+
+				fieldDiff.DiffCount++
+
+				if len(fieldDiff.Entries) < maxEntries {
+					fieldDiff.Entries = append(fieldDiff.Entries, &EntryDiff{
+						Index:          idx,
+						Value:          entry,
+						ReferenceValue: referenceEntry,
+					})
+				}
+			}
+		} else {
+			fieldDiff.Value = field.Interface()
+			fieldDiff.ReferenceValue = referenceField.Interface()
+		}
+
+		diffs = append(diffs, fieldDiff)
+	}
+
+	return diffs, nil
+}
+
+func getStateStruct(state *spec.VersionedBeaconState) (reflect.Value, error) {
+	var stateObj interface{}
+
+	switch state.Version {
+	case spec.DataVersionPhase0:
+		stateObj = state.Phase0
+	case spec.DataVersionAltair:
+		stateObj = state.Altair
+	case spec.DataVersionBellatrix:
+		stateObj = state.Bellatrix
+	case spec.DataVersionCapella:
+		stateObj = state.Capella
+	case spec.DataVersionDeneb:
+		stateObj = state.Deneb
+	case spec.DataVersionElectra:
+		stateObj = state.Electra
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported state version: %v", state.Version)
+	}
+
+	value := reflect.ValueOf(stateObj)
+	if value.IsNil() {
+		return reflect.Value{}, fmt.Errorf("no %v state", state.Version)
+	}
+
+	return value.Elem(), nil
+}
+
+// toSnakeCase converts go field names to the spec field names (eg. ETH1DataVotes -> eth1_data_votes).
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	result := &strings.Builder{}
+
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				result.WriteRune('_')
+			}
+		}
+
+		result.WriteRune(unicode.ToLower(r))
+	}
+
+	return result.String()
+}
@@ -0,0 +1,506 @@
+package checkconsensusstateconsistency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+	"github.com/erigontech/assertoor/pkg/coordinator/db"
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/erigontech/assertoor/pkg/coordinator/vars"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	TaskName       = "check_consensus_state_consistency"
+	TaskDescriptor = &types.TaskDescriptor{
+		Name:        TaskName,
+		Description: "Checks that all consensus clients compute the same beacon state at selected slots.",
+		Config:      DefaultConfig(),
+		NewTask:     NewTask,
+	}
+)
+
+type Task struct {
+	ctx           *types.TaskContext
+	options       *types.TaskOptions
+	config        Config
+	logger        logrus.FieldLogger
+	checks        []*SlotCheck
+	mismatchCount uint64
+}
+
+type SlotCheck struct {
+	Slot          uint64             `json:"slot"`
+	Consistent    bool               `json:"consistent"`
+	ReferenceRoot string             `json:"referenceRoot"`
+	ComputedRoot  string             `json:"computedRoot,omitempty"`
+	RootError     string             `json:"rootError,omitempty"`
+	Clients       []*ClientStateRoot `json:"clients"`
+	DiffFile      string             `json:"diffFile,omitempty"`
+}
+
+type ClientStateRoot struct {
+	Client    string `json:"client"`
+	StateRoot string `json:"stateRoot,omitempty"`
+	Matches   bool   `json:"matches"`
+	Error     string `json:"error,omitempty"`
+}
+
+type StateDiff struct {
+	Slot            uint64       `json:"slot"`
+	Client          string       `json:"client"`
+	StateRoot       string       `json:"stateRoot"`
+	ReferenceClient string       `json:"referenceClient"`
+	ReferenceRoot   string       `json:"referenceRoot"`
+	Error           string       `json:"error,omitempty"`
+	Fields          []*FieldDiff `json:"fields"`
+}
+
+func NewTask(ctx *types.TaskContext, options *types.TaskOptions) (types.Task, error) {
+	return &Task{
+		ctx:     ctx,
+		options: options,
+		logger:  ctx.Logger.GetLogger(),
+	}, nil
+}
+
+func (t *Task) Config() interface{} {
+	return t.config
+}
+
+func (t *Task) Timeout() time.Duration {
+	return t.options.Timeout.Duration
+}
+
+func (t *Task) LoadConfig() error {
+	config := DefaultConfig()
+
+	// parse static config
+	if t.options.Config != nil {
+		if err := t.options.Config.Unmarshal(&config); err != nil {
+			return fmt.Errorf("error parsing task config for %v: %w", TaskName, err)
+		}
+	}
+
+	// load dynamic vars
+	err := t.ctx.Vars.ConsumeVars(&config, t.options.ConfigVars)
+	if err != nil {
+		return err
+	}
+
+	// validate config
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	t.config = config
+
+	return nil
+}
+
+func (t *Task) Execute(ctx context.Context) error {
+	consensusPool := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool()
+	blockCache := consensusPool.GetBlockCache()
+
+	slotSubscription := blockCache.SubscribeWallclockSlotEvent(10)
+	defer slotSubscription.Unsubscribe()
+
+	pendingSlots := slices.Clone(t.config.Slots)
+	slices.Sort(pendingSlots)
+	pendingSlots = slices.Compact(pendingSlots)
+
+	currentSlot, _, err := blockCache.GetWallclock().Now()
+	if err != nil {
+		return fmt.Errorf("failed fetching wallclock: %w", err)
+	}
+
+	// check configured slots that are already in the past right away
+	for len(pendingSlots) > 0 && pendingSlots[0] < currentSlot.Number() {
+		done, err := t.processSlot(ctx, pendingSlots[0])
+		pendingSlots = pendingSlots[1:]
+
+		if done || err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case slot := <-slotSubscription.Channel():
+			if slot.Number() == 0 {
+				continue
+			}
+
+			targetSlot := slot.Number() - 1
+			isEpochStart := t.config.EveryEpoch && targetSlot%blockCache.GetSpecs().SlotsPerEpoch == 0
+			isPending := len(pendingSlots) > 0 && pendingSlots[0] == targetSlot
+
+			for len(pendingSlots) > 0 && pendingSlots[0] <= targetSlot {
+				pendingSlots = pendingSlots[1:]
+			}
+
+			if !isEpochStart && !isPending {
+				continue
+			}
+
+			// give clients some time to process the next slot before requesting the state
+			select {
+			case <-time.After(t.config.CheckDelay.Duration):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			done, err := t.processSlot(ctx, targetSlot)
+			if done || err != nil {
+				return err
+			}
+
+			if !t.config.EveryEpoch && len(pendingSlots) == 0 {
+				t.ctx.SetResult(types.TaskResultSuccess)
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// processSlot runs the consistency check for a single slot and returns true if the task is complete.
+func (t *Task) processSlot(ctx context.Context, slot uint64) (bool, error) {
+	check, err := t.runStateCheck(ctx, slot)
+	if err != nil {
+		t.logger.Warnf("check missed for slot %v: %v", slot, err)
+		return false, nil
+	}
+
+	t.checks = append(t.checks, check)
+
+	if !check.Consistent {
+		t.mismatchCount++
+	}
+
+	t.ctx.Outputs.SetVar("checkedSlots", len(t.checks))
+	t.ctx.Outputs.SetVar("mismatchCount", t.mismatchCount)
+
+	if checkData, err := vars.GeneralizeData(check); err == nil {
+		t.ctx.Outputs.SetVar("lastCheck", checkData)
+	} else {
+		t.logger.Warnf("failed setting `lastCheck` output: %v", err)
+	}
+
+	t.storeSummary()
+
+	if !check.Consistent && t.config.FailOnMismatch {
+		t.ctx.SetResult(types.TaskResultFailure)
+
+		if check.DiffFile == "" {
+			return true, fmt.Errorf("beacon state root mismatch at slot %v (reported: %v, computed: %v)", slot, check.ReferenceRoot, check.ComputedRoot)
+		}
+
+		return true, fmt.Errorf("beacon state mismatch at slot %v (see %v)", slot, check.DiffFile)
+	}
+
+	if t.config.CheckCount > 0 && uint64(len(t.checks)) >= t.config.CheckCount {
+		t.ctx.SetResult(types.TaskResultSuccess)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (t *Task) getClients() []*clients.PoolClient {
+	poolClients := []*clients.PoolClient{}
+
+	for _, client := range t.ctx.Scheduler.GetServices().ClientPool().GetClientsByNamePatterns(t.config.ClientPattern, t.config.ExcludeClientPattern) {
+		if client.ConsensusClient != nil {
+			poolClients = append(poolClients, client)
+		}
+	}
+
+	return poolClients
+}
+
+func (t *Task) runStateCheck(ctx context.Context, slot uint64) (*SlotCheck, error) {
+	poolClients := t.getClients()
+	check := &SlotCheck{
+		Slot:    slot,
+		Clients: make([]*ClientStateRoot, len(poolClients)),
+	}
+	stateRoots := make([]*phase0.Root, len(poolClients))
+
+	reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+	defer cancel()
+
+	wg := sync.WaitGroup{}
+
+	for idx, client := range poolClients {
+		wg.Add(1)
+
+		go func(idx int, client *clients.PoolClient) {
+			defer wg.Done()
+
+			clientRoot := &ClientStateRoot{
+				Client: client.Config.Name,
+			}
+			check.Clients[idx] = clientRoot
+
+			stateRoot, err := client.ConsensusClient.GetRPCClient().GetStateRoot(reqCtx, fmt.Sprintf("%v", slot))
+			if err != nil {
+				clientRoot.Error = err.Error()
+				return
+			}
+
+			clientRoot.StateRoot = stateRoot.String()
+			stateRoots[idx] = &stateRoot
+		}(idx, client)
+	}
+
+	wg.Wait()
+
+	// group clients by state root, the root returned by most clients is used as reference
+	rootGroups := map[phase0.Root][]int{}
+	referenceRoot := phase0.Root{}
+	rootCount := 0
+
+	for idx, stateRoot := range stateRoots {
+		if stateRoot == nil {
+			t.logger.Warnf("failed fetching state root for slot %v from %v: %v", slot, check.Clients[idx].Client, check.Clients[idx].Error)
+			continue
+		}
+
+		rootGroups[*stateRoot] = append(rootGroups[*stateRoot], idx)
+		rootCount++
+
+		if len(rootGroups[*stateRoot]) > len(rootGroups[referenceRoot]) {
+			referenceRoot = *stateRoot
+		}
+	}
+
+	if rootCount < max(t.config.MinClientCount, 1) {
+		return nil, fmt.Errorf("not enough state roots (have: %v, want: %v)", rootCount, max(t.config.MinClientCount, 1))
+	}
+
+	check.ReferenceRoot = referenceRoot.String()
+	check.Consistent = len(rootGroups) == 1
+
+	for idx, stateRoot := range stateRoots {
+		check.Clients[idx].Matches = stateRoot != nil && *stateRoot == referenceRoot
+	}
+
+	if check.Consistent {
+		if t.config.VerifyStateRoot {
+			t.verifyStateRoot(ctx, slot, poolClients[rootGroups[referenceRoot][0]], referenceRoot, check)
+		}
+
+		if check.Consistent {
+			t.logger.Infof("beacon state at slot %v is consistent across %v clients (root: %v)", slot, rootCount, check.ReferenceRoot)
+		}
+
+		return check, nil
+	}
+
+	t.logger.Errorf("beacon state mismatch at slot %v (reference root: %v)", slot, check.ReferenceRoot)
+
+	referenceClient := poolClients[rootGroups[referenceRoot][0]]
+	deviatingClients := []*clients.PoolClient{}
+
+	for idx, clientRoot := range check.Clients {
+		if clientRoot.StateRoot != "" && !clientRoot.Matches {
+			t.logger.Errorf("  client %v: %v", clientRoot.Client, clientRoot.StateRoot)
+			deviatingClients = append(deviatingClients, poolClients[idx])
+		}
+	}
+
+	check.DiffFile = fmt.Sprintf("state-diff-slot-%v.json", slot)
+	stateDiffs := t.buildStateDiffs(ctx, slot, referenceClient, deviatingClients, check)
+	t.storeStateDiffs(check.DiffFile, stateDiffs)
+
+	return check, nil
+}
+
+// verifyStateRoot recomputes the hash tree root of the reference state and marks the check as inconsistent if it differs from the reported root.
+// The root can only be recomputed for the mainnet preset, the ssz hashing of the beacon state types is bound to the mainnet list & vector sizes.
+func (t *Task) verifyStateRoot(ctx context.Context, slot uint64, referenceClient *clients.PoolClient, referenceRoot phase0.Root, check *SlotCheck) {
+	specs := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetBlockCache().GetSpecs()
+	if specs == nil || specs.PresetBase != "mainnet" {
+		check.RootError = "state root recomputation is only supported for the mainnet preset"
+		return
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+	defer cancel()
+
+	referenceState, err := referenceClient.ConsensusClient.GetRPCClient().GetState(reqCtx, fmt.Sprintf("%v", slot))
+	if err != nil {
+		check.RootError = fmt.Sprintf("failed fetching reference state: %v", err)
+		t.logger.Warnf("could not verify state root at slot %v: %v", slot, check.RootError)
+
+		return
+	}
+
+	computedRoot, err := referenceState.HashTreeRoot()
+	if err != nil {
+		check.RootError = fmt.Sprintf("failed computing state root: %v", err)
+		t.logger.Warnf("could not verify state root at slot %v: %v", slot, check.RootError)
+
+		return
+	}
+
+	check.ComputedRoot = phase0.Root(computedRoot).String()
+
+	if phase0.Root(computedRoot) != referenceRoot {
+		check.Consistent = false
+		t.logger.Errorf("beacon state root mismatch at slot %v: all clients report %v, but the state from %v hashes to %v", slot, check.ReferenceRoot, referenceClient.Config.Name, check.ComputedRoot)
+	}
+}
+
+func (t *Task) buildStateDiffs(ctx context.Context, slot uint64, referenceClient *clients.PoolClient, deviatingClients []*clients.PoolClient, check *SlotCheck) []*StateDiff {
+	reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+	defer cancel()
+
+	stateRef := fmt.Sprintf("%v", slot)
+
+	referenceState, err := referenceClient.ConsensusClient.GetRPCClient().GetState(reqCtx, stateRef)
+	if err != nil {
+		t.logger.Errorf("failed fetching reference state for slot %v from %v: %v", slot, referenceClient.Config.Name, err)
+	}
+
+	stateDiffs := make([]*StateDiff, len(deviatingClients))
+	wg := sync.WaitGroup{}
+
+	for idx, client := range deviatingClients {
+		stateDiff := &StateDiff{
+			Slot:            slot,
+			Client:          client.Config.Name,
+			ReferenceClient: referenceClient.Config.Name,
+			ReferenceRoot:   check.ReferenceRoot,
+		}
+		stateDiffs[idx] = stateDiff
+
+		for _, clientRoot := range check.Clients {
+			if clientRoot.Client == client.Config.Name {
+				stateDiff.StateRoot = clientRoot.StateRoot
+			}
+		}
+
+		if referenceState == nil {
+			stateDiff.Error = fmt.Sprintf("reference state unavailable: %v", err)
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(client *clients.PoolClient, stateDiff *StateDiff) {
+			defer wg.Done()
+
+			state, err := client.ConsensusClient.GetRPCClient().GetState(reqCtx, stateRef)
+			if err != nil {
+				stateDiff.Error = fmt.Sprintf("failed fetching state: %v", err)
+				return
+			}
+
+			fieldDiffs, err := diffStates(state, referenceState, t.config.MaxDiffEntries)
+			if err != nil {
+				stateDiff.Error = fmt.Sprintf("failed comparing states: %v", err)
+				return
+			}
+
+			stateDiff.Fields = fieldDiffs
+		}(client, stateDiff)
+	}
+
+	wg.Wait()
+
+	for _, stateDiff := range stateDiffs {
+		if stateDiff.Error != "" {
+			t.logger.Warnf("no state diff for %v at slot %v: %v", stateDiff.Client, slot, stateDiff.Error)
+			continue
+		}
+
+		fieldNames := make([]string, len(stateDiff.Fields))
+		for idx, fieldDiff := range stateDiff.Fields {
+			fieldNames[idx] = fieldDiff.Field
+		}
+
+		t.logger.Errorf("state of %v at slot %v differs in: %v", stateDiff.Client, slot, strings.Join(fieldNames, ", "))
+	}
+
+	return stateDiffs
+}
+
+func (t *Task) storeStateDiffs(fileName string, stateDiffs []*StateDiff) {
+	diffData, err := json.MarshalIndent(stateDiffs, "", "  ")
+	if err != nil {
+		t.logger.Errorf("failed serializing state diff: %v", err)
+		return
+	}
+
+	database := t.ctx.Scheduler.GetServices().Database()
+	if err := database.RunTransaction(func(tx *sqlx.Tx) error {
+		return database.UpsertTaskResult(tx, &db.TaskResult{
+			RunID:  t.ctx.Scheduler.GetTestRunID(),
+			TaskID: uint64(t.ctx.Index),
+			Type:   "result",
+			Index:  t.mismatchCount,
+			Name:   fileName,
+			Size:   uint64(len(diffData)),
+			Data:   diffData,
+		})
+	}); err != nil {
+		t.logger.Errorf("failed storing state diff to db: %v", err)
+	}
+}
+
+func (t *Task) storeSummary() {
+	summary := &strings.Builder{}
+
+	fmt.Fprintf(summary, "# Beacon state consistency\n\n| Slot | Result | Reference root | Deviating clients |\n|---|---|---|---|\n")
+
+	for _, check := range t.checks {
+		result := "consistent"
+		deviating := []string{}
+
+		for _, clientRoot := range check.Clients {
+			switch {
+			case clientRoot.Error != "":
+				deviating = append(deviating, fmt.Sprintf("%v (error)", clientRoot.Client))
+			case !clientRoot.Matches:
+				deviating = append(deviating, fmt.Sprintf("%v (`%v`)", clientRoot.Client, clientRoot.StateRoot))
+			}
+		}
+
+		switch {
+		case !check.Consistent && check.DiffFile == "":
+			result = fmt.Sprintf("root mismatch (computed `%v`)", check.ComputedRoot)
+		case !check.Consistent:
+			result = fmt.Sprintf("mismatch (%v)", check.DiffFile)
+		}
+
+		fmt.Fprintf(summary, "| %v | %v | `%v` | %v |\n", check.Slot, result, check.ReferenceRoot, strings.Join(deviating, ", "))
+	}
+
+	summaryData := []byte(summary.String())
+
+	database := t.ctx.Scheduler.GetServices().Database()
+	if err := database.RunTransaction(func(tx *sqlx.Tx) error {
+		return database.UpsertTaskResult(tx, &db.TaskResult{
+			RunID:  t.ctx.Scheduler.GetTestRunID(),
+			TaskID: uint64(t.ctx.Index),
+			Type:   "summary",
+			Index:  0,
+			Name:   "",
+			Size:   uint64(len(summaryData)),
+			Data:   summaryData,
+		})
+	}); err != nil {
+		t.logger.Errorf("failed storing state consistency summary to db: %v", err)
+	}
+}
@@ -1,5 +1,12 @@
 package checkconsensusvalidatorstatus
 
+import (
+	"fmt"
+	"strconv"
+
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
+)
+
 type Config struct {
 	ValidatorPubKey       string   `yaml:"validatorPubKey" json:"validatorPubKey"`
 	ValidatorNamePattern  string   `yaml:"validatorNamePattern" json:"validatorNamePattern"`
@@ -19,5 +26,12 @@ func DefaultConfig() Config {
 }
 
 func (c *Config) Validate() error {
+	for _, statusName := range c.ValidatorStatus {
+		var status v1.ValidatorState
+		if err := status.UnmarshalJSON([]byte(strconv.Quote(statusName))); err != nil || status.String() != statusName {
+			return fmt.Errorf("invalid validatorStatus: %v", statusName)
+		}
+	}
+
 	return nil
 }
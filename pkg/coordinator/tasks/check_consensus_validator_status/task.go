@@ -6,9 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"time"
 
+	v1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/erigontech/assertoor/pkg/coordinator/vars"
 	"github.com/ethereum/go-ethereum/common"
@@ -110,13 +114,12 @@ func (t *Task) runCheck() {
 }
 
 func (t *Task) runValidatorStatusCheck() bool {
-	validatorSet := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetValidatorSet()
-	if validatorSet == nil {
+	validatorRegistry := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetValidatorRegistry()
+	if validatorRegistry.GetValidatorCount() == 0 {
 		t.logger.Errorf("check failed: no validator set")
 		return false
 	}
 
-	currentIndex := uint64(0)
 	matchingValidators := uint64(0)
 	pubkey := []byte{}
 
@@ -136,14 +139,7 @@ func (t *Task) runValidatorStatusCheck() bool {
 		namePattern = pattern
 	}
 
-	for {
-		validator := validatorSet[phase0.ValidatorIndex(currentIndex)]
-		if validator == nil {
-			break
-		}
-
-		currentIndex++
-
+	for _, validator := range t.getCandidateValidators(validatorRegistry, pubkey) {
 		if t.config.ValidatorIndex != nil && uint64(validator.Index) != *t.config.ValidatorIndex {
 			continue
 		}
@@ -228,3 +224,46 @@ func (t *Task) runValidatorStatusCheck() bool {
 
 	return false
 }
+
+// getCandidateValidators uses the registry indexes to preselect the validators that may match the configured filters.
+func (t *Task) getCandidateValidators(validatorRegistry *consensus.ValidatorRegistry, pubkey []byte) []*v1.Validator {
+	switch {
+	case t.config.ValidatorIndex != nil:
+		validator := validatorRegistry.GetValidatorByIndex(phase0.ValidatorIndex(*t.config.ValidatorIndex))
+		if validator == nil {
+			return nil
+		}
+
+		return []*v1.Validator{validator}
+	case t.config.ValidatorPubKey != "":
+		if len(pubkey) != len(phase0.BLSPubKey{}) {
+			return nil
+		}
+
+		validator := validatorRegistry.GetValidatorByPubkey(phase0.BLSPubKey(pubkey))
+		if validator == nil {
+			return nil
+		}
+
+		return []*v1.Validator{validator}
+	case len(t.config.ValidatorStatus) > 0:
+		statuses := make([]v1.ValidatorState, 0, len(t.config.ValidatorStatus))
+
+		// status names are checked in Config.Validate
+		for _, statusName := range t.config.ValidatorStatus {
+			var status v1.ValidatorState
+			if err := status.UnmarshalJSON([]byte(strconv.Quote(statusName))); err == nil {
+				statuses = append(statuses, status)
+			}
+		}
+
+		validators := validatorRegistry.GetValidatorsByStatus(statuses...)
+		sort.Slice(validators, func(a, b int) bool {
+			return validators[a].Index < validators[b].Index
+		})
+
+		return validators
+	default:
+		return validatorRegistry.GetValidators()
+	}
+}
@@ -0,0 +1,26 @@
+package checkconsensuswithdrawalsweep
+
+import (
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+)
+
+type Config struct {
+	ClientPattern      string          `yaml:"clientPattern" json:"clientPattern"`
+	BlockCount         uint64          `yaml:"blockCount" json:"blockCount"`
+	MinWithdrawalCount uint64          `yaml:"minWithdrawalCount" json:"minWithdrawalCount"`
+	RequestTimeout     helper.Duration `yaml:"requestTimeout" json:"requestTimeout"`
+	FailOnMismatch     bool            `yaml:"failOnMismatch" json:"failOnMismatch"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		RequestTimeout: helper.Duration{Duration: 1 * time.Minute},
+		FailOnMismatch: true,
+	}
+}
+
+func (c *Config) Validate() error {
+	return nil
+}
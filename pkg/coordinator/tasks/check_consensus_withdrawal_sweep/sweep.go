@@ -0,0 +1,232 @@
+package checkconsensuswithdrawalsweep
+
+import (
+	"fmt"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus"
+)
+
+// sweepSpec holds the spec values used by the withdrawal sweep.
+type sweepSpec struct {
+	slotsPerEpoch              uint64
+	maxWithdrawalsPerPayload   uint64
+	maxValidatorsPerSweep      uint64
+	maxPendingPartialsPerSweep uint64
+	minActivationBalance       uint64
+	maxEffectiveBalance        uint64
+	maxEffectiveBalanceElectra uint64
+}
+
+func loadSweepSpec(specValues map[string]interface{}) *sweepSpec {
+	getValue := func(key string, defaultValue uint64) uint64 {
+		if value, isUint := specValues[key].(uint64); isUint {
+			return value
+		}
+
+		return defaultValue
+	}
+
+	return &sweepSpec{
+		slotsPerEpoch:              getValue("SLOTS_PER_EPOCH", 32),
+		maxWithdrawalsPerPayload:   getValue("MAX_WITHDRAWALS_PER_PAYLOAD", 16),
+		maxValidatorsPerSweep:      getValue("MAX_VALIDATORS_PER_WITHDRAWALS_SWEEP", 16384),
+		maxPendingPartialsPerSweep: getValue("MAX_PENDING_PARTIALS_PER_WITHDRAWALS_SWEEP", 8),
+		minActivationBalance:       getValue("MIN_ACTIVATION_BALANCE", 32000000000),
+		maxEffectiveBalance:        getValue("MAX_EFFECTIVE_BALANCE", 32000000000),
+		maxEffectiveBalanceElectra: getValue("MAX_EFFECTIVE_BALANCE_ELECTRA", 2048000000000),
+	}
+}
+
+// sweepState is the part of the beacon state that is relevant for the withdrawal sweep.
+type sweepState struct {
+	version                      spec.DataVersion
+	slot                         uint64
+	nextWithdrawalIndex          uint64
+	nextWithdrawalValidatorIndex uint64
+	validators                   []*phase0.Validator
+	balances                     []phase0.Gwei
+	pendingPartialWithdrawals    []*electra.PendingPartialWithdrawal
+}
+
+func newSweepState(state *spec.VersionedBeaconState) (*sweepState, error) {
+	sweep := &sweepState{
+		version: state.Version,
+	}
+
+	switch state.Version {
+	case spec.DataVersionCapella:
+		sweep.nextWithdrawalIndex = uint64(state.Capella.NextWithdrawalIndex)
+	case spec.DataVersionDeneb:
+		sweep.nextWithdrawalIndex = uint64(state.Deneb.NextWithdrawalIndex)
+	case spec.DataVersionElectra:
+		sweep.nextWithdrawalIndex = uint64(state.Electra.NextWithdrawalIndex)
+		sweep.pendingPartialWithdrawals = state.Electra.PendingPartialWithdrawals
+	default:
+		return nil, fmt.Errorf("unsupported state version: %v", state.Version)
+	}
+
+	slot, err := state.Slot()
+	if err != nil {
+		return nil, err
+	}
+
+	sweep.slot = uint64(slot)
+
+	nextValidatorIndex, err := state.NextWithdrawalValidatorIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	sweep.nextWithdrawalValidatorIndex = uint64(nextValidatorIndex)
+
+	sweep.validators, err = state.Validators()
+	if err != nil {
+		return nil, err
+	}
+
+	sweep.balances, err = state.ValidatorBalances()
+	if err != nil {
+		return nil, err
+	}
+
+	return sweep, nil
+}
+
+func (s *sweepState) isElectra() bool {
+	return s.version >= spec.DataVersionElectra
+}
+
+func (s *sweepState) hasExecutionWithdrawalCredential(validator *phase0.Validator) bool {
+	prefix := validator.WithdrawalCredentials[0]
+	return prefix == 0x01 || (s.isElectra() && prefix == 0x02)
+}
+
+func (s *sweepState) getMaxEffectiveBalance(validator *phase0.Validator, sweepSpec *sweepSpec) uint64 {
+	if !s.isElectra() {
+		return sweepSpec.maxEffectiveBalance
+	}
+
+	if validator.WithdrawalCredentials[0] == 0x02 {
+		return sweepSpec.maxEffectiveBalanceElectra
+	}
+
+	return sweepSpec.minActivationBalance
+}
+
+func getWithdrawalAddress(validator *phase0.Validator) [20]byte {
+	address, _ := consensus.GetWithdrawalAddress(validator.WithdrawalCredentials)
+	return address
+}
+
+// predictWithdrawals computes the withdrawals of a block at the given epoch from the pre-state (`get_expected_withdrawals`).
+// The state must not require an epoch transition to reach the block epoch.
+func (s *sweepState) predictWithdrawals(epoch uint64, sweepSpec *sweepSpec) []*capella.Withdrawal {
+	withdrawals := []*capella.Withdrawal{}
+	withdrawalIndex := s.nextWithdrawalIndex
+	validatorCount := uint64(len(s.validators))
+
+	if validatorCount == 0 {
+		return withdrawals
+	}
+
+	getWithdrawn := func(validatorIndex phase0.ValidatorIndex) uint64 {
+		withdrawn := uint64(0)
+
+		for _, withdrawal := range withdrawals {
+			if withdrawal.ValidatorIndex == validatorIndex {
+				withdrawn += uint64(withdrawal.Amount)
+			}
+		}
+
+		return withdrawn
+	}
+
+	// pending partial withdrawals (electra, eip-7002 / eip-7251)
+	for _, pendingWithdrawal := range s.pendingPartialWithdrawals {
+		if uint64(pendingWithdrawal.WithdrawableEpoch) > epoch || uint64(len(withdrawals)) == sweepSpec.maxPendingPartialsPerSweep {
+			break
+		}
+
+		validator := s.validators[pendingWithdrawal.ValidatorIndex]
+		balance := uint64(s.balances[pendingWithdrawal.ValidatorIndex]) - getWithdrawn(pendingWithdrawal.ValidatorIndex)
+
+		hasSufficientEffectiveBalance := uint64(validator.EffectiveBalance) >= sweepSpec.minActivationBalance
+		hasExcessBalance := balance > sweepSpec.minActivationBalance
+
+		if validator.ExitEpoch == consensus.FarFutureEpoch && hasSufficientEffectiveBalance && hasExcessBalance {
+			withdrawals = append(withdrawals, &capella.Withdrawal{
+				Index:          capella.WithdrawalIndex(withdrawalIndex),
+				ValidatorIndex: pendingWithdrawal.ValidatorIndex,
+				Address:        getWithdrawalAddress(validator),
+				Amount:         phase0.Gwei(min(balance-sweepSpec.minActivationBalance, uint64(pendingWithdrawal.Amount))),
+			})
+			withdrawalIndex++
+		}
+	}
+
+	// validator sweep
+	validatorIndex := s.nextWithdrawalValidatorIndex
+	bound := min(validatorCount, sweepSpec.maxValidatorsPerSweep)
+
+	for i := uint64(0); i < bound; i++ {
+		validator := s.validators[validatorIndex]
+		balance := uint64(s.balances[validatorIndex]) - getWithdrawn(phase0.ValidatorIndex(validatorIndex))
+		maxEffectiveBalance := s.getMaxEffectiveBalance(validator, sweepSpec)
+
+		var amount uint64
+
+		switch {
+		case !s.hasExecutionWithdrawalCredential(validator):
+		case uint64(validator.WithdrawableEpoch) <= epoch && balance > 0:
+			// fully withdrawable
+			amount = balance
+		case uint64(validator.EffectiveBalance) == maxEffectiveBalance && balance > maxEffectiveBalance:
+			// partially withdrawable
+			amount = balance - maxEffectiveBalance
+		}
+
+		if amount > 0 {
+			withdrawals = append(withdrawals, &capella.Withdrawal{
+				Index:          capella.WithdrawalIndex(withdrawalIndex),
+				ValidatorIndex: phase0.ValidatorIndex(validatorIndex),
+				Address:        getWithdrawalAddress(validator),
+				Amount:         phase0.Gwei(amount),
+			})
+			withdrawalIndex++
+		}
+
+		if uint64(len(withdrawals)) == sweepSpec.maxWithdrawalsPerPayload {
+			break
+		}
+
+		validatorIndex = (validatorIndex + 1) % validatorCount
+	}
+
+	return withdrawals
+}
+
+// getNextSweepPointers returns the expected next_withdrawal_index & next_withdrawal_validator_index after processing the given withdrawals.
+func (s *sweepState) getNextSweepPointers(withdrawals []*capella.Withdrawal, sweepSpec *sweepSpec) (nextWithdrawalIndex, nextValidatorIndex uint64) {
+	nextWithdrawalIndex = s.nextWithdrawalIndex
+	validatorCount := uint64(len(s.validators))
+
+	if len(withdrawals) > 0 {
+		nextWithdrawalIndex = uint64(withdrawals[len(withdrawals)-1].Index) + 1
+	}
+
+	if validatorCount == 0 {
+		return nextWithdrawalIndex, 0
+	}
+
+	if uint64(len(withdrawals)) == sweepSpec.maxWithdrawalsPerPayload {
+		nextValidatorIndex = (uint64(withdrawals[len(withdrawals)-1].ValidatorIndex) + 1) % validatorCount
+	} else {
+		nextValidatorIndex = (s.nextWithdrawalValidatorIndex + sweepSpec.maxValidatorsPerSweep) % validatorCount
+	}
+
+	return nextWithdrawalIndex, nextValidatorIndex
+}
@@ -0,0 +1,334 @@
+package checkconsensuswithdrawalsweep
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus"
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/erigontech/assertoor/pkg/coordinator/vars"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	TaskName       = "check_consensus_withdrawal_sweep"
+	TaskDescriptor = &types.TaskDescriptor{
+		Name:        TaskName,
+		Description: "Predicts the withdrawals of each block from the beacon state and compares them with the payload withdrawals.",
+		Config:      DefaultConfig(),
+		NewTask:     NewTask,
+	}
+)
+
+type Task struct {
+	ctx             *types.TaskContext
+	options         *types.TaskOptions
+	config          Config
+	logger          logrus.FieldLogger
+	sweepSpec       *sweepSpec
+	lastState       *sweepState
+	lastStateRoot   phase0.Root
+	checkedBlocks   uint64
+	withdrawalCount uint64
+	mismatches      []*BlockMismatch
+}
+
+type BlockMismatch struct {
+	Slot      uint64   `json:"slot"`
+	BlockRoot string   `json:"blockRoot"`
+	Errors    []string `json:"errors"`
+}
+
+type WithdrawalInfo struct {
+	Index          uint64 `json:"index"`
+	ValidatorIndex uint64 `json:"validatorIndex"`
+	Address        string `json:"address"`
+	Amount         uint64 `json:"amount"`
+}
+
+func NewTask(ctx *types.TaskContext, options *types.TaskOptions) (types.Task, error) {
+	return &Task{
+		ctx:     ctx,
+		options: options,
+		logger:  ctx.Logger.GetLogger(),
+	}, nil
+}
+
+func (t *Task) Config() interface{} {
+	return t.config
+}
+
+func (t *Task) Timeout() time.Duration {
+	return t.options.Timeout.Duration
+}
+
+func (t *Task) LoadConfig() error {
+	config := DefaultConfig()
+
+	// parse static config
+	if t.options.Config != nil {
+		if err := t.options.Config.Unmarshal(&config); err != nil {
+			return fmt.Errorf("error parsing task config for %v: %w", TaskName, err)
+		}
+	}
+
+	// load dynamic vars
+	err := t.ctx.Vars.ConsumeVars(&config, t.options.ConfigVars)
+	if err != nil {
+		return err
+	}
+
+	// validate config
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	t.config = config
+
+	return nil
+}
+
+func (t *Task) Execute(ctx context.Context) error {
+	blockCache := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetBlockCache()
+	t.sweepSpec = loadSweepSpec(blockCache.GetSpecValues())
+
+	blockSubscription := blockCache.SubscribeBlockEvent(32)
+	defer blockSubscription.Unsubscribe()
+
+	for {
+		select {
+		case block := <-blockSubscription.Channel():
+			blockErrors, err := t.checkBlock(ctx, block)
+			if err != nil {
+				t.logger.Warnf("could not check withdrawals of block %v [0x%x]: %v", block.Slot, block.Root, err)
+				continue
+			}
+
+			if blockErrors == nil {
+				// pre-capella block
+				continue
+			}
+
+			t.checkedBlocks++
+
+			if len(blockErrors) > 0 {
+				t.mismatches = append(t.mismatches, &BlockMismatch{
+					Slot:      uint64(block.Slot),
+					BlockRoot: block.Root.String(),
+					Errors:    blockErrors,
+				})
+
+				for _, err := range blockErrors {
+					t.logger.Errorf("withdrawal mismatch in block %v [0x%x]: %v", block.Slot, block.Root, err)
+				}
+			}
+
+			t.setOutputs()
+
+			if len(blockErrors) > 0 && t.config.FailOnMismatch {
+				t.ctx.SetResult(types.TaskResultFailure)
+				return fmt.Errorf("withdrawal mismatch in block %v", block.Slot)
+			}
+
+			if t.config.BlockCount > 0 && t.checkedBlocks >= t.config.BlockCount && t.withdrawalCount >= t.config.MinWithdrawalCount {
+				if len(t.mismatches) > 0 {
+					t.ctx.SetResult(types.TaskResultFailure)
+					return fmt.Errorf("%v blocks with withdrawal mismatches", len(t.mismatches))
+				}
+
+				t.ctx.SetResult(types.TaskResultSuccess)
+
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// checkBlock compares the payload withdrawals of a block with the withdrawals predicted from its parent state.
+// Returns nil for blocks without withdrawals support.
+func (t *Task) checkBlock(ctx context.Context, block *consensus.Block) ([]string, error) {
+	blockData := block.AwaitBlock(ctx, 2*time.Second)
+	if blockData == nil {
+		return nil, fmt.Errorf("block not available")
+	}
+
+	if blockData.Version < spec.DataVersionCapella {
+		return nil, nil
+	}
+
+	withdrawals, err := blockData.Withdrawals()
+	if err != nil {
+		return nil, err
+	}
+
+	parentRoot, err := blockData.ParentRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	stateRoot, err := blockData.StateRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	parentState, err := t.getParentState(ctx, parentRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading parent state: %w", err)
+	}
+
+	blockErrors := []string{}
+	blockEpoch := uint64(block.Slot) / t.sweepSpec.slotsPerEpoch
+
+	sameEpoch := parentState.slot/t.sweepSpec.slotsPerEpoch == blockEpoch
+	if sameEpoch {
+		predicted := parentState.predictWithdrawals(blockEpoch, t.sweepSpec)
+		blockErrors = append(blockErrors, compareWithdrawals(predicted, withdrawals)...)
+	} else {
+		// the epoch transition changes balances & validator states, so only the sweep structure can be checked
+		for idx, withdrawal := range withdrawals {
+			if uint64(withdrawal.Index) != parentState.nextWithdrawalIndex+uint64(idx) {
+				blockErrors = append(blockErrors, fmt.Sprintf("withdrawal %v has index %v, expected %v", idx, withdrawal.Index, parentState.nextWithdrawalIndex+uint64(idx)))
+			}
+		}
+	}
+
+	// load the post state of this block, which is the parent state of the next one
+	postState, err := t.loadState(ctx, stateRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading post state: %w", err)
+	}
+
+	expectedWithdrawalIndex, expectedValidatorIndex := parentState.getNextSweepPointers(withdrawals, t.sweepSpec)
+
+	if postState.nextWithdrawalIndex != expectedWithdrawalIndex {
+		blockErrors = append(blockErrors, fmt.Sprintf("next_withdrawal_index is %v, expected %v", postState.nextWithdrawalIndex, expectedWithdrawalIndex))
+	}
+
+	// validators added by the epoch transition change the sweep modulus, skip the validator index check in that case
+	sameValidatorCount := len(postState.validators) == len(parentState.validators)
+	if (sameEpoch || sameValidatorCount) && postState.nextWithdrawalValidatorIndex != expectedValidatorIndex {
+		blockErrors = append(blockErrors, fmt.Sprintf("next_withdrawal_validator_index is %v, expected %v", postState.nextWithdrawalValidatorIndex, expectedValidatorIndex))
+	}
+
+	t.lastState = postState
+	t.lastStateRoot = block.Root
+	t.withdrawalCount += uint64(len(withdrawals))
+
+	return blockErrors, nil
+}
+
+func (t *Task) getParentState(ctx context.Context, parentRoot phase0.Root) (*sweepState, error) {
+	if t.lastState != nil && t.lastStateRoot == parentRoot {
+		return t.lastState, nil
+	}
+
+	blockCache := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetBlockCache()
+
+	parentBlock := blockCache.GetCachedBlockByRoot(parentRoot)
+	if parentBlock == nil {
+		return nil, fmt.Errorf("parent block 0x%x not found in cache", parentRoot)
+	}
+
+	parentHeader := parentBlock.AwaitHeader(ctx, 2*time.Second)
+	if parentHeader == nil {
+		return nil, fmt.Errorf("parent block header 0x%x not available", parentRoot)
+	}
+
+	return t.loadState(ctx, parentHeader.Message.StateRoot)
+}
+
+func (t *Task) loadState(ctx context.Context, stateRoot phase0.Root) (*sweepState, error) {
+	client := t.getStateClient(ctx)
+	if client == nil {
+		return nil, fmt.Errorf("no ready consensus client")
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+	defer cancel()
+
+	state, err := client.GetRPCClient().GetState(reqCtx, stateRoot.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return newSweepState(state)
+}
+
+func (t *Task) getStateClient(ctx context.Context) *consensus.Client {
+	if t.config.ClientPattern != "" {
+		for _, client := range t.ctx.Scheduler.GetServices().ClientPool().GetClientsByNamePatterns(t.config.ClientPattern, "") {
+			if client.ConsensusClient != nil {
+				return client.ConsensusClient
+			}
+		}
+
+		return nil
+	}
+
+	return t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().AwaitReadyEndpoint(ctx, consensus.AnyClient)
+}
+
+func compareWithdrawals(predicted, actual []*capella.Withdrawal) []string {
+	blockErrors := []string{}
+
+	if len(predicted) != len(actual) {
+		blockErrors = append(blockErrors, fmt.Sprintf("block contains %v withdrawals, expected %v", len(actual), len(predicted)))
+	}
+
+	for idx := 0; idx < min(len(predicted), len(actual)); idx++ {
+		expected := predicted[idx]
+		withdrawal := actual[idx]
+
+		if *expected != *withdrawal {
+			blockErrors = append(blockErrors, fmt.Sprintf("withdrawal %v is (index: %v, validator: %v, address: %v, amount: %v), expected (index: %v, validator: %v, address: %v, amount: %v)",
+				idx, withdrawal.Index, withdrawal.ValidatorIndex, withdrawal.Address.String(), withdrawal.Amount,
+				expected.Index, expected.ValidatorIndex, expected.Address.String(), expected.Amount))
+		}
+	}
+
+	return blockErrors
+}
+
+func (t *Task) setOutputs() {
+	t.ctx.Outputs.SetVar("checkedBlocks", t.checkedBlocks)
+	t.ctx.Outputs.SetVar("withdrawalCount", t.withdrawalCount)
+
+	if mismatchesData, err := vars.GeneralizeData(t.mismatches); err == nil {
+		t.ctx.Outputs.SetVar("mismatches", mismatchesData)
+	} else {
+		t.logger.Warnf("failed setting `mismatches` output: %v", err)
+	}
+
+	if t.lastState == nil {
+		return
+	}
+
+	t.ctx.Outputs.SetVar("nextWithdrawalIndex", t.lastState.nextWithdrawalIndex)
+	t.ctx.Outputs.SetVar("nextWithdrawalValidatorIndex", t.lastState.nextWithdrawalValidatorIndex)
+	t.ctx.Outputs.SetVar("pendingPartialWithdrawals", len(t.lastState.pendingPartialWithdrawals))
+
+	// withdrawals of the next block, assuming it is proposed within the same epoch
+	predicted := t.lastState.predictWithdrawals(t.lastState.slot/t.sweepSpec.slotsPerEpoch, t.sweepSpec)
+	expectedWithdrawals := make([]*WithdrawalInfo, len(predicted))
+
+	for idx, withdrawal := range predicted {
+		expectedWithdrawals[idx] = &WithdrawalInfo{
+			Index:          uint64(withdrawal.Index),
+			ValidatorIndex: uint64(withdrawal.ValidatorIndex),
+			Address:        withdrawal.Address.String(),
+			Amount:         uint64(withdrawal.Amount),
+		}
+	}
+
+	if withdrawalsData, err := vars.GeneralizeData(expectedWithdrawals); err == nil {
+		t.ctx.Outputs.SetVar("expectedWithdrawals", withdrawalsData)
+	} else {
+		t.logger.Warnf("failed setting `expectedWithdrawals` output: %v", err)
+	}
+}
@@ -0,0 +1,356 @@
+package checkexecutionblockintegrity
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/bellatrix"
+	"github.com/attestantio/go-eth2-client/spec/capella"
+	"github.com/attestantio/go-eth2-client/spec/electra"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// executionPayload holds the execution payload fields of a beacon block, independent of the fork version.
+type executionPayload struct {
+	parentHash    common.Hash
+	feeRecipient  common.Address
+	stateRoot     common.Hash
+	receiptsRoot  common.Hash
+	logsBloom     ethtypes.Bloom
+	prevRandao    common.Hash
+	blockNumber   uint64
+	gasLimit      uint64
+	gasUsed       uint64
+	timestamp     uint64
+	extraData     []byte
+	baseFeePerGas *big.Int
+	blockHash     common.Hash
+	transactions  []bellatrix.Transaction
+	withdrawals   []*capella.Withdrawal
+	blobGasUsed   *uint64
+	excessBlobGas *uint64
+}
+
+// getExecutionPayload extracts the execution payload of a beacon block. Returns nil for pre-bellatrix blocks.
+func getExecutionPayload(block *spec.VersionedSignedBeaconBlock) *executionPayload {
+	switch block.Version {
+	case spec.DataVersionBellatrix:
+		payload := block.Bellatrix.Message.Body.ExecutionPayload
+
+		return &executionPayload{
+			parentHash:    common.Hash(payload.ParentHash),
+			feeRecipient:  common.Address(payload.FeeRecipient),
+			stateRoot:     common.Hash(payload.StateRoot),
+			receiptsRoot:  common.Hash(payload.ReceiptsRoot),
+			logsBloom:     ethtypes.Bloom(payload.LogsBloom),
+			prevRandao:    common.Hash(payload.PrevRandao),
+			blockNumber:   payload.BlockNumber,
+			gasLimit:      payload.GasLimit,
+			gasUsed:       payload.GasUsed,
+			timestamp:     payload.Timestamp,
+			extraData:     payload.ExtraData,
+			baseFeePerGas: littleEndianToBig(payload.BaseFeePerGas),
+			blockHash:     common.Hash(payload.BlockHash),
+			transactions:  payload.Transactions,
+		}
+	case spec.DataVersionCapella:
+		payload := block.Capella.Message.Body.ExecutionPayload
+
+		return &executionPayload{
+			parentHash:    common.Hash(payload.ParentHash),
+			feeRecipient:  common.Address(payload.FeeRecipient),
+			stateRoot:     common.Hash(payload.StateRoot),
+			receiptsRoot:  common.Hash(payload.ReceiptsRoot),
+			logsBloom:     ethtypes.Bloom(payload.LogsBloom),
+			prevRandao:    common.Hash(payload.PrevRandao),
+			blockNumber:   payload.BlockNumber,
+			gasLimit:      payload.GasLimit,
+			gasUsed:       payload.GasUsed,
+			timestamp:     payload.Timestamp,
+			extraData:     payload.ExtraData,
+			baseFeePerGas: littleEndianToBig(payload.BaseFeePerGas),
+			blockHash:     common.Hash(payload.BlockHash),
+			transactions:  payload.Transactions,
+			withdrawals:   payload.Withdrawals,
+		}
+	case spec.DataVersionDeneb, spec.DataVersionElectra:
+		payload := block.Deneb.Message.Body.ExecutionPayload
+		if block.Version == spec.DataVersionElectra {
+			payload = block.Electra.Message.Body.ExecutionPayload
+		}
+
+		return &executionPayload{
+			parentHash:    common.Hash(payload.ParentHash),
+			feeRecipient:  common.Address(payload.FeeRecipient),
+			stateRoot:     common.Hash(payload.StateRoot),
+			receiptsRoot:  common.Hash(payload.ReceiptsRoot),
+			logsBloom:     ethtypes.Bloom(payload.LogsBloom),
+			prevRandao:    common.Hash(payload.PrevRandao),
+			blockNumber:   payload.BlockNumber,
+			gasLimit:      payload.GasLimit,
+			gasUsed:       payload.GasUsed,
+			timestamp:     payload.Timestamp,
+			extraData:     payload.ExtraData,
+			baseFeePerGas: payload.BaseFeePerGas.ToBig(),
+			blockHash:     common.Hash(payload.BlockHash),
+			transactions:  payload.Transactions,
+			withdrawals:   payload.Withdrawals,
+			blobGasUsed:   &payload.BlobGasUsed,
+			excessBlobGas: &payload.ExcessBlobGas,
+		}
+	}
+
+	return nil
+}
+
+func littleEndianToBig(value [32]byte) *big.Int {
+	bigEndian := value
+	slices.Reverse(bigEndian[:])
+
+	return new(big.Int).SetBytes(bigEndian[:])
+}
+
+// checkBlockRoots recomputes the transactions root, withdrawals root, receipts root and logs bloom of an execution block.
+// The receipts checks are skipped if no receipts are given.
+func checkBlockRoots(block *ethtypes.Block, receipts []*ethtypes.Receipt) []string {
+	header := block.Header()
+	blockErrors := []string{}
+
+	if txRoot := ethtypes.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)); txRoot != header.TxHash {
+		blockErrors = append(blockErrors, fmt.Sprintf("transactions root is %v, computed %v", header.TxHash.String(), txRoot.String()))
+	}
+
+	if header.WithdrawalsHash != nil {
+		withdrawalsRoot := ethtypes.DeriveSha(block.Withdrawals(), trie.NewStackTrie(nil))
+		if withdrawalsRoot != *header.WithdrawalsHash {
+			blockErrors = append(blockErrors, fmt.Sprintf("withdrawals root is %v, computed %v", header.WithdrawalsHash.String(), withdrawalsRoot.String()))
+		}
+	}
+
+	if receipts == nil {
+		return blockErrors
+	}
+
+	if len(receipts) != len(block.Transactions()) {
+		return append(blockErrors, fmt.Sprintf("got %v receipts for %v transactions", len(receipts), len(block.Transactions())))
+	}
+
+	if receiptsRoot := ethtypes.DeriveSha(ethtypes.Receipts(receipts), trie.NewStackTrie(nil)); receiptsRoot != header.ReceiptHash {
+		blockErrors = append(blockErrors, fmt.Sprintf("receipts root is %v, computed %v", header.ReceiptHash.String(), receiptsRoot.String()))
+	}
+
+	logsBloom := ethtypes.Bloom{}
+
+	for idx, receipt := range receipts {
+		receiptBloom := ethtypes.CreateBloom(receipt)
+		if receiptBloom != receipt.Bloom {
+			blockErrors = append(blockErrors, fmt.Sprintf("logs bloom of receipt %v does not match its logs", idx))
+		}
+
+		for i := range logsBloom {
+			logsBloom[i] |= receiptBloom[i]
+		}
+	}
+
+	if logsBloom != header.Bloom {
+		blockErrors = append(blockErrors, "logs bloom does not match the receipt logs")
+	}
+
+	if len(receipts) > 0 && receipts[len(receipts)-1].CumulativeGasUsed != header.GasUsed {
+		blockErrors = append(blockErrors, fmt.Sprintf("gas used is %v, receipts used %v", header.GasUsed, receipts[len(receipts)-1].CumulativeGasUsed))
+	}
+
+	return blockErrors
+}
+
+// checkPayloadFields compares the execution payload of a beacon block with the corresponding execution block.
+func checkPayloadFields(beaconBlock *spec.VersionedSignedBeaconBlock, payload *executionPayload, block *ethtypes.Block) []string {
+	header := block.Header()
+	blockErrors := []string{}
+
+	addMismatch := func(field string, payloadValue, blockValue interface{}) {
+		blockErrors = append(blockErrors, fmt.Sprintf("%v mismatch: payload %v, block %v", field, payloadValue, blockValue))
+	}
+
+	if block.Hash() != payload.blockHash {
+		addMismatch("block_hash", payload.blockHash.String(), block.Hash().String())
+	}
+
+	if header.ParentHash != payload.parentHash {
+		addMismatch("parent_hash", payload.parentHash.String(), header.ParentHash.String())
+	}
+
+	if header.Coinbase != payload.feeRecipient {
+		addMismatch("fee_recipient", payload.feeRecipient.String(), header.Coinbase.String())
+	}
+
+	if header.Root != payload.stateRoot {
+		addMismatch("state_root", payload.stateRoot.String(), header.Root.String())
+	}
+
+	if header.ReceiptHash != payload.receiptsRoot {
+		addMismatch("receipts_root", payload.receiptsRoot.String(), header.ReceiptHash.String())
+	}
+
+	if header.Bloom != payload.logsBloom {
+		addMismatch("logs_bloom", common.Bytes2Hex(payload.logsBloom[:]), common.Bytes2Hex(header.Bloom[:]))
+	}
+
+	if header.MixDigest != payload.prevRandao {
+		addMismatch("prev_randao", payload.prevRandao.String(), header.MixDigest.String())
+	}
+
+	if header.Number.Uint64() != payload.blockNumber {
+		addMismatch("block_number", payload.blockNumber, header.Number.Uint64())
+	}
+
+	if header.GasLimit != payload.gasLimit {
+		addMismatch("gas_limit", payload.gasLimit, header.GasLimit)
+	}
+
+	if header.GasUsed != payload.gasUsed {
+		addMismatch("gas_used", payload.gasUsed, header.GasUsed)
+	}
+
+	if header.Time != payload.timestamp {
+		addMismatch("timestamp", payload.timestamp, header.Time)
+	}
+
+	if !bytes.Equal(header.Extra, payload.extraData) {
+		addMismatch("extra_data", common.Bytes2Hex(payload.extraData), common.Bytes2Hex(header.Extra))
+	}
+
+	if header.BaseFee == nil || header.BaseFee.Cmp(payload.baseFeePerGas) != 0 {
+		addMismatch("base_fee_per_gas", payload.baseFeePerGas, header.BaseFee)
+	}
+
+	blockErrors = append(blockErrors, checkPayloadTransactions(payload.transactions, block.Transactions())...)
+
+	if payload.withdrawals != nil {
+		blockErrors = append(blockErrors, checkPayloadWithdrawals(payload.withdrawals, block.Withdrawals())...)
+	}
+
+	if payload.blobGasUsed != nil {
+		if header.BlobGasUsed == nil || *header.BlobGasUsed != *payload.blobGasUsed {
+			addMismatch("blob_gas_used", *payload.blobGasUsed, formatOptional(header.BlobGasUsed))
+		}
+
+		if header.ExcessBlobGas == nil || *header.ExcessBlobGas != *payload.excessBlobGas {
+			addMismatch("excess_blob_gas", *payload.excessBlobGas, formatOptional(header.ExcessBlobGas))
+		}
+
+		parentRoot, err := beaconBlock.ParentRoot()
+		if err == nil && (header.ParentBeaconRoot == nil || *header.ParentBeaconRoot != common.Hash(parentRoot)) {
+			addMismatch("parent_beacon_block_root", parentRoot.String(), formatOptional(header.ParentBeaconRoot))
+		}
+	}
+
+	if beaconBlock.Version >= spec.DataVersionElectra {
+		executionRequests, err := beaconBlock.ExecutionRequests()
+		if err != nil {
+			blockErrors = append(blockErrors, fmt.Sprintf("failed getting execution requests: %v", err))
+		} else if requestsHash, err := calcRequestsHash(executionRequests); err != nil {
+			blockErrors = append(blockErrors, fmt.Sprintf("failed encoding execution requests: %v", err))
+		} else if header.RequestsHash == nil || *header.RequestsHash != requestsHash {
+			addMismatch("requests_hash", requestsHash.String(), formatOptional(header.RequestsHash))
+		}
+	}
+
+	return blockErrors
+}
+
+func checkPayloadTransactions(payloadTxs []bellatrix.Transaction, blockTxs ethtypes.Transactions) []string {
+	if len(payloadTxs) != len(blockTxs) {
+		return []string{fmt.Sprintf("transaction count mismatch: payload %v, block %v", len(payloadTxs), len(blockTxs))}
+	}
+
+	blockErrors := []string{}
+
+	for idx, payloadTx := range payloadTxs {
+		// the hash of the opaque transaction bytes equals the transaction hash for legacy & typed transactions
+		if txHash := crypto.Keccak256Hash(payloadTx); txHash != blockTxs[idx].Hash() {
+			blockErrors = append(blockErrors, fmt.Sprintf("transaction %v mismatch: payload %v, block %v", idx, txHash.String(), blockTxs[idx].Hash().String()))
+		}
+	}
+
+	return blockErrors
+}
+
+func checkPayloadWithdrawals(payloadWithdrawals []*capella.Withdrawal, blockWithdrawals ethtypes.Withdrawals) []string {
+	if len(payloadWithdrawals) != len(blockWithdrawals) {
+		return []string{fmt.Sprintf("withdrawal count mismatch: payload %v, block %v", len(payloadWithdrawals), len(blockWithdrawals))}
+	}
+
+	blockErrors := []string{}
+
+	for idx, payloadWithdrawal := range payloadWithdrawals {
+		blockWithdrawal := blockWithdrawals[idx]
+
+		if uint64(payloadWithdrawal.Index) != blockWithdrawal.Index ||
+			uint64(payloadWithdrawal.ValidatorIndex) != blockWithdrawal.Validator ||
+			common.Address(payloadWithdrawal.Address) != blockWithdrawal.Address ||
+			uint64(payloadWithdrawal.Amount) != blockWithdrawal.Amount {
+			blockErrors = append(blockErrors, fmt.Sprintf("withdrawal %v mismatch: payload (index: %v, validator: %v, address: %v, amount: %v), block (index: %v, validator: %v, address: %v, amount: %v)",
+				idx, payloadWithdrawal.Index, payloadWithdrawal.ValidatorIndex, payloadWithdrawal.Address.String(), payloadWithdrawal.Amount,
+				blockWithdrawal.Index, blockWithdrawal.Validator, blockWithdrawal.Address.String(), blockWithdrawal.Amount))
+		}
+	}
+
+	return blockErrors
+}
+
+// calcRequestsHash computes the EIP-7685 requests hash from the execution requests of a beacon block.
+func calcRequestsHash(executionRequests *electra.ExecutionRequests) (common.Hash, error) {
+	requests := make([][]byte, 0, 3)
+
+	depositRequests := []byte{0x00}
+
+	for _, request := range executionRequests.Deposits {
+		requestData, err := request.MarshalSSZ()
+		if err != nil {
+			return common.Hash{}, err
+		}
+
+		depositRequests = append(depositRequests, requestData...)
+	}
+
+	withdrawalRequests := []byte{0x01}
+
+	for _, request := range executionRequests.Withdrawals {
+		requestData, err := request.MarshalSSZ()
+		if err != nil {
+			return common.Hash{}, err
+		}
+
+		withdrawalRequests = append(withdrawalRequests, requestData...)
+	}
+
+	consolidationRequests := []byte{0x02}
+
+	for _, request := range executionRequests.Consolidations {
+		requestData, err := request.MarshalSSZ()
+		if err != nil {
+			return common.Hash{}, err
+		}
+
+		consolidationRequests = append(consolidationRequests, requestData...)
+	}
+
+	requests = append(requests, depositRequests, withdrawalRequests, consolidationRequests)
+
+	return ethtypes.CalcRequestsHash(requests), nil
+}
+
+func formatOptional[T any](value *T) interface{} {
+	if value == nil {
+		return "<nil>"
+	}
+
+	return *value
+}
@@ -0,0 +1,26 @@
+package checkexecutionblockintegrity
+
+import (
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+)
+
+type Config struct {
+	BlockCount     uint64          `yaml:"blockCount" json:"blockCount"`
+	CheckReceipts  bool            `yaml:"checkReceipts" json:"checkReceipts"`
+	RequestTimeout helper.Duration `yaml:"requestTimeout" json:"requestTimeout"`
+	FailOnMismatch bool            `yaml:"failOnMismatch" json:"failOnMismatch"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		CheckReceipts:  true,
+		RequestTimeout: helper.Duration{Duration: 30 * time.Second},
+		FailOnMismatch: true,
+	}
+}
+
+func (c *Config) Validate() error {
+	return nil
+}
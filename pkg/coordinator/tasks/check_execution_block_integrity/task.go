@@ -0,0 +1,264 @@
+package checkexecutionblockintegrity
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/execution"
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/erigontech/assertoor/pkg/coordinator/vars"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	TaskName       = "check_execution_block_integrity"
+	TaskDescriptor = &types.TaskDescriptor{
+		Name:        TaskName,
+		Description: "Recomputes the roots of every new execution block and checks the beacon block execution payloads against the execution blocks.",
+		Config:      DefaultConfig(),
+		NewTask:     NewTask,
+	}
+)
+
+type Task struct {
+	ctx                    *types.TaskContext
+	options                *types.TaskOptions
+	config                 Config
+	logger                 logrus.FieldLogger
+	checkedExecutionBlocks uint64
+	checkedConsensusBlocks uint64
+	mismatches             []*BlockMismatch
+}
+
+type BlockMismatch struct {
+	Layer  string   `json:"layer"`
+	Number uint64   `json:"number"`
+	Hash   string   `json:"hash"`
+	Errors []string `json:"errors"`
+}
+
+func NewTask(ctx *types.TaskContext, options *types.TaskOptions) (types.Task, error) {
+	return &Task{
+		ctx:     ctx,
+		options: options,
+		logger:  ctx.Logger.GetLogger(),
+	}, nil
+}
+
+func (t *Task) Config() interface{} {
+	return t.config
+}
+
+func (t *Task) Timeout() time.Duration {
+	return t.options.Timeout.Duration
+}
+
+func (t *Task) LoadConfig() error {
+	config := DefaultConfig()
+
+	// parse static config
+	if t.options.Config != nil {
+		if err := t.options.Config.Unmarshal(&config); err != nil {
+			return fmt.Errorf("error parsing task config for %v: %w", TaskName, err)
+		}
+	}
+
+	// load dynamic vars
+	err := t.ctx.Vars.ConsumeVars(&config, t.options.ConfigVars)
+	if err != nil {
+		return err
+	}
+
+	// validate config
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	t.config = config
+
+	return nil
+}
+
+func (t *Task) Execute(ctx context.Context) error {
+	clientPool := t.ctx.Scheduler.GetServices().ClientPool()
+
+	executionSubscription := clientPool.GetExecutionPool().GetBlockCache().SubscribeBlockEvent(32)
+	defer executionSubscription.Unsubscribe()
+
+	consensusSubscription := clientPool.GetConsensusPool().GetBlockCache().SubscribeBlockEvent(32)
+	defer consensusSubscription.Unsubscribe()
+
+	for {
+		var mismatch *BlockMismatch
+
+		select {
+		case block := <-executionSubscription.Channel():
+			blockErrors, err := t.checkExecutionBlock(ctx, block)
+			if err != nil {
+				t.logger.Warnf("could not check execution block %v [%v]: %v", block.Number, block.Hash.String(), err)
+				continue
+			}
+
+			t.checkedExecutionBlocks++
+
+			if len(blockErrors) > 0 {
+				mismatch = &BlockMismatch{
+					Layer:  "execution",
+					Number: block.Number,
+					Hash:   block.Hash.String(),
+					Errors: blockErrors,
+				}
+			}
+		case block := <-consensusSubscription.Channel():
+			blockErrors, err := t.checkConsensusBlock(ctx, block)
+			if err != nil {
+				t.logger.Warnf("could not check consensus block %v [0x%x]: %v", block.Slot, block.Root, err)
+				continue
+			}
+
+			if blockErrors == nil {
+				// pre-merge block
+				continue
+			}
+
+			t.checkedConsensusBlocks++
+
+			if len(blockErrors) > 0 {
+				mismatch = &BlockMismatch{
+					Layer:  "consensus",
+					Number: uint64(block.Slot),
+					Hash:   block.Root.String(),
+					Errors: blockErrors,
+				}
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		if mismatch != nil {
+			t.mismatches = append(t.mismatches, mismatch)
+
+			for _, err := range mismatch.Errors {
+				t.logger.Errorf("invalid %v block %v [%v]: %v", mismatch.Layer, mismatch.Number, mismatch.Hash, err)
+			}
+		}
+
+		t.setOutputs()
+
+		if mismatch != nil && t.config.FailOnMismatch {
+			t.ctx.SetResult(types.TaskResultFailure)
+			return fmt.Errorf("invalid %v block %v", mismatch.Layer, mismatch.Number)
+		}
+
+		if t.config.BlockCount > 0 && t.checkedExecutionBlocks >= t.config.BlockCount && t.checkedConsensusBlocks >= t.config.BlockCount {
+			if len(t.mismatches) > 0 {
+				t.ctx.SetResult(types.TaskResultFailure)
+				return fmt.Errorf("%v invalid blocks", len(t.mismatches))
+			}
+
+			t.ctx.SetResult(types.TaskResultSuccess)
+
+			return nil
+		}
+	}
+}
+
+func (t *Task) checkExecutionBlock(ctx context.Context, block *execution.Block) ([]string, error) {
+	blockData := block.AwaitBlock(ctx, 2*time.Second)
+	if blockData == nil {
+		return nil, fmt.Errorf("block not available")
+	}
+
+	var receipts []*ethtypes.Receipt
+
+	if t.config.CheckReceipts {
+		client := t.getExecutionClient(ctx, block)
+		if client == nil {
+			return nil, fmt.Errorf("no ready execution client")
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+		defer cancel()
+
+		blockReceipts, err := client.GetRPCClient().GetBlockReceipts(reqCtx, block.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed loading receipts from %v: %w", client.GetName(), err)
+		}
+
+		receipts = blockReceipts
+		if receipts == nil {
+			receipts = []*ethtypes.Receipt{}
+		}
+	}
+
+	return checkBlockRoots(blockData, receipts), nil
+}
+
+// checkConsensusBlock compares the execution payload of a beacon block with the execution block.
+// Returns nil for blocks without execution payload.
+func (t *Task) checkConsensusBlock(ctx context.Context, block *consensus.Block) ([]string, error) {
+	blockData := block.AwaitBlock(ctx, 2*time.Second)
+	if blockData == nil {
+		return nil, fmt.Errorf("block not available")
+	}
+
+	payload := getExecutionPayload(blockData)
+	if payload == nil || payload.blockHash == (common.Hash{}) {
+		return nil, nil
+	}
+
+	executionBlock, err := t.getExecutionBlock(ctx, payload.blockHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading execution block %v: %w", payload.blockHash.String(), err)
+	}
+
+	return checkPayloadFields(blockData, payload, executionBlock), nil
+}
+
+func (t *Task) getExecutionBlock(ctx context.Context, blockHash common.Hash) (*ethtypes.Block, error) {
+	executionPool := t.ctx.Scheduler.GetServices().ClientPool().GetExecutionPool()
+
+	if cachedBlock := executionPool.GetBlockCache().GetCachedBlockByRoot(blockHash); cachedBlock != nil {
+		if blockData := cachedBlock.AwaitBlock(ctx, 2*time.Second); blockData != nil {
+			return blockData, nil
+		}
+	}
+
+	client := executionPool.AwaitReadyEndpoint(ctx, execution.AnyClient)
+	if client == nil {
+		return nil, fmt.Errorf("no ready execution client")
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+	defer cancel()
+
+	return client.GetRPCClient().GetBlockByHash(reqCtx, blockHash)
+}
+
+// getExecutionClient returns a client that has seen the block, or any ready client.
+func (t *Task) getExecutionClient(ctx context.Context, block *execution.Block) *execution.Client {
+	executionPool := t.ctx.Scheduler.GetServices().ClientPool().GetExecutionPool()
+
+	for _, client := range block.GetSeenBy() {
+		if executionPool.IsClientReady(client) {
+			return client
+		}
+	}
+
+	return executionPool.AwaitReadyEndpoint(ctx, execution.AnyClient)
+}
+
+func (t *Task) setOutputs() {
+	t.ctx.Outputs.SetVar("checkedExecutionBlocks", t.checkedExecutionBlocks)
+	t.ctx.Outputs.SetVar("checkedConsensusBlocks", t.checkedConsensusBlocks)
+
+	if mismatchesData, err := vars.GeneralizeData(t.mismatches); err == nil {
+		t.ctx.Outputs.SetVar("mismatches", mismatchesData)
+	} else {
+		t.logger.Warnf("failed setting `mismatches` output: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package checkexecutionfeemarket
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+)
+
+type Config struct {
+	BlockCount     uint64               `yaml:"blockCount" json:"blockCount"`
+	TrendWindow    uint64               `yaml:"trendWindow" json:"trendWindow"`
+	BlobSchedule   []BlobScheduleConfig `yaml:"blobSchedule" json:"blobSchedule"`
+	RequestTimeout helper.Duration      `yaml:"requestTimeout" json:"requestTimeout"`
+	FailOnMismatch bool                 `yaml:"failOnMismatch" json:"failOnMismatch"`
+}
+
+type BlobScheduleConfig struct {
+	ActivationTime        uint64 `yaml:"activationTime" json:"activationTime"`
+	Target                uint64 `yaml:"target" json:"target"`
+	Max                   uint64 `yaml:"max" json:"max"`
+	BaseFeeUpdateFraction uint64 `yaml:"baseFeeUpdateFraction" json:"baseFeeUpdateFraction"`
+	ReservePrice          bool   `yaml:"reservePrice" json:"reservePrice"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		TrendWindow:    32,
+		RequestTimeout: helper.Duration{Duration: 30 * time.Second},
+		FailOnMismatch: true,
+	}
+}
+
+func (c *Config) Validate() error {
+	if c.TrendWindow == 0 {
+		return errors.New("trendWindow must be greater than 0")
+	}
+
+	for idx, schedule := range c.BlobSchedule {
+		if schedule.Max < schedule.Target || schedule.BaseFeeUpdateFraction == 0 {
+			return fmt.Errorf("invalid blobSchedule entry %v", idx)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,96 @@
+package checkexecutionfeemarket
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	elasticityMultiplier     = 2
+	baseFeeChangeDenominator = 8
+	gasPerBlob               = 1 << 17
+	blobBaseCost             = 1 << 13
+	minBaseFeePerBlobGas     = 1
+)
+
+// calcBaseFee computes the EIP-1559 base fee of a block from its parent header.
+func calcBaseFee(parent *types.Header) *big.Int {
+	parentGasTarget := parent.GasLimit / elasticityMultiplier
+	if parentGasTarget == 0 || parent.GasUsed == parentGasTarget {
+		return new(big.Int).Set(parent.BaseFee)
+	}
+
+	target := new(big.Int).SetUint64(parentGasTarget)
+
+	if parent.GasUsed > parentGasTarget {
+		delta := new(big.Int).SetUint64(parent.GasUsed - parentGasTarget)
+		delta.Mul(delta, parent.BaseFee)
+		delta.Div(delta, target)
+		delta.Div(delta, big.NewInt(baseFeeChangeDenominator))
+
+		if delta.Sign() == 0 {
+			delta.SetUint64(1)
+		}
+
+		return delta.Add(delta, parent.BaseFee)
+	}
+
+	delta := new(big.Int).SetUint64(parentGasTarget - parent.GasUsed)
+	delta.Mul(delta, parent.BaseFee)
+	delta.Div(delta, target)
+	delta.Div(delta, big.NewInt(baseFeeChangeDenominator))
+
+	return delta.Sub(parent.BaseFee, delta)
+}
+
+// calcExcessBlobGas computes the EIP-4844 excess blob gas of a block from its parent header, using the blob schedule of the block.
+// With the reserve price rule (EIP-7918), the excess blob gas is not reduced while the blob base fee is below the execution cost.
+// Like in the execution specs, the blob base fee of the parent is computed with the update fraction of the block schedule.
+func calcExcessBlobGas(parent *types.Header, schedule *BlobScheduleConfig) uint64 {
+	parentExcessBlobGas := uint64(0)
+	parentBlobGasUsed := uint64(0)
+
+	if parent.ExcessBlobGas != nil {
+		parentExcessBlobGas = *parent.ExcessBlobGas
+		parentBlobGasUsed = *parent.BlobGasUsed
+	}
+
+	targetBlobGas := schedule.Target * gasPerBlob
+
+	if parentExcessBlobGas+parentBlobGasUsed < targetBlobGas {
+		return 0
+	}
+
+	if schedule.ReservePrice && parent.BaseFee != nil {
+		reservePrice := new(big.Int).Mul(big.NewInt(blobBaseCost), parent.BaseFee)
+		blobPrice := new(big.Int).Mul(calcBlobBaseFee(parentExcessBlobGas, schedule), big.NewInt(gasPerBlob))
+
+		if reservePrice.Cmp(blobPrice) > 0 {
+			return parentExcessBlobGas + parentBlobGasUsed*(schedule.Max-schedule.Target)/schedule.Max
+		}
+	}
+
+	return parentExcessBlobGas + parentBlobGasUsed - targetBlobGas
+}
+
+// calcBlobBaseFee computes the base fee per blob gas for the given excess blob gas.
+func calcBlobBaseFee(excessBlobGas uint64, schedule *BlobScheduleConfig) *big.Int {
+	return fakeExponential(big.NewInt(minBaseFeePerBlobGas), new(big.Int).SetUint64(excessBlobGas), new(big.Int).SetUint64(schedule.BaseFeeUpdateFraction))
+}
+
+// fakeExponential approximates factor * e ** (numerator / denominator) using Taylor expansion.
+func fakeExponential(factor, numerator, denominator *big.Int) *big.Int {
+	output := new(big.Int)
+	accum := new(big.Int).Mul(factor, denominator)
+
+	for i := 1; accum.Sign() > 0; i++ {
+		output.Add(output, accum)
+
+		accum.Mul(accum, numerator)
+		accum.Div(accum, denominator)
+		accum.Div(accum, big.NewInt(int64(i)))
+	}
+
+	return output.Div(output, denominator)
+}
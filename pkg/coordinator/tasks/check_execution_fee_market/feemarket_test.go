@@ -0,0 +1,76 @@
+package checkexecutionfeemarket
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestCalcBaseFee(t *testing.T) {
+	tests := []struct {
+		name     string
+		gasLimit uint64
+		gasUsed  uint64
+		baseFee  int64
+		expected int64
+	}{
+		{name: "at target", gasLimit: 30000000, gasUsed: 15000000, baseFee: 1000000000, expected: 1000000000},
+		{name: "full block", gasLimit: 30000000, gasUsed: 30000000, baseFee: 1000000000, expected: 1125000000},
+		{name: "empty block", gasLimit: 30000000, gasUsed: 0, baseFee: 1000000000, expected: 875000000},
+		{name: "minimum increase", gasLimit: 30000000, gasUsed: 20000000, baseFee: 7, expected: 8},
+		{name: "below target", gasLimit: 36000000, gasUsed: 12345678, baseFee: 987654321, expected: 948872963},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parent := &types.Header{
+				GasLimit: test.gasLimit,
+				GasUsed:  test.gasUsed,
+				BaseFee:  big.NewInt(test.baseFee),
+			}
+
+			if baseFee := calcBaseFee(parent); baseFee.Cmp(big.NewInt(test.expected)) != 0 {
+				t.Errorf("base fee is %v, expected %v", baseFee, test.expected)
+			}
+		})
+	}
+}
+
+func TestCalcExcessBlobGas(t *testing.T) {
+	cancunSchedule := &BlobScheduleConfig{Target: 3, Max: 6, BaseFeeUpdateFraction: 3338477}
+	pragueSchedule := &BlobScheduleConfig{Target: 6, Max: 9, BaseFeeUpdateFraction: 5007716}
+	osakaSchedule := &BlobScheduleConfig{Target: 6, Max: 9, BaseFeeUpdateFraction: 5007716, ReservePrice: true}
+	bpo1Schedule := &BlobScheduleConfig{Target: 10, Max: 15, BaseFeeUpdateFraction: 8346193, ReservePrice: true}
+
+	tests := []struct {
+		name                string
+		parentExcessBlobGas uint64
+		parentBlobGasUsed   uint64
+		parentBaseFee       int64
+		schedule            *BlobScheduleConfig
+		expected            uint64
+	}{
+		{name: "below target", parentExcessBlobGas: 0, parentBlobGasUsed: 3 * gasPerBlob, parentBaseFee: 1000000000, schedule: cancunSchedule, expected: 0},
+		{name: "above target", parentExcessBlobGas: 1000000, parentBlobGasUsed: 6 * gasPerBlob, parentBaseFee: 1000000000, schedule: pragueSchedule, expected: 1000000},
+		{name: "reserve price active", parentExcessBlobGas: 1000000, parentBlobGasUsed: 6 * gasPerBlob, parentBaseFee: 1000000000, schedule: osakaSchedule, expected: 1262144},
+		{name: "reserve price inactive", parentExcessBlobGas: 100000000, parentBlobGasUsed: 6 * gasPerBlob, parentBaseFee: 1000000000, schedule: osakaSchedule, expected: 100000000},
+		// the parent blob base fee is computed with the update fraction of the child schedule,
+		// with the parent (osaka) update fraction the reserve price would be inactive (19475712)
+		{name: "update fraction change", parentExcessBlobGas: 20000000, parentBlobGasUsed: 6 * gasPerBlob, parentBaseFee: 500, schedule: bpo1Schedule, expected: 20262144},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parent := &types.Header{
+				ExcessBlobGas: &test.parentExcessBlobGas,
+				BlobGasUsed:   &test.parentBlobGasUsed,
+				BaseFee:       big.NewInt(test.parentBaseFee),
+			}
+
+			if excessBlobGas := calcExcessBlobGas(parent, test.schedule); excessBlobGas != test.expected {
+				t.Errorf("excess blob gas is %v, expected %v", excessBlobGas, test.expected)
+			}
+		})
+	}
+}
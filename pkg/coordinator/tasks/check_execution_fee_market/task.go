@@ -0,0 +1,465 @@
+package checkexecutionfeemarket
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/execution"
+	executionrpc "github.com/erigontech/assertoor/pkg/coordinator/clients/execution/rpc"
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/erigontech/assertoor/pkg/coordinator/vars"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	TaskName       = "check_execution_fee_market"
+	TaskDescriptor = &types.TaskDescriptor{
+		Name:        TaskName,
+		Description: "Verifies the EIP-1559 base fee and the EIP-4844 excess blob gas of every new block.",
+		Config:      DefaultConfig(),
+		NewTask:     NewTask,
+	}
+)
+
+// default blob parameters of the cancun & prague forks, used if the execution clients do not support `eth_config`
+var (
+	cancunBlobSchedule = BlobScheduleConfig{Target: 3, Max: 6, BaseFeeUpdateFraction: 3338477}
+	pragueBlobSchedule = BlobScheduleConfig{Target: 6, Max: 9, BaseFeeUpdateFraction: 5007716}
+)
+
+type Task struct {
+	ctx           *types.TaskContext
+	options       *types.TaskOptions
+	config        Config
+	logger        logrus.FieldLogger
+	blobSchedule  []*BlobScheduleConfig
+	checkedBlocks uint64
+	mismatches    []*BlockMismatch
+	blockStats    []*blockFeeStats
+}
+
+type BlockMismatch struct {
+	Number uint64   `json:"number"`
+	Hash   string   `json:"hash"`
+	Errors []string `json:"errors"`
+}
+
+type blockFeeStats struct {
+	number          uint64
+	baseFee         *big.Int
+	blobBaseFee     *big.Int
+	excessBlobGas   uint64
+	gasUtilization  float64
+	blobUtilization *float64
+}
+
+type UtilizationStats struct {
+	Last    float64 `json:"last"`
+	Average float64 `json:"average"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+}
+
+func NewTask(ctx *types.TaskContext, options *types.TaskOptions) (types.Task, error) {
+	return &Task{
+		ctx:     ctx,
+		options: options,
+		logger:  ctx.Logger.GetLogger(),
+	}, nil
+}
+
+func (t *Task) Config() interface{} {
+	return t.config
+}
+
+func (t *Task) Timeout() time.Duration {
+	return t.options.Timeout.Duration
+}
+
+func (t *Task) LoadConfig() error {
+	config := DefaultConfig()
+
+	// parse static config
+	if t.options.Config != nil {
+		if err := t.options.Config.Unmarshal(&config); err != nil {
+			return fmt.Errorf("error parsing task config for %v: %w", TaskName, err)
+		}
+	}
+
+	// load dynamic vars
+	err := t.ctx.Vars.ConsumeVars(&config, t.options.ConfigVars)
+	if err != nil {
+		return err
+	}
+
+	// validate config
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	t.config = config
+
+	return nil
+}
+
+func (t *Task) Execute(ctx context.Context) error {
+	executionPool := t.ctx.Scheduler.GetServices().ClientPool().GetExecutionPool()
+
+	blockSubscription := executionPool.GetBlockCache().SubscribeBlockEvent(32)
+	defer blockSubscription.Unsubscribe()
+
+	t.loadBlobSchedule(ctx)
+
+	for {
+		select {
+		case block := <-blockSubscription.Channel():
+			blockErrors, err := t.checkBlock(ctx, block)
+			if err != nil {
+				t.logger.Warnf("could not check fees of block %v [%v]: %v", block.Number, block.Hash.String(), err)
+				continue
+			}
+
+			t.checkedBlocks++
+
+			if len(blockErrors) > 0 {
+				t.mismatches = append(t.mismatches, &BlockMismatch{
+					Number: block.Number,
+					Hash:   block.Hash.String(),
+					Errors: blockErrors,
+				})
+
+				for _, err := range blockErrors {
+					t.logger.Errorf("fee mismatch in block %v [%v]: %v", block.Number, block.Hash.String(), err)
+				}
+			}
+
+			t.setOutputs()
+
+			if len(blockErrors) > 0 && t.config.FailOnMismatch {
+				t.ctx.SetResult(types.TaskResultFailure)
+				return fmt.Errorf("fee mismatch in block %v", block.Number)
+			}
+
+			if t.config.BlockCount > 0 && t.checkedBlocks >= t.config.BlockCount {
+				if len(t.mismatches) > 0 {
+					t.ctx.SetResult(types.TaskResultFailure)
+					return fmt.Errorf("%v blocks with fee mismatches", len(t.mismatches))
+				}
+
+				t.ctx.SetResult(types.TaskResultSuccess)
+
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// loadBlobSchedule loads the blob schedule from the task config, from `eth_config` (EIP-7910) or
+// from the consensus fork schedule with the default cancun & prague blob parameters.
+func (t *Task) loadBlobSchedule(ctx context.Context) {
+	reservePriceTime := t.getConsensusForkTime("fulu")
+
+	if len(t.config.BlobSchedule) > 0 {
+		for idx := range t.config.BlobSchedule {
+			t.blobSchedule = append(t.blobSchedule, &t.config.BlobSchedule[idx])
+		}
+	} else if schedule := t.loadEthConfigBlobSchedule(ctx, reservePriceTime); len(schedule) > 0 {
+		t.blobSchedule = schedule
+	} else {
+		t.logger.Warnf("could not load blob schedule via eth_config, using default cancun & prague blob parameters")
+
+		defaultSchedules := []struct {
+			fork     string
+			schedule BlobScheduleConfig
+		}{
+			{"deneb", cancunBlobSchedule},
+			{"electra", pragueBlobSchedule},
+		}
+
+		for _, defaultSchedule := range defaultSchedules {
+			activationTime := t.getConsensusForkTime(defaultSchedule.fork)
+			if activationTime == nil {
+				continue
+			}
+
+			schedule := defaultSchedule.schedule
+			schedule.ActivationTime = *activationTime
+			schedule.ReservePrice = reservePriceTime != nil && *activationTime >= *reservePriceTime
+
+			t.blobSchedule = append(t.blobSchedule, &schedule)
+		}
+	}
+
+	sort.Slice(t.blobSchedule, func(a, b int) bool {
+		return t.blobSchedule[a].ActivationTime < t.blobSchedule[b].ActivationTime
+	})
+
+	for _, schedule := range t.blobSchedule {
+		t.logger.Infof("blob schedule: time %v, target %v, max %v, update fraction %v, reserve price: %v", schedule.ActivationTime, schedule.Target, schedule.Max, schedule.BaseFeeUpdateFraction, schedule.ReservePrice)
+	}
+}
+
+func (t *Task) loadEthConfigBlobSchedule(ctx context.Context, reservePriceTime *uint64) []*BlobScheduleConfig {
+	for _, client := range t.ctx.Scheduler.GetServices().ClientPool().GetExecutionPool().GetReadyEndpoints(true) {
+		reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+		ethConfig, err := client.GetRPCClient().GetEthConfig(reqCtx)
+
+		cancel()
+
+		if err != nil {
+			t.logger.Debugf("could not load eth_config from %v: %v", client.GetName(), err)
+			continue
+		}
+
+		schedule := []*BlobScheduleConfig{}
+		activationTimes := map[uint64]bool{}
+
+		for _, forkConfig := range []*executionrpc.EthForkConfig{ethConfig.Current, ethConfig.Next, ethConfig.Last} {
+			if forkConfig == nil || forkConfig.BlobSchedule == nil || activationTimes[forkConfig.ActivationTime] {
+				continue
+			}
+
+			activationTimes[forkConfig.ActivationTime] = true
+			schedule = append(schedule, &BlobScheduleConfig{
+				ActivationTime:        forkConfig.ActivationTime,
+				Target:                forkConfig.BlobSchedule.Target,
+				Max:                   forkConfig.BlobSchedule.Max,
+				BaseFeeUpdateFraction: forkConfig.BlobSchedule.BaseFeeUpdateFraction,
+				ReservePrice:          reservePriceTime != nil && forkConfig.ActivationTime >= *reservePriceTime,
+			})
+		}
+
+		return schedule
+	}
+
+	return nil
+}
+
+// getConsensusForkTime returns the activation timestamp of a consensus fork, or nil if the fork is not scheduled.
+func (t *Task) getConsensusForkTime(forkName string) *uint64 {
+	blockCache := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetBlockCache()
+	genesis := blockCache.GetGenesis()
+	specs := blockCache.GetSpecs()
+
+	if genesis == nil || specs == nil {
+		return nil
+	}
+
+	for _, fork := range blockCache.GetForkSchedule() {
+		if fork.Name != forkName || fork.Epoch == consensus.FarFutureEpoch {
+			continue
+		}
+
+		forkTime := uint64(genesis.GenesisTime.Unix()) + fork.Epoch*specs.SlotsPerEpoch*uint64(specs.SecondsPerSlot.Seconds())
+
+		return &forkTime
+	}
+
+	return nil
+}
+
+func (t *Task) getBlobSchedule(timestamp uint64) *BlobScheduleConfig {
+	var schedule *BlobScheduleConfig
+
+	for _, entry := range t.blobSchedule {
+		if entry.ActivationTime <= timestamp {
+			schedule = entry
+		}
+	}
+
+	return schedule
+}
+
+func (t *Task) checkBlock(ctx context.Context, block *execution.Block) ([]string, error) {
+	blockData := block.AwaitBlock(ctx, 2*time.Second)
+	if blockData == nil {
+		return nil, fmt.Errorf("block not available")
+	}
+
+	header := blockData.Header()
+
+	parent, err := t.getParentHeader(ctx, header.ParentHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed loading parent block: %w", err)
+	}
+
+	blockErrors := []string{}
+	stats := &blockFeeStats{
+		number:  block.Number,
+		baseFee: header.BaseFee,
+	}
+
+	if parent.BaseFee != nil {
+		expectedBaseFee := calcBaseFee(parent)
+
+		if header.BaseFee == nil {
+			blockErrors = append(blockErrors, "block has no base fee")
+		} else if header.BaseFee.Cmp(expectedBaseFee) != 0 {
+			blockErrors = append(blockErrors, fmt.Sprintf("base fee is %v, expected %v", header.BaseFee, expectedBaseFee))
+		}
+	}
+
+	if gasTarget := header.GasLimit / elasticityMultiplier; gasTarget > 0 {
+		stats.gasUtilization = float64(header.GasUsed) * 100 / float64(gasTarget)
+	}
+
+	if schedule := t.getBlobSchedule(header.Time); schedule != nil {
+		blockErrors = append(blockErrors, t.checkBlobGas(header, parent, schedule, stats)...)
+	}
+
+	t.blockStats = append(t.blockStats, stats)
+	if uint64(len(t.blockStats)) > t.config.TrendWindow {
+		t.blockStats = t.blockStats[1:]
+	}
+
+	return blockErrors, nil
+}
+
+func (t *Task) checkBlobGas(header, parent *ethtypes.Header, schedule *BlobScheduleConfig, stats *blockFeeStats) []string {
+	if header.ExcessBlobGas == nil || header.BlobGasUsed == nil {
+		return []string{"block has no blob gas fields"}
+	}
+
+	blockErrors := []string{}
+
+	expectedExcessBlobGas := calcExcessBlobGas(parent, schedule)
+	if *header.ExcessBlobGas != expectedExcessBlobGas {
+		blockErrors = append(blockErrors, fmt.Sprintf("excess blob gas is %v, expected %v", *header.ExcessBlobGas, expectedExcessBlobGas))
+	}
+
+	if *header.BlobGasUsed%gasPerBlob != 0 {
+		blockErrors = append(blockErrors, fmt.Sprintf("blob gas used %v is not a multiple of %v", *header.BlobGasUsed, gasPerBlob))
+	}
+
+	if *header.BlobGasUsed > schedule.Max*gasPerBlob {
+		blockErrors = append(blockErrors, fmt.Sprintf("blob gas used %v exceeds the limit of %v blobs", *header.BlobGasUsed, schedule.Max))
+	}
+
+	stats.excessBlobGas = *header.ExcessBlobGas
+	stats.blobBaseFee = calcBlobBaseFee(*header.ExcessBlobGas, schedule)
+
+	if schedule.Target > 0 {
+		blobUtilization := float64(*header.BlobGasUsed) * 100 / float64(schedule.Target*gasPerBlob)
+		stats.blobUtilization = &blobUtilization
+	}
+
+	return blockErrors
+}
+
+func (t *Task) getParentHeader(ctx context.Context, parentHash common.Hash) (*ethtypes.Header, error) {
+	executionPool := t.ctx.Scheduler.GetServices().ClientPool().GetExecutionPool()
+
+	if parentBlock := executionPool.GetBlockCache().GetCachedBlockByRoot(parentHash); parentBlock != nil {
+		if parentData := parentBlock.AwaitBlock(ctx, 2*time.Second); parentData != nil {
+			return parentData.Header(), nil
+		}
+	}
+
+	client := executionPool.AwaitReadyEndpoint(ctx, execution.AnyClient)
+	if client == nil {
+		return nil, fmt.Errorf("no ready execution client")
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+	defer cancel()
+
+	parentData, err := client.GetRPCClient().GetBlockByHash(reqCtx, parentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return parentData.Header(), nil
+}
+
+func (t *Task) setOutputs() {
+	t.ctx.Outputs.SetVar("checkedBlocks", t.checkedBlocks)
+
+	if mismatchesData, err := vars.GeneralizeData(t.mismatches); err == nil {
+		t.ctx.Outputs.SetVar("mismatches", mismatchesData)
+	} else {
+		t.logger.Warnf("failed setting `mismatches` output: %v", err)
+	}
+
+	if len(t.blockStats) == 0 {
+		return
+	}
+
+	firstStats := t.blockStats[0]
+	lastStats := t.blockStats[len(t.blockStats)-1]
+
+	if lastStats.baseFee != nil {
+		t.ctx.Outputs.SetVar("baseFee", lastStats.baseFee.String())
+
+		if firstStats.baseFee != nil {
+			t.ctx.Outputs.SetVar("baseFeeChange", getRelativeChange(firstStats.baseFee, lastStats.baseFee))
+		}
+	}
+
+	if lastStats.blobBaseFee != nil {
+		t.ctx.Outputs.SetVar("blobBaseFee", lastStats.blobBaseFee.String())
+		t.ctx.Outputs.SetVar("excessBlobGas", lastStats.excessBlobGas)
+
+		if firstStats.blobBaseFee != nil {
+			t.ctx.Outputs.SetVar("blobBaseFeeChange", getRelativeChange(firstStats.blobBaseFee, lastStats.blobBaseFee))
+		}
+	}
+
+	gasUtilization := []float64{}
+	blobUtilization := []float64{}
+
+	for _, stats := range t.blockStats {
+		gasUtilization = append(gasUtilization, stats.gasUtilization)
+
+		if stats.blobUtilization != nil {
+			blobUtilization = append(blobUtilization, *stats.blobUtilization)
+		}
+	}
+
+	t.setUtilizationOutput("gasUtilization", gasUtilization)
+	t.setUtilizationOutput("blobUtilization", blobUtilization)
+}
+
+func (t *Task) setUtilizationOutput(name string, values []float64) {
+	if len(values) == 0 {
+		return
+	}
+
+	stats := &UtilizationStats{
+		Last: values[len(values)-1],
+		Min:  values[0],
+		Max:  values[0],
+	}
+
+	for _, value := range values {
+		stats.Average += value
+		stats.Min = min(stats.Min, value)
+		stats.Max = max(stats.Max, value)
+	}
+
+	stats.Average /= float64(len(values))
+
+	if statsData, err := vars.GeneralizeData(stats); err == nil {
+		t.ctx.Outputs.SetVar(name, statsData)
+	} else {
+		t.logger.Warnf("failed setting `%v` output: %v", name, err)
+	}
+}
+
+// getRelativeChange returns the change from the first to the last value in percent.
+func getRelativeChange(first, last *big.Int) float64 {
+	if first.Sign() == 0 {
+		return 0
+	}
+
+	change, _ := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Sub(last, first)), new(big.Float).SetInt(first)).Float64()
+
+	return change * 100
+}
@@ -0,0 +1,35 @@
+package checkexecutiongaslimit
+
+import (
+	"errors"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+)
+
+type Config struct {
+	TargetGasLimit       uint64          `yaml:"targetGasLimit" json:"targetGasLimit"`
+	ConvergenceBlocks    uint64          `yaml:"convergenceBlocks" json:"convergenceBlocks"`
+	StableBlocks         uint64          `yaml:"stableBlocks" json:"stableBlocks"`
+	FailOnWrongDirection bool            `yaml:"failOnWrongDirection" json:"failOnWrongDirection"`
+	RequestTimeout       helper.Duration `yaml:"requestTimeout" json:"requestTimeout"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		StableBlocks:   1,
+		RequestTimeout: helper.Duration{Duration: 30 * time.Second},
+	}
+}
+
+func (c *Config) Validate() error {
+	if c.TargetGasLimit == 0 {
+		return errors.New("targetGasLimit must be set")
+	}
+
+	if c.StableBlocks == 0 {
+		return errors.New("stableBlocks must be greater than 0")
+	}
+
+	return nil
+}
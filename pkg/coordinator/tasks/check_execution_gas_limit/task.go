@@ -0,0 +1,411 @@
+package checkexecutiongaslimit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/attestantio/go-eth2-client/spec"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/execution"
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/erigontech/assertoor/pkg/coordinator/vars"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	TaskName       = "check_execution_gas_limit"
+	TaskDescriptor = &types.TaskDescriptor{
+		Name:        TaskName,
+		Description: "Checks that the block gas limit converges to a target within the protocol adjustment bound.",
+		Config:      DefaultConfig(),
+		NewTask:     NewTask,
+	}
+)
+
+const (
+	gasLimitBoundDivisor = 1024
+	minGasLimit          = 5000
+
+	voteToward  = "toward"
+	voteAway    = "away"
+	voteStalled = "stalled"
+	voteHold    = "hold"
+)
+
+type Task struct {
+	ctx           *types.TaskContext
+	options       *types.TaskOptions
+	config        Config
+	logger        logrus.FieldLogger
+	checkedBlocks uint64
+	gasLimit      uint64
+	stableCount   uint64
+	convergedAt   *uint64
+	violations    []*BoundViolation
+	proposers     map[uint64]*ProposerStats
+	clients       map[string]*ClientStats
+}
+
+type BoundViolation struct {
+	Slot           uint64 `json:"slot"`
+	BlockNumber    uint64 `json:"blockNumber"`
+	ProposerIndex  uint64 `json:"proposerIndex"`
+	ProposerName   string `json:"proposerName"`
+	ParentGasLimit uint64 `json:"parentGasLimit"`
+	GasLimit       uint64 `json:"gasLimit"`
+}
+
+type ProposerStats struct {
+	Index        uint64 `json:"index"`
+	Name         string `json:"name"`
+	Blocks       uint64 `json:"blocks"`
+	Toward       uint64 `json:"toward"`
+	Away         uint64 `json:"away"`
+	Stalled      uint64 `json:"stalled"`
+	LastGasLimit uint64 `json:"lastGasLimit"`
+}
+
+type ClientStats struct {
+	Name          string   `json:"name"`
+	Proposers     []uint64 `json:"proposers"`
+	Blocks        uint64   `json:"blocks"`
+	Toward        uint64   `json:"toward"`
+	Away          uint64   `json:"away"`
+	Stalled       uint64   `json:"stalled"`
+	LastGasLimit  uint64   `json:"lastGasLimit"`
+	lastBlockSlot uint64
+}
+
+func NewTask(ctx *types.TaskContext, options *types.TaskOptions) (types.Task, error) {
+	return &Task{
+		ctx:       ctx,
+		options:   options,
+		logger:    ctx.Logger.GetLogger(),
+		proposers: map[uint64]*ProposerStats{},
+		clients:   map[string]*ClientStats{},
+	}, nil
+}
+
+func (t *Task) Config() interface{} {
+	return t.config
+}
+
+func (t *Task) Timeout() time.Duration {
+	return t.options.Timeout.Duration
+}
+
+func (t *Task) LoadConfig() error {
+	config := DefaultConfig()
+
+	// parse static config
+	if t.options.Config != nil {
+		if err := t.options.Config.Unmarshal(&config); err != nil {
+			return fmt.Errorf("error parsing task config for %v: %w", TaskName, err)
+		}
+	}
+
+	// load dynamic vars
+	err := t.ctx.Vars.ConsumeVars(&config, t.options.ConfigVars)
+	if err != nil {
+		return err
+	}
+
+	// validate config
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	t.config = config
+
+	return nil
+}
+
+func (t *Task) Execute(ctx context.Context) error {
+	blockSubscription := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetBlockCache().SubscribeBlockEvent(32)
+	defer blockSubscription.Unsubscribe()
+
+	for {
+		select {
+		case block := <-blockSubscription.Channel():
+			vote, err := t.checkBlock(ctx, block)
+			if err != nil {
+				t.logger.Warnf("could not check gas limit of block %v [0x%x]: %v", block.Slot, block.Root, err)
+				continue
+			}
+
+			if vote == "" {
+				// pre-merge block
+				continue
+			}
+
+			t.setOutputs()
+
+			if len(t.violations) > 0 {
+				t.ctx.SetResult(types.TaskResultFailure)
+				return fmt.Errorf("gas limit of block %v exceeds the adjustment bound", block.Slot)
+			}
+
+			if t.config.FailOnWrongDirection && (vote == voteAway || vote == voteStalled) {
+				t.ctx.SetResult(types.TaskResultFailure)
+				return fmt.Errorf("proposer of block %v voted in the wrong direction (%v)", block.Slot, vote)
+			}
+
+			if t.stableCount >= t.config.StableBlocks {
+				t.logger.Infof("gas limit converged to %v after %v blocks", t.config.TargetGasLimit, *t.convergedAt)
+				t.ctx.SetResult(types.TaskResultSuccess)
+
+				return nil
+			}
+
+			if t.config.ConvergenceBlocks > 0 && t.checkedBlocks >= t.config.ConvergenceBlocks && t.convergedAt == nil {
+				t.ctx.SetResult(types.TaskResultFailure)
+				return fmt.Errorf("gas limit did not converge to %v within %v blocks (current: %v)", t.config.TargetGasLimit, t.config.ConvergenceBlocks, t.gasLimit)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// checkBlock checks the gas limit adjustment of a block and returns the proposer vote.
+// Returns an empty vote for blocks without execution payload.
+func (t *Task) checkBlock(ctx context.Context, block *consensus.Block) (string, error) {
+	blockData := block.AwaitBlock(ctx, 2*time.Second)
+	if blockData == nil {
+		return "", fmt.Errorf("block not available")
+	}
+
+	blockNumber, gasLimit, parentHash := getPayloadGasLimit(blockData)
+	if blockNumber == 0 {
+		return "", nil
+	}
+
+	parentGasLimit, err := t.getParentGasLimit(ctx, parentHash)
+	if err != nil {
+		return "", fmt.Errorf("failed loading parent block: %w", err)
+	}
+
+	proposerIndex, err := blockData.ProposerIndex()
+	if err != nil {
+		return "", err
+	}
+
+	proposer := t.proposers[uint64(proposerIndex)]
+	if proposer == nil {
+		proposer = &ProposerStats{
+			Index: uint64(proposerIndex),
+			Name:  t.ctx.Scheduler.GetServices().ValidatorNames().GetValidatorName(uint64(proposerIndex)),
+		}
+		t.proposers[uint64(proposerIndex)] = proposer
+	}
+
+	// the gas limit may change by less than parent_gas_limit / 1024 per block
+	diff := max(gasLimit, parentGasLimit) - min(gasLimit, parentGasLimit)
+	if diff >= parentGasLimit/gasLimitBoundDivisor || gasLimit < minGasLimit {
+		t.violations = append(t.violations, &BoundViolation{
+			Slot:           uint64(block.Slot),
+			BlockNumber:    blockNumber,
+			ProposerIndex:  proposer.Index,
+			ProposerName:   proposer.Name,
+			ParentGasLimit: parentGasLimit,
+			GasLimit:       gasLimit,
+		})
+
+		t.logger.Errorf("gas limit of block %v (slot %v, proposer %v %v) changed from %v to %v, exceeding the adjustment bound", blockNumber, block.Slot, proposer.Index, proposer.Name, parentGasLimit, gasLimit)
+	}
+
+	vote := getVote(parentGasLimit, gasLimit, t.config.TargetGasLimit)
+
+	switch vote {
+	case voteToward:
+		proposer.Toward++
+	case voteAway:
+		proposer.Away++
+	case voteStalled:
+		proposer.Stalled++
+	}
+
+	if vote == voteAway || vote == voteStalled {
+		t.logger.Warnf("proposer %v (%v) voted in the wrong direction in block %v: %v -> %v (target: %v)", proposer.Index, proposer.Name, blockNumber, parentGasLimit, gasLimit, t.config.TargetGasLimit)
+	}
+
+	proposer.Blocks++
+	proposer.LastGasLimit = gasLimit
+
+	// aggregate the votes per client, validators without name are grouped as "unknown"
+	clientName := proposer.Name
+	if clientName == "" {
+		clientName = "unknown"
+	}
+
+	client := t.clients[clientName]
+	if client == nil {
+		client = &ClientStats{
+			Name: clientName,
+		}
+		t.clients[clientName] = client
+	}
+
+	if proposer.Blocks == 1 {
+		client.Proposers = append(client.Proposers, proposer.Index)
+	}
+
+	client.Blocks++
+
+	switch vote {
+	case voteToward:
+		client.Toward++
+	case voteAway:
+		client.Away++
+	case voteStalled:
+		client.Stalled++
+	}
+
+	if uint64(block.Slot) >= client.lastBlockSlot {
+		client.LastGasLimit = gasLimit
+		client.lastBlockSlot = uint64(block.Slot)
+	}
+
+	t.checkedBlocks++
+	t.gasLimit = gasLimit
+
+	if gasLimit == t.config.TargetGasLimit {
+		t.stableCount++
+
+		if t.convergedAt == nil {
+			convergedAt := t.checkedBlocks
+			t.convergedAt = &convergedAt
+		}
+	} else {
+		t.stableCount = 0
+		t.convergedAt = nil
+	}
+
+	return vote, nil
+}
+
+// getVote classifies the gas limit change of a block relative to the target gas limit.
+func getVote(parentGasLimit, gasLimit, targetGasLimit uint64) string {
+	parentDistance := max(parentGasLimit, targetGasLimit) - min(parentGasLimit, targetGasLimit)
+	distance := max(gasLimit, targetGasLimit) - min(gasLimit, targetGasLimit)
+
+	switch {
+	case parentDistance == 0 && distance == 0:
+		return voteHold
+	case distance < parentDistance:
+		return voteToward
+	case distance > parentDistance:
+		return voteAway
+	default:
+		return voteStalled
+	}
+}
+
+// getPayloadGasLimit returns the block number, gas limit and parent hash of the execution payload of a beacon block.
+func getPayloadGasLimit(block *spec.VersionedSignedBeaconBlock) (blockNumber, gasLimit uint64, parentHash common.Hash) {
+	switch block.Version {
+	case spec.DataVersionBellatrix:
+		payload := block.Bellatrix.Message.Body.ExecutionPayload
+		return payload.BlockNumber, payload.GasLimit, common.Hash(payload.ParentHash)
+	case spec.DataVersionCapella:
+		payload := block.Capella.Message.Body.ExecutionPayload
+		return payload.BlockNumber, payload.GasLimit, common.Hash(payload.ParentHash)
+	case spec.DataVersionDeneb:
+		payload := block.Deneb.Message.Body.ExecutionPayload
+		return payload.BlockNumber, payload.GasLimit, common.Hash(payload.ParentHash)
+	case spec.DataVersionElectra:
+		payload := block.Electra.Message.Body.ExecutionPayload
+		return payload.BlockNumber, payload.GasLimit, common.Hash(payload.ParentHash)
+	}
+
+	return 0, 0, common.Hash{}
+}
+
+func (t *Task) getParentGasLimit(ctx context.Context, parentHash common.Hash) (uint64, error) {
+	executionPool := t.ctx.Scheduler.GetServices().ClientPool().GetExecutionPool()
+
+	if parentBlock := executionPool.GetBlockCache().GetCachedBlockByRoot(parentHash); parentBlock != nil {
+		if parentData := parentBlock.AwaitBlock(ctx, 2*time.Second); parentData != nil {
+			return parentData.GasLimit(), nil
+		}
+	}
+
+	client := executionPool.AwaitReadyEndpoint(ctx, execution.AnyClient)
+	if client == nil {
+		return 0, fmt.Errorf("no ready execution client")
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+	defer cancel()
+
+	parentData, err := client.GetRPCClient().GetBlockByHash(reqCtx, parentHash)
+	if err != nil {
+		return 0, err
+	}
+
+	return parentData.GasLimit(), nil
+}
+
+func (t *Task) setOutputs() {
+	t.ctx.Outputs.SetVar("checkedBlocks", t.checkedBlocks)
+	t.ctx.Outputs.SetVar("gasLimit", t.gasLimit)
+	t.ctx.Outputs.SetVar("converged", t.convergedAt != nil)
+
+	if t.convergedAt != nil {
+		t.ctx.Outputs.SetVar("convergedAfter", *t.convergedAt)
+	}
+
+	proposers := make([]*ProposerStats, 0, len(t.proposers))
+	wrongDirection := []*ProposerStats{}
+
+	for _, proposer := range t.proposers {
+		proposers = append(proposers, proposer)
+
+		if proposer.Away > 0 || proposer.Stalled > 0 {
+			wrongDirection = append(wrongDirection, proposer)
+		}
+	}
+
+	sort.Slice(proposers, func(a, b int) bool {
+		return proposers[a].Index < proposers[b].Index
+	})
+	sort.Slice(wrongDirection, func(a, b int) bool {
+		return wrongDirection[a].Index < wrongDirection[b].Index
+	})
+
+	if proposersData, err := vars.GeneralizeData(proposers); err == nil {
+		t.ctx.Outputs.SetVar("proposers", proposersData)
+	} else {
+		t.logger.Warnf("failed setting `proposers` output: %v", err)
+	}
+
+	if wrongDirectionData, err := vars.GeneralizeData(wrongDirection); err == nil {
+		t.ctx.Outputs.SetVar("wrongDirectionProposers", wrongDirectionData)
+	} else {
+		t.logger.Warnf("failed setting `wrongDirectionProposers` output: %v", err)
+	}
+
+	clients := make([]*ClientStats, 0, len(t.clients))
+	for _, client := range t.clients {
+		clients = append(clients, client)
+	}
+
+	sort.Slice(clients, func(a, b int) bool {
+		return clients[a].Name < clients[b].Name
+	})
+
+	if clientsData, err := vars.GeneralizeData(clients); err == nil {
+		t.ctx.Outputs.SetVar("clients", clientsData)
+	} else {
+		t.logger.Warnf("failed setting `clients` output: %v", err)
+	}
+
+	if violationsData, err := vars.GeneralizeData(t.violations); err == nil {
+		t.ctx.Outputs.SetVar("boundViolations", violationsData)
+	} else {
+		t.logger.Warnf("failed setting `boundViolations` output: %v", err)
+	}
+}
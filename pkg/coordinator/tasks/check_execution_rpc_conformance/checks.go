@@ -0,0 +1,157 @@
+package checkexecutionrpcconformance
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/utils/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed execution-api.yaml
+var executionAPISpecYaml []byte
+
+var (
+	executionAPISpecMutex  sync.Mutex
+	executionAPIResults    map[string]interface{}
+	executionAPIValidator  *jsonschema.Validator
+	executionAPINamespaces = []string{"eth", "net", "web3", "debug"}
+)
+
+const (
+	fixtureBlock   = "block"
+	fixtureAddress = "address"
+	fixtureTx      = "tx"
+)
+
+// rpcCheck describes a single json-rpc call sent to each execution client.
+// Params is a json array template, placeholders are filled from the fixtures before sending the request.
+// Checks that require the `tx` fixture are run once for each fixture transaction.
+type rpcCheck struct {
+	ID       string
+	Method   string
+	Params   string
+	Requires []string
+	Compare  bool
+}
+
+var executionAPIChecks = []*rpcCheck{
+	// web3 & net
+	{ID: "web3_clientVersion", Method: "web3_clientVersion", Params: `[]`},
+	{ID: "web3_sha3", Method: "web3_sha3", Params: `["0x68656c6c6f20776f726c64"]`, Compare: true},
+	{ID: "net_version", Method: "net_version", Params: `[]`, Compare: true},
+	{ID: "net_listening", Method: "net_listening", Params: `[]`},
+	{ID: "net_peerCount", Method: "net_peerCount", Params: `[]`},
+
+	// eth: chain state
+	{ID: "eth_chainId", Method: "eth_chainId", Params: `[]`, Compare: true},
+	{ID: "eth_syncing", Method: "eth_syncing", Params: `[]`},
+	{ID: "eth_blockNumber", Method: "eth_blockNumber", Params: `[]`},
+	{ID: "eth_gasPrice", Method: "eth_gasPrice", Params: `[]`},
+	{ID: "eth_maxPriorityFeePerGas", Method: "eth_maxPriorityFeePerGas", Params: `[]`},
+	{ID: "eth_blobBaseFee", Method: "eth_blobBaseFee", Params: `[]`},
+	{ID: "eth_feeHistory", Method: "eth_feeHistory", Params: `["0x4", "{block_number}", [25, 75]]`, Requires: []string{fixtureBlock}, Compare: true},
+
+	// eth: blocks
+	{ID: "eth_getBlockByNumber", Method: "eth_getBlockByNumber", Params: `["{block_number}", false]`, Requires: []string{fixtureBlock}, Compare: true},
+	{ID: "eth_getBlockByNumber_fullTx", Method: "eth_getBlockByNumber", Params: `["{block_number}", true]`, Requires: []string{fixtureBlock}, Compare: true},
+	{ID: "eth_getBlockByHash", Method: "eth_getBlockByHash", Params: `["{block_hash}", false]`, Requires: []string{fixtureBlock}, Compare: true},
+	{ID: "eth_getBlockTransactionCountByHash", Method: "eth_getBlockTransactionCountByHash", Params: `["{block_hash}"]`, Requires: []string{fixtureBlock}, Compare: true},
+	{ID: "eth_getBlockTransactionCountByNumber", Method: "eth_getBlockTransactionCountByNumber", Params: `["{block_number}"]`, Requires: []string{fixtureBlock}, Compare: true},
+	{ID: "eth_getUncleCountByBlockHash", Method: "eth_getUncleCountByBlockHash", Params: `["{block_hash}"]`, Requires: []string{fixtureBlock}, Compare: true},
+	{ID: "eth_getUncleCountByBlockNumber", Method: "eth_getUncleCountByBlockNumber", Params: `["{block_number}"]`, Requires: []string{fixtureBlock}, Compare: true},
+	{ID: "eth_getBlockReceipts", Method: "eth_getBlockReceipts", Params: `["{block_number}"]`, Requires: []string{fixtureBlock}, Compare: true},
+	{ID: "eth_getLogs", Method: "eth_getLogs", Params: `[{"fromBlock": "{block_number}", "toBlock": "{block_number}"}]`, Requires: []string{fixtureBlock}, Compare: true},
+
+	// eth: state
+	{ID: "eth_getBalance", Method: "eth_getBalance", Params: `["{address}", "{block_number}"]`, Requires: []string{fixtureBlock, fixtureAddress}, Compare: true},
+	{ID: "eth_getTransactionCount", Method: "eth_getTransactionCount", Params: `["{address}", "{block_number}"]`, Requires: []string{fixtureBlock, fixtureAddress}, Compare: true},
+	{ID: "eth_getCode", Method: "eth_getCode", Params: `["{address}", "{block_number}"]`, Requires: []string{fixtureBlock, fixtureAddress}, Compare: true},
+	{ID: "eth_getStorageAt", Method: "eth_getStorageAt", Params: `["{address}", "0x0", "{block_number}"]`, Requires: []string{fixtureBlock, fixtureAddress}, Compare: true},
+	{ID: "eth_getProof", Method: "eth_getProof", Params: `["{address}", [], "{block_number}"]`, Requires: []string{fixtureBlock, fixtureAddress}, Compare: true},
+	{ID: "eth_call", Method: "eth_call", Params: `[{"to": "{address}", "data": "0x"}, "{block_number}"]`, Requires: []string{fixtureBlock, fixtureAddress}, Compare: true},
+	{ID: "eth_estimateGas", Method: "eth_estimateGas", Params: `[{"from": "{address}", "to": "{address}", "value": "0x0"}, "{block_number}"]`, Requires: []string{fixtureBlock, fixtureAddress}, Compare: true},
+	{ID: "eth_createAccessList", Method: "eth_createAccessList", Params: `[{"from": "{address}", "to": "{address}", "value": "0x0"}, "{block_number}"]`, Requires: []string{fixtureBlock, fixtureAddress}, Compare: true},
+
+	// eth: transactions
+	{ID: "eth_getTransactionByHash", Method: "eth_getTransactionByHash", Params: `["{tx_hash}"]`, Requires: []string{fixtureTx}, Compare: true},
+	{ID: "eth_getTransactionByBlockHashAndIndex", Method: "eth_getTransactionByBlockHashAndIndex", Params: `["{tx_block_hash}", "{tx_index}"]`, Requires: []string{fixtureTx}, Compare: true},
+	{ID: "eth_getTransactionByBlockNumberAndIndex", Method: "eth_getTransactionByBlockNumberAndIndex", Params: `["{tx_block_number}", "{tx_index}"]`, Requires: []string{fixtureTx}, Compare: true},
+	{ID: "eth_getTransactionReceipt", Method: "eth_getTransactionReceipt", Params: `["{tx_hash}"]`, Requires: []string{fixtureTx}, Compare: true},
+
+	// debug
+	{ID: "debug_getRawHeader", Method: "debug_getRawHeader", Params: `["{block_number}"]`, Requires: []string{fixtureBlock}, Compare: true},
+	{ID: "debug_getRawBlock", Method: "debug_getRawBlock", Params: `["{block_number}"]`, Requires: []string{fixtureBlock}, Compare: true},
+	{ID: "debug_getRawReceipts", Method: "debug_getRawReceipts", Params: `["{block_number}"]`, Requires: []string{fixtureBlock}, Compare: true},
+	{ID: "debug_getRawTransaction", Method: "debug_getRawTransaction", Params: `["{tx_hash}"]`, Requires: []string{fixtureTx}, Compare: true},
+	{ID: "debug_getBadBlocks", Method: "debug_getBadBlocks", Params: `[]`},
+}
+
+func (c *rpcCheck) Namespace() string {
+	namespace, _, _ := strings.Cut(c.Method, "_")
+	return namespace
+}
+
+func (c *rpcCheck) requires(fixture string) bool {
+	for _, required := range c.Requires {
+		if required == fixture {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isKnownNamespace(namespace string) bool {
+	for _, known := range executionAPINamespaces {
+		if known == namespace {
+			return true
+		}
+	}
+
+	return false
+}
+
+func loadExecutionAPISpec() (*jsonschema.Validator, error) {
+	executionAPISpecMutex.Lock()
+	defer executionAPISpecMutex.Unlock()
+
+	if executionAPIValidator != nil {
+		return executionAPIValidator, nil
+	}
+
+	spec := map[string]interface{}{}
+	if err := yaml.Unmarshal(executionAPISpecYaml, &spec); err != nil {
+		return nil, fmt.Errorf("failed parsing embedded execution api spec: %w", err)
+	}
+
+	methods, _ := spec["methods"].([]interface{})
+	results := make(map[string]interface{}, len(methods))
+
+	for _, method := range methods {
+		methodSpec, _ := method.(map[string]interface{})
+		methodName, _ := methodSpec["name"].(string)
+		methodResult, _ := methodSpec["result"].(map[string]interface{})
+
+		if methodName != "" && methodResult != nil {
+			results[methodName] = methodResult["schema"]
+		}
+	}
+
+	executionAPIResults = results
+	executionAPIValidator = jsonschema.NewValidator(spec)
+
+	return executionAPIValidator, nil
+}
+
+// getResultSchema returns the result schema of the given method from the openrpc spec.
+func getResultSchema(method string) (interface{}, error) {
+	schema := executionAPIResults[method]
+	if schema == nil {
+		return nil, fmt.Errorf("method %v not found in spec", method)
+	}
+
+	return schema, nil
+}
@@ -0,0 +1,54 @@
+package checkexecutionrpcconformance
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type Config struct {
+	ClientPattern        string          `yaml:"clientPattern" json:"clientPattern"`
+	ExcludeClientPattern string          `yaml:"excludeClientPattern" json:"excludeClientPattern"`
+	Namespaces           []string        `yaml:"namespaces" json:"namespaces"`
+	Methods              []string        `yaml:"methods" json:"methods"`
+	ExcludeMethods       []string        `yaml:"excludeMethods" json:"excludeMethods"`
+	TransactionHashes    []string        `yaml:"transactionHashes" json:"transactionHashes"`
+	BlockNumber          uint64          `yaml:"blockNumber" json:"blockNumber"`
+	BlockDistance        uint64          `yaml:"blockDistance" json:"blockDistance"`
+	Address              string          `yaml:"address" json:"address"`
+	CompareResults       bool            `yaml:"compareResults" json:"compareResults"`
+	RequestTimeout       helper.Duration `yaml:"requestTimeout" json:"requestTimeout"`
+	MinClientCount       int             `yaml:"minClientCount" json:"minClientCount"`
+	FailOnMismatch       bool            `yaml:"failOnMismatch" json:"failOnMismatch"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		BlockDistance:  2,
+		CompareResults: true,
+		RequestTimeout: helper.Duration{Duration: 30 * time.Second},
+		FailOnMismatch: true,
+	}
+}
+
+func (c *Config) Validate() error {
+	for _, namespace := range c.Namespaces {
+		if !isKnownNamespace(namespace) {
+			return fmt.Errorf("unknown rpc namespace: %v", namespace)
+		}
+	}
+
+	for _, txHash := range c.TransactionHashes {
+		if len(common.FromHex(txHash)) != common.HashLength {
+			return fmt.Errorf("invalid transaction hash: %v", txHash)
+		}
+	}
+
+	if c.Address != "" && !common.IsHexAddress(c.Address) {
+		return fmt.Errorf("invalid address: %v", c.Address)
+	}
+
+	return nil
+}
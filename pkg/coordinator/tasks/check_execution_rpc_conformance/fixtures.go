@@ -0,0 +1,188 @@
+package checkexecutionrpcconformance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Fixtures holds the blocks, transactions & accounts the rpc checks are run against.
+type Fixtures struct {
+	BlockNumber  uint64                `json:"blockNumber"`
+	BlockHash    string                `json:"blockHash"`
+	Address      string                `json:"address"`
+	Transactions []*TransactionFixture `json:"transactions"`
+}
+
+type TransactionFixture struct {
+	Hash        string `json:"hash"`
+	BlockHash   string `json:"blockHash"`
+	BlockNumber string `json:"blockNumber"`
+	Index       string `json:"index"`
+	From        string `json:"from"`
+}
+
+type rpcTransaction struct {
+	Hash             string `json:"hash"`
+	BlockHash        string `json:"blockHash"`
+	BlockNumber      string `json:"blockNumber"`
+	TransactionIndex string `json:"transactionIndex"`
+	From             string `json:"from"`
+}
+
+type rpcBlock struct {
+	Hash         string            `json:"hash"`
+	Miner        string            `json:"miner"`
+	Transactions []*rpcTransaction `json:"transactions"`
+}
+
+// loadFixtures resolves the fixture transactions & block from the first client that is able to serve them.
+func (t *Task) loadFixtures(ctx context.Context, poolClients []*clients.PoolClient) (*Fixtures, error) {
+	fixtures := &Fixtures{
+		Transactions: []*TransactionFixture{},
+	}
+
+	for _, txHash := range t.config.TransactionHashes {
+		tx := &rpcTransaction{}
+		if err := t.callFirstClient(ctx, poolClients, "eth_getTransactionByHash", []interface{}{txHash}, tx); err != nil {
+			return nil, fmt.Errorf("failed loading fixture transaction %v: %w", txHash, err)
+		}
+
+		if tx.BlockHash == "" {
+			return nil, fmt.Errorf("fixture transaction %v is not included in a block", txHash)
+		}
+
+		fixtures.Transactions = append(fixtures.Transactions, &TransactionFixture{
+			Hash:        tx.Hash,
+			BlockHash:   tx.BlockHash,
+			BlockNumber: tx.BlockNumber,
+			Index:       tx.TransactionIndex,
+			From:        tx.From,
+		})
+	}
+
+	switch {
+	case t.config.BlockNumber > 0:
+		fixtures.BlockNumber = t.config.BlockNumber
+	case len(fixtures.Transactions) > 0:
+		blockNumber, err := hexutil.DecodeUint64(fixtures.Transactions[0].BlockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("invalid block number of fixture transaction: %w", err)
+		}
+
+		fixtures.BlockNumber = blockNumber
+	default:
+		headNumber := uint64(0)
+
+		for _, client := range poolClients {
+			if number, _ := client.ExecutionClient.GetLastHead(); number > headNumber {
+				headNumber = number
+			}
+		}
+
+		if headNumber > t.config.BlockDistance {
+			fixtures.BlockNumber = headNumber - t.config.BlockDistance
+		}
+	}
+
+	block := &rpcBlock{}
+	if err := t.callFirstClient(ctx, poolClients, "eth_getBlockByNumber", []interface{}{hexutil.EncodeUint64(fixtures.BlockNumber), true}, block); err != nil {
+		return nil, fmt.Errorf("failed loading fixture block %v: %w", fixtures.BlockNumber, err)
+	}
+
+	fixtures.BlockHash = block.Hash
+
+	// use the first transaction of the fixture block if no transactions were given
+	if len(t.config.TransactionHashes) == 0 && len(block.Transactions) > 0 {
+		tx := block.Transactions[0]
+		fixtures.Transactions = append(fixtures.Transactions, &TransactionFixture{
+			Hash:        tx.Hash,
+			BlockHash:   tx.BlockHash,
+			BlockNumber: tx.BlockNumber,
+			Index:       tx.TransactionIndex,
+			From:        tx.From,
+		})
+	}
+
+	switch {
+	case t.config.Address != "":
+		fixtures.Address = t.config.Address
+	case len(fixtures.Transactions) > 0:
+		fixtures.Address = fixtures.Transactions[0].From
+	default:
+		fixtures.Address = block.Miner
+	}
+
+	return fixtures, nil
+}
+
+func (t *Task) callFirstClient(ctx context.Context, poolClients []*clients.PoolClient, method string, params []interface{}, result interface{}) error {
+	paramsData, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+
+	for _, client := range poolClients {
+		rspData, err := t.callRPC(ctx, client, method, paramsData)
+		if err != nil {
+			lastErr = fmt.Errorf("%v: %w", client.Config.Name, err)
+			continue
+		}
+
+		if string(rspData) == "null" {
+			lastErr = fmt.Errorf("%v: not found", client.Config.Name)
+			continue
+		}
+
+		return json.Unmarshal(rspData, result)
+	}
+
+	return lastErr
+}
+
+func (f *Fixtures) hasFixture(fixture string) bool {
+	switch fixture {
+	case fixtureBlock:
+		return f.BlockHash != ""
+	case fixtureAddress:
+		return f.Address != ""
+	case fixtureTx:
+		return len(f.Transactions) > 0
+	}
+
+	return false
+}
+
+func (f *Fixtures) getReplacer(tx *TransactionFixture) *strings.Replacer {
+	replacements := []string{
+		"{block_number}", hexutil.EncodeUint64(f.BlockNumber),
+		"{block_hash}", f.BlockHash,
+		"{address}", f.Address,
+	}
+
+	if tx != nil {
+		replacements = append(replacements,
+			"{tx_hash}", tx.Hash,
+			"{tx_block_hash}", tx.BlockHash,
+			"{tx_block_number}", tx.BlockNumber,
+			"{tx_index}", tx.Index,
+		)
+	}
+
+	return strings.NewReplacer(replacements...)
+}
+
+func (f *Fixtures) getCheckID(check *rpcCheck, txIdx int) string {
+	if !check.requires(fixtureTx) || len(f.Transactions) <= 1 {
+		return check.ID
+	}
+
+	return check.ID + "#" + strconv.Itoa(txIdx)
+}
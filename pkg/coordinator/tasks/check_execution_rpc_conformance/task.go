@@ -0,0 +1,561 @@
+package checkexecutionrpcconformance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+	"github.com/erigontech/assertoor/pkg/coordinator/db"
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/erigontech/assertoor/pkg/coordinator/utils/jsonschema"
+	"github.com/erigontech/assertoor/pkg/coordinator/vars"
+	"github.com/jmoiron/sqlx"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	TaskName       = "check_execution_rpc_conformance"
+	TaskDescriptor = &types.TaskDescriptor{
+		Name:        TaskName,
+		Description: "Checks the json-rpc api of execution clients against the execution-apis openrpc specification.",
+		Config:      DefaultConfig(),
+		NewTask:     NewTask,
+	}
+)
+
+const (
+	checkResultPass     = "pass"
+	checkResultFail     = "fail"
+	checkResultMismatch = "mismatch"
+	checkResultSkip     = "skip"
+)
+
+type Task struct {
+	ctx        *types.TaskContext
+	options    *types.TaskOptions
+	config     Config
+	logger     logrus.FieldLogger
+	validator  *jsonschema.Validator
+	httpClient *http.Client
+}
+
+type ClientResult struct {
+	Client     string         `json:"client"`
+	Version    string         `json:"version"`
+	Passed     int            `json:"passed"`
+	Failed     int            `json:"failed"`
+	Mismatched int            `json:"mismatched"`
+	Skipped    int            `json:"skipped"`
+	Checks     []*CheckResult `json:"checks"`
+}
+
+type CheckResult struct {
+	ID         string   `json:"id"`
+	Method     string   `json:"method"`
+	Params     string   `json:"params"`
+	Result     string   `json:"result"`
+	DurationMs int64    `json:"durationMs"`
+	Errors     []string `json:"errors,omitempty"`
+	response   string
+}
+
+// checkInstance is a rpc check with resolved parameters.
+type checkInstance struct {
+	id         string
+	check      *rpcCheck
+	params     string
+	skipReason string
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func NewTask(ctx *types.TaskContext, options *types.TaskOptions) (types.Task, error) {
+	return &Task{
+		ctx:     ctx,
+		options: options,
+		logger:  ctx.Logger.GetLogger(),
+	}, nil
+}
+
+func (t *Task) Config() interface{} {
+	return t.config
+}
+
+func (t *Task) Timeout() time.Duration {
+	return t.options.Timeout.Duration
+}
+
+func (t *Task) LoadConfig() error {
+	config := DefaultConfig()
+
+	// parse static config
+	if t.options.Config != nil {
+		if err := t.options.Config.Unmarshal(&config); err != nil {
+			return fmt.Errorf("error parsing task config for %v: %w", TaskName, err)
+		}
+	}
+
+	// load dynamic vars
+	err := t.ctx.Vars.ConsumeVars(&config, t.options.ConfigVars)
+	if err != nil {
+		return err
+	}
+
+	// validate config
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	t.config = config
+
+	return nil
+}
+
+func (t *Task) Execute(ctx context.Context) error {
+	validator, err := loadExecutionAPISpec()
+	if err != nil {
+		return err
+	}
+
+	t.validator = validator
+	t.httpClient = &http.Client{}
+
+	poolClients := []*clients.PoolClient{}
+
+	for _, client := range t.ctx.Scheduler.GetServices().ClientPool().GetClientsByNamePatterns(t.config.ClientPattern, t.config.ExcludeClientPattern) {
+		if client.ExecutionClient != nil {
+			poolClients = append(poolClients, client)
+		}
+	}
+
+	if len(poolClients) == 0 || len(poolClients) < t.config.MinClientCount {
+		t.ctx.SetResult(types.TaskResultFailure)
+		return fmt.Errorf("not enough matching execution clients (have: %v, want: %v)", len(poolClients), max(t.config.MinClientCount, 1))
+	}
+
+	fixtures, err := t.loadFixtures(ctx, poolClients)
+	if err != nil {
+		t.ctx.SetResult(types.TaskResultFailure)
+		return err
+	}
+
+	if fixturesData, err := vars.GeneralizeData(fixtures); err == nil {
+		t.ctx.Outputs.SetVar("fixtures", fixturesData)
+	} else {
+		t.logger.Warnf("Failed setting `fixtures` output: %v", err)
+	}
+
+	checks := t.getCheckInstances(fixtures)
+	if len(checks) == 0 {
+		return fmt.Errorf("no rpc checks selected")
+	}
+
+	t.logger.Infof("running %v rpc checks against %v clients (block: %v, transactions: %v)", len(checks), len(poolClients), fixtures.BlockNumber, len(fixtures.Transactions))
+
+	results := make([]*ClientResult, len(poolClients))
+	wg := sync.WaitGroup{}
+
+	for idx, client := range poolClients {
+		wg.Add(1)
+
+		go func(idx int, client *clients.PoolClient) {
+			defer wg.Done()
+
+			results[idx] = t.runClientChecks(ctx, client, checks)
+		}(idx, client)
+	}
+
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if t.config.CompareResults {
+		t.compareResults(checks, results)
+	}
+
+	totalFailed := 0
+	totalMismatched := 0
+	matrix := map[string]map[string]string{}
+
+	for _, clientResult := range results {
+		for _, checkResult := range clientResult.Checks {
+			if matrix[checkResult.ID] == nil {
+				matrix[checkResult.ID] = map[string]string{}
+			}
+
+			matrix[checkResult.ID][clientResult.Client] = checkResult.Result
+
+			switch checkResult.Result {
+			case checkResultPass:
+				clientResult.Passed++
+			case checkResultFail:
+				clientResult.Failed++
+			case checkResultMismatch:
+				clientResult.Mismatched++
+			case checkResultSkip:
+				clientResult.Skipped++
+			}
+		}
+
+		totalFailed += clientResult.Failed
+		totalMismatched += clientResult.Mismatched
+
+		if clientResult.Failed > 0 || clientResult.Mismatched > 0 {
+			t.logger.Warnf("client %v failed %v and mismatched %v of %v rpc checks", clientResult.Client, clientResult.Failed, clientResult.Mismatched, len(clientResult.Checks))
+		} else {
+			t.logger.Infof("client %v passed all %v rpc checks (%v skipped)", clientResult.Client, clientResult.Passed, clientResult.Skipped)
+		}
+	}
+
+	if matrixData, err := vars.GeneralizeData(matrix); err == nil {
+		t.ctx.Outputs.SetVar("matrix", matrixData)
+	} else {
+		t.logger.Warnf("Failed setting `matrix` output: %v", err)
+	}
+
+	if resultsData, err := vars.GeneralizeData(results); err == nil {
+		t.ctx.Outputs.SetVar("results", resultsData)
+	} else {
+		t.logger.Warnf("Failed setting `results` output: %v", err)
+	}
+
+	t.ctx.Outputs.SetVar("failedCount", totalFailed)
+	t.ctx.Outputs.SetVar("mismatchCount", totalMismatched)
+
+	t.storeTaskResults(checks, results)
+
+	switch {
+	case totalFailed == 0 && totalMismatched == 0:
+		t.ctx.SetResult(types.TaskResultSuccess)
+	case t.config.FailOnMismatch:
+		t.ctx.SetResult(types.TaskResultFailure)
+	default:
+		t.ctx.SetResult(types.TaskResultNone)
+	}
+
+	return nil
+}
+
+func (t *Task) getCheckInstances(fixtures *Fixtures) []*checkInstance {
+	instances := []*checkInstance{}
+
+	for _, check := range executionAPIChecks {
+		if len(t.config.Namespaces) > 0 && !slices.Contains(t.config.Namespaces, check.Namespace()) {
+			continue
+		}
+
+		if len(t.config.Methods) > 0 && !slices.Contains(t.config.Methods, check.Method) && !slices.Contains(t.config.Methods, check.ID) {
+			continue
+		}
+
+		if slices.Contains(t.config.ExcludeMethods, check.Method) || slices.Contains(t.config.ExcludeMethods, check.ID) {
+			continue
+		}
+
+		skipReason := ""
+
+		for _, fixture := range check.Requires {
+			if !fixtures.hasFixture(fixture) {
+				skipReason = fmt.Sprintf("no %v fixture available", fixture)
+				break
+			}
+		}
+
+		if !check.requires(fixtureTx) || skipReason != "" {
+			instances = append(instances, &checkInstance{
+				id:         check.ID,
+				check:      check,
+				params:     fixtures.getReplacer(nil).Replace(check.Params),
+				skipReason: skipReason,
+			})
+
+			continue
+		}
+
+		for txIdx, tx := range fixtures.Transactions {
+			instances = append(instances, &checkInstance{
+				id:     fixtures.getCheckID(check, txIdx),
+				check:  check,
+				params: fixtures.getReplacer(tx).Replace(check.Params),
+			})
+		}
+	}
+
+	return instances
+}
+
+func (t *Task) runClientChecks(ctx context.Context, client *clients.PoolClient, checks []*checkInstance) *ClientResult {
+	result := &ClientResult{
+		Client:  client.Config.Name,
+		Version: client.ExecutionClient.GetVersion(),
+		Checks:  make([]*CheckResult, 0, len(checks)),
+	}
+
+	for _, check := range checks {
+		if ctx.Err() != nil {
+			break
+		}
+
+		checkResult := t.runCheck(ctx, client, check)
+		result.Checks = append(result.Checks, checkResult)
+
+		if checkResult.Result == checkResultFail {
+			t.logger.Debugf("client %v failed check %v: %v", client.Config.Name, check.id, strings.Join(checkResult.Errors, ", "))
+		}
+	}
+
+	return result
+}
+
+func (t *Task) runCheck(ctx context.Context, client *clients.PoolClient, check *checkInstance) *CheckResult {
+	result := &CheckResult{
+		ID:     check.id,
+		Method: check.check.Method,
+		Params: check.params,
+	}
+
+	if check.skipReason != "" {
+		result.Result = checkResultSkip
+		result.Errors = []string{check.skipReason}
+
+		return result
+	}
+
+	startTime := time.Now()
+	rspData, err := t.callRPC(ctx, client, check.check.Method, json.RawMessage(check.params))
+	result.DurationMs = time.Since(startTime).Milliseconds()
+
+	if err != nil {
+		result.Result = checkResultFail
+		result.Errors = []string{err.Error()}
+
+		return result
+	}
+
+	var rspValue interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(rspData))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&rspValue); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("failed decoding result: %v", err))
+	} else if schema, err := getResultSchema(check.check.Method); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	} else {
+		result.Errors = append(result.Errors, t.validator.Validate(schema, rspValue)...)
+
+		if normalized, err := json.Marshal(rspValue); err == nil {
+			result.response = string(normalized)
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		result.Result = checkResultFail
+	} else {
+		result.Result = checkResultPass
+	}
+
+	return result
+}
+
+func (t *Task) callRPC(ctx context.Context, client *clients.PoolClient, method string, params json.RawMessage) (json.RawMessage, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+	defer cancel()
+
+	reqData, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  method,
+		"params":  params,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	endpointConfig := client.ExecutionClient.GetEndpointConfig()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, endpointConfig.URL, bytes.NewReader(reqData))
+	if err != nil {
+		return nil, err
+	}
+
+	for headerName, headerValue := range endpointConfig.Headers {
+		req.Header.Set(headerName, headerValue)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	rsp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	defer rsp.Body.Close()
+
+	rspData, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading response: %w", err)
+	}
+
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %v", rsp.StatusCode)
+	}
+
+	rpcRsp := &rpcResponse{}
+	if err := json.Unmarshal(rspData, rpcRsp); err != nil {
+		return nil, fmt.Errorf("invalid json-rpc response: %w", err)
+	}
+
+	if rpcRsp.Error != nil {
+		return nil, fmt.Errorf("rpc error %v: %v", rpcRsp.Error.Code, rpcRsp.Error.Message)
+	}
+
+	if rpcRsp.Result == nil {
+		return nil, fmt.Errorf("json-rpc response without result")
+	}
+
+	return rpcRsp.Result, nil
+}
+
+// compareResults marks results that differ from the result returned by the majority of clients.
+func (t *Task) compareResults(checks []*checkInstance, results []*ClientResult) {
+	for checkIdx, check := range checks {
+		if !check.check.Compare {
+			continue
+		}
+
+		responseCounts := map[string]int{}
+		majorityResponse := ""
+
+		for _, clientResult := range results {
+			if checkIdx >= len(clientResult.Checks) || clientResult.Checks[checkIdx].Result != checkResultPass {
+				continue
+			}
+
+			response := clientResult.Checks[checkIdx].response
+			responseCounts[response]++
+
+			if responseCounts[response] > responseCounts[majorityResponse] {
+				majorityResponse = response
+			}
+		}
+
+		if len(responseCounts) <= 1 {
+			continue
+		}
+
+		for _, clientResult := range results {
+			if checkIdx >= len(clientResult.Checks) || clientResult.Checks[checkIdx].Result != checkResultPass {
+				continue
+			}
+
+			checkResult := clientResult.Checks[checkIdx]
+			if checkResult.response != majorityResponse {
+				checkResult.Result = checkResultMismatch
+				checkResult.Errors = append(checkResult.Errors, fmt.Sprintf("result differs from %v of %v clients", responseCounts[majorityResponse], len(results)))
+			}
+		}
+	}
+}
+
+func (t *Task) storeTaskResults(checks []*checkInstance, results []*ClientResult) {
+	summary := t.buildSummary(checks, results)
+
+	resultData, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		t.logger.Errorf("failed serializing conformance results: %v", err)
+		return
+	}
+
+	database := t.ctx.Scheduler.GetServices().Database()
+	if err := database.RunTransaction(func(tx *sqlx.Tx) error {
+		if err := database.UpsertTaskResult(tx, &db.TaskResult{
+			RunID:  t.ctx.Scheduler.GetTestRunID(),
+			TaskID: uint64(t.ctx.Index),
+			Type:   "summary",
+			Index:  0,
+			Name:   "",
+			Size:   uint64(len(summary)),
+			Data:   summary,
+		}); err != nil {
+			return err
+		}
+
+		return database.UpsertTaskResult(tx, &db.TaskResult{
+			RunID:  t.ctx.Scheduler.GetTestRunID(),
+			TaskID: uint64(t.ctx.Index),
+			Type:   "result",
+			Index:  0,
+			Name:   "execution-rpc-conformance.json",
+			Size:   uint64(len(resultData)),
+			Data:   resultData,
+		})
+	}); err != nil {
+		t.logger.Errorf("failed storing conformance results to db: %v", err)
+	}
+}
+
+// buildSummary renders the per-client, per-method conformance matrix as markdown.
+func (t *Task) buildSummary(checks []*checkInstance, results []*ClientResult) []byte {
+	summary := &strings.Builder{}
+
+	fmt.Fprintf(summary, "# Execution JSON-RPC conformance\n\n| Method |")
+
+	for _, clientResult := range results {
+		fmt.Fprintf(summary, " %v |", clientResult.Client)
+	}
+
+	fmt.Fprintf(summary, "\n|---|%v\n", strings.Repeat("---|", len(results)))
+
+	for checkIdx, check := range checks {
+		fmt.Fprintf(summary, "| `%v` |", check.id)
+
+		for _, clientResult := range results {
+			if checkIdx < len(clientResult.Checks) {
+				fmt.Fprintf(summary, " %v |", clientResult.Checks[checkIdx].Result)
+			} else {
+				fmt.Fprintf(summary, " - |")
+			}
+		}
+
+		fmt.Fprintf(summary, "\n")
+	}
+
+	failures := []string{}
+
+	for _, clientResult := range results {
+		for _, checkResult := range clientResult.Checks {
+			if checkResult.Result == checkResultSkip {
+				continue
+			}
+
+			for _, checkError := range checkResult.Errors {
+				failures = append(failures, fmt.Sprintf("- %v `%v`: %v", clientResult.Client, checkResult.ID, checkError))
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		fmt.Fprintf(summary, "\n## Failures\n\n%v\n", strings.Join(failures, "\n"))
+	}
+
+	return []byte(summary.String())
+}
@@ -0,0 +1,173 @@
+package checkforktransition
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// enrForkID is the `eth2` entry of the beacon node ENR.
+type enrForkID struct {
+	ForkDigest      phase0.ForkDigest
+	NextForkVersion phase0.Version
+	NextForkEpoch   uint64
+}
+
+func (t *Task) getENRForkID(ctx context.Context, client *clients.PoolClient) (*enrForkID, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+	defer cancel()
+
+	identity, err := client.ConsensusClient.GetRPCClient().GetNodeIdentity(reqCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := enode.Parse(enode.ValidSchemes, identity.ENR)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enr: %w", err)
+	}
+
+	var eth2Data []byte
+	if err := node.Load(enr.WithEntry("eth2", &eth2Data)); err != nil {
+		return nil, fmt.Errorf("enr has no eth2 entry: %w", err)
+	}
+
+	if len(eth2Data) != 16 {
+		return nil, fmt.Errorf("invalid eth2 enr entry length: %v", len(eth2Data))
+	}
+
+	forkID := &enrForkID{
+		NextForkEpoch: binary.LittleEndian.Uint64(eth2Data[8:16]),
+	}
+	copy(forkID.ForkDigest[:], eth2Data[0:4])
+	copy(forkID.NextForkVersion[:], eth2Data[4:8])
+
+	return forkID, nil
+}
+
+// computeForkDigest computes the fork digest for the given epoch, including the blob parameter mask introduced with fulu.
+func computeForkDigest(version phase0.Version, genesisValidatorsRoot phase0.Root, epoch uint64, specValues map[string]interface{}) (phase0.ForkDigest, error) {
+	forkData := &phase0.ForkData{
+		CurrentVersion:        version,
+		GenesisValidatorsRoot: genesisValidatorsRoot,
+	}
+
+	forkDataRoot, err := forkData.HashTreeRoot()
+	if err != nil {
+		return phase0.ForkDigest{}, err
+	}
+
+	fuluEpoch, hasFulu := getSpecUint(specValues, "FULU_FORK_EPOCH")
+	if hasFulu && epoch >= fuluEpoch {
+		blobParamsEpoch, maxBlobs, err := getBlobParameters(specValues, epoch)
+		if err != nil {
+			return phase0.ForkDigest{}, err
+		}
+
+		blobParamsData := make([]byte, 16)
+		binary.LittleEndian.PutUint64(blobParamsData[0:8], blobParamsEpoch)
+		binary.LittleEndian.PutUint64(blobParamsData[8:16], maxBlobs)
+		blobParamsHash := sha256.Sum256(blobParamsData)
+
+		for i := range forkDataRoot {
+			forkDataRoot[i] ^= blobParamsHash[i]
+		}
+	}
+
+	digest := phase0.ForkDigest{}
+	copy(digest[:], forkDataRoot[:4])
+
+	return digest, nil
+}
+
+// getBlobParameters returns the blob schedule entry that is active at the given epoch.
+func getBlobParameters(specValues map[string]interface{}, epoch uint64) (activationEpoch, maxBlobs uint64, err error) {
+	type blobScheduleEntry struct {
+		epoch    uint64
+		maxBlobs uint64
+	}
+
+	schedule := []blobScheduleEntry{}
+
+	if scheduleList, isList := specValues["BLOB_SCHEDULE"].([]interface{}); isList {
+		for _, entry := range scheduleList {
+			entryMap, isMap := entry.(map[string]interface{})
+			if !isMap {
+				continue
+			}
+
+			entryEpoch, hasEpoch := getSpecUint(entryMap, "EPOCH")
+			entryMaxBlobs, hasMaxBlobs := getSpecUint(entryMap, "MAX_BLOBS_PER_BLOCK")
+
+			if hasEpoch && hasMaxBlobs {
+				schedule = append(schedule, blobScheduleEntry{entryEpoch, entryMaxBlobs})
+			}
+		}
+	}
+
+	sort.Slice(schedule, func(a, b int) bool {
+		return schedule[a].epoch > schedule[b].epoch
+	})
+
+	for _, entry := range schedule {
+		if epoch >= entry.epoch {
+			return entry.epoch, entry.maxBlobs, nil
+		}
+	}
+
+	electraEpoch, hasElectraEpoch := getSpecUint(specValues, "ELECTRA_FORK_EPOCH")
+	electraMaxBlobs, hasElectraMaxBlobs := getSpecUint(specValues, "MAX_BLOBS_PER_BLOCK_ELECTRA")
+
+	if !hasElectraEpoch || !hasElectraMaxBlobs {
+		return 0, 0, errors.New("no blob parameters found in spec")
+	}
+
+	return electraEpoch, electraMaxBlobs, nil
+}
+
+func getSpecUint(specValues map[string]interface{}, key string) (uint64, bool) {
+	switch value := specValues[key].(type) {
+	case string:
+		number, err := strconv.ParseUint(value, 10, 64)
+		return number, err == nil
+	case float64:
+		return uint64(value), true
+	case uint64:
+		return value, true
+	}
+
+	return 0, false
+}
+
+// getExpectedFork returns the fork that is active at the given epoch.
+func getExpectedFork(forkSchedule []*consensus.ForkVersion, epoch uint64) *consensus.ForkVersion {
+	var activeFork *consensus.ForkVersion
+
+	for _, fork := range forkSchedule {
+		if fork.Epoch <= epoch {
+			activeFork = fork
+		}
+	}
+
+	return activeFork
+}
+
+func isMethodNotFound(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == -32601
+	}
+
+	return false
+}
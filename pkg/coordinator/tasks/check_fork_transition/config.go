@@ -0,0 +1,40 @@
+package checkforktransition
+
+import (
+	"errors"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+)
+
+type Config struct {
+	ClientPattern         string          `yaml:"clientPattern" json:"clientPattern"`
+	ExcludeClientPattern  string          `yaml:"excludeClientPattern" json:"excludeClientPattern"`
+	Forks                 []string        `yaml:"forks" json:"forks"`
+	FinalityEpochs        uint64          `yaml:"finalityEpochs" json:"finalityEpochs"`
+	MaxUnfinalizedEpochs  uint64          `yaml:"maxUnfinalizedEpochs" json:"maxUnfinalizedEpochs"`
+	CheckBlockProduction  bool            `yaml:"checkBlockProduction" json:"checkBlockProduction"`
+	CheckForkDigest       bool            `yaml:"checkForkDigest" json:"checkForkDigest"`
+	CheckExecutionConfig  bool            `yaml:"checkExecutionConfig" json:"checkExecutionConfig"`
+	RequestTimeout        helper.Duration `yaml:"requestTimeout" json:"requestTimeout"`
+	ContinueOnForkFailure bool            `yaml:"continueOnForkFailure" json:"continueOnForkFailure"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		FinalityEpochs:       4,
+		MaxUnfinalizedEpochs: 3,
+		CheckBlockProduction: true,
+		CheckForkDigest:      true,
+		CheckExecutionConfig: true,
+		RequestTimeout:       helper.Duration{Duration: 10 * time.Second},
+	}
+}
+
+func (c *Config) Validate() error {
+	if c.FinalityEpochs == 0 {
+		return errors.New("finalityEpochs must be at least 1")
+	}
+
+	return nil
+}
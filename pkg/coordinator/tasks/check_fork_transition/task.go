@@ -0,0 +1,259 @@
+package checkforktransition
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus"
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/erigontech/assertoor/pkg/coordinator/vars"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	TaskName       = "check_fork_transition"
+	TaskDescriptor = &types.TaskDescriptor{
+		Name:        TaskName,
+		Description: "Checks that all clients transition to scheduled forks and keep finalizing afterwards.",
+		Config:      DefaultConfig(),
+		NewTask:     NewTask,
+	}
+)
+
+type Task struct {
+	ctx          *types.TaskContext
+	options      *types.TaskOptions
+	config       Config
+	logger       logrus.FieldLogger
+	forkSchedule []*consensus.ForkVersion
+	poolClients  []*clients.PoolClient
+}
+
+func NewTask(ctx *types.TaskContext, options *types.TaskOptions) (types.Task, error) {
+	return &Task{
+		ctx:     ctx,
+		options: options,
+		logger:  ctx.Logger.GetLogger(),
+	}, nil
+}
+
+func (t *Task) Config() interface{} {
+	return t.config
+}
+
+func (t *Task) Timeout() time.Duration {
+	return t.options.Timeout.Duration
+}
+
+func (t *Task) LoadConfig() error {
+	config := DefaultConfig()
+
+	// parse static config
+	if t.options.Config != nil {
+		if err := t.options.Config.Unmarshal(&config); err != nil {
+			return fmt.Errorf("error parsing task config for %v: %w", TaskName, err)
+		}
+	}
+
+	// load dynamic vars
+	err := t.ctx.Vars.ConsumeVars(&config, t.options.ConfigVars)
+	if err != nil {
+		return err
+	}
+
+	// validate config
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	t.config = config
+
+	return nil
+}
+
+func (t *Task) Execute(ctx context.Context) error {
+	blockCache := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetBlockCache()
+
+	// subscribe before selecting the forks, so no events get lost in between
+	blockSubscription := blockCache.SubscribeBlockEvent(32)
+	defer blockSubscription.Unsubscribe()
+
+	slotSubscription := blockCache.SubscribeWallclockSlotEvent(10)
+	defer slotSubscription.Unsubscribe()
+
+	epochSubscription := blockCache.SubscribeWallclockEpochEvent(10)
+	defer epochSubscription.Unsubscribe()
+
+	_, currentEpoch, err := blockCache.GetWallclock().Now()
+	if err != nil {
+		return fmt.Errorf("failed fetching wallclock: %w", err)
+	}
+
+	t.forkSchedule = blockCache.GetForkSchedule()
+
+	targetForks, err := t.getTargetForks(currentEpoch.Number())
+	if err != nil {
+		t.ctx.SetResult(types.TaskResultFailure)
+		return err
+	}
+
+	for _, client := range t.ctx.Scheduler.GetServices().ClientPool().GetClientsByNamePatterns(t.config.ClientPattern, t.config.ExcludeClientPattern) {
+		if client.ConsensusClient != nil {
+			t.poolClients = append(t.poolClients, client)
+		}
+	}
+
+	if len(t.poolClients) == 0 {
+		t.ctx.SetResult(types.TaskResultFailure)
+		return fmt.Errorf("no matching consensus clients found")
+	}
+
+	trackers := make([]*forkTracker, len(targetForks))
+	for idx, fork := range targetForks {
+		trackers[idx] = t.newForkTracker(fork)
+		t.logger.Infof("checking %v fork transition at epoch %v (activation time: %v)", fork.Name, fork.Epoch, trackers[idx].result.ActivationTime)
+
+		if currentEpoch.Number() < fork.Epoch {
+			trackers[idx].runPreForkChecks(ctx, currentEpoch.Number())
+		}
+	}
+
+	t.setOutputs(trackers)
+
+	for {
+		select {
+		case block := <-blockSubscription.Channel():
+			for _, tracker := range trackers {
+				tracker.processBlock(ctx, block)
+			}
+		case slot := <-slotSubscription.Channel():
+			for _, tracker := range trackers {
+				tracker.processSlot(ctx, slot.Number())
+			}
+		case epoch := <-epochSubscription.Channel():
+			activeTrackers := 0
+			failedTrackers := 0
+
+			for _, tracker := range trackers {
+				tracker.processEpoch(ctx, epoch.Number())
+
+				switch {
+				case !tracker.completed:
+					activeTrackers++
+				case !tracker.result.Passed:
+					failedTrackers++
+				}
+			}
+
+			t.setOutputs(trackers)
+
+			if failedTrackers > 0 && !t.config.ContinueOnForkFailure {
+				t.ctx.SetResult(types.TaskResultFailure)
+				return fmt.Errorf("fork transition check failed")
+			}
+
+			if activeTrackers == 0 {
+				if failedTrackers > 0 {
+					t.ctx.SetResult(types.TaskResultFailure)
+					return fmt.Errorf("%v fork transition checks failed", failedTrackers)
+				}
+
+				t.ctx.SetResult(types.TaskResultSuccess)
+
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// getTargetForks returns the scheduled forks that should be checked.
+// Without explicit forks in the config, all forks whose observation window has not passed yet are checked.
+func (t *Task) getTargetForks(currentEpoch uint64) ([]*consensus.ForkVersion, error) {
+	targetForks := []*consensus.ForkVersion{}
+
+	for idx, fork := range t.forkSchedule {
+		if fork.Epoch == 0 || fork.Epoch == consensus.FarFutureEpoch {
+			continue
+		}
+
+		if idx+1 < len(t.forkSchedule) && t.forkSchedule[idx+1].Epoch == fork.Epoch {
+			// superseded by a later fork activating at the same epoch
+			continue
+		}
+
+		if len(t.config.Forks) > 0 && !slices.ContainsFunc(t.config.Forks, func(name string) bool {
+			return strings.EqualFold(name, fork.Name)
+		}) {
+			continue
+		}
+
+		if fork.Epoch+t.config.FinalityEpochs <= currentEpoch {
+			if len(t.config.Forks) > 0 {
+				return nil, fmt.Errorf("%v fork at epoch %v has already passed", fork.Name, fork.Epoch)
+			}
+
+			continue
+		}
+
+		targetForks = append(targetForks, fork)
+	}
+
+	for _, name := range t.config.Forks {
+		if !slices.ContainsFunc(targetForks, func(fork *consensus.ForkVersion) bool {
+			return strings.EqualFold(name, fork.Name)
+		}) {
+			return nil, fmt.Errorf("%v fork is not scheduled", name)
+		}
+	}
+
+	if len(targetForks) == 0 {
+		return nil, fmt.Errorf("no upcoming fork transitions found")
+	}
+
+	return targetForks, nil
+}
+
+// getClientByValidatorName maps a validator name to the client it belongs to.
+// Exact matches take precedence, otherwise the longest client name that contains or is contained in the validator name wins.
+func (t *Task) getClientByValidatorName(validatorName string) int {
+	if validatorName == "" {
+		return -1
+	}
+
+	matchIdx := -1
+	matchLen := 0
+
+	for idx, client := range t.poolClients {
+		clientName := client.Config.Name
+
+		if clientName == validatorName {
+			return idx
+		}
+
+		if (strings.Contains(validatorName, clientName) || strings.Contains(clientName, validatorName)) && len(clientName) > matchLen {
+			matchIdx = idx
+			matchLen = len(clientName)
+		}
+	}
+
+	return matchIdx
+}
+
+func (t *Task) setOutputs(trackers []*forkTracker) {
+	results := make([]*ForkResult, len(trackers))
+	for idx, tracker := range trackers {
+		results[idx] = tracker.result
+	}
+
+	if resultsData, err := vars.GeneralizeData(results); err == nil {
+		t.ctx.Outputs.SetVar("forks", resultsData)
+	} else {
+		t.logger.Warnf("failed setting `forks` output: %v", err)
+	}
+}
@@ -0,0 +1,444 @@
+package checkforktransition
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus"
+)
+
+const (
+	checkPending = "pending"
+	checkPass    = "pass"
+	checkSkipped = "skipped"
+)
+
+// forks that are activated by block number or terminal difficulty on the execution layer, so there is no eth_config entry for them
+var preTimestampForks = []string{"genesis", "altair", "bellatrix"}
+
+type ForkResult struct {
+	Fork               string          `json:"fork"`
+	Epoch              uint64          `json:"epoch"`
+	Version            string          `json:"version"`
+	ActivationTime     uint64          `json:"activationTime"`
+	Passed             bool            `json:"passed"`
+	Completed          bool            `json:"completed"`
+	BlockCount         uint64          `json:"blockCount"`
+	WrongVersionBlocks []uint64        `json:"wrongVersionBlocks,omitempty"`
+	FinalizedEpoch     uint64          `json:"finalizedEpoch"`
+	Clients            []*ClientResult `json:"clients"`
+	Errors             []string        `json:"errors,omitempty"`
+}
+
+type ClientResult struct {
+	Client             string   `json:"client"`
+	ProposalDuties     uint64   `json:"proposalDuties"`
+	ProducedBlocks     uint64   `json:"producedBlocks"`
+	AcceptedBlocks     uint64   `json:"acceptedBlocks"`
+	AnnouncedFork      string   `json:"announcedFork"`
+	AnnouncedELFork    string   `json:"announcedElFork"`
+	ForkState          string   `json:"forkState"`
+	ForkDigest         string   `json:"forkDigest"`
+	ExecutionConfig    string   `json:"executionConfig"`
+	Errors             []string `json:"errors,omitempty"`
+	rawSpecs           map[string]interface{}
+	executionForkCheck bool
+}
+
+type forkTracker struct {
+	task      *Task
+	fork      *consensus.ForkVersion
+	forkSlot  uint64
+	endEpoch  uint64
+	result    *ForkResult
+	blocks    []*consensus.Block
+	completed bool
+}
+
+func (t *Task) newForkTracker(fork *consensus.ForkVersion) *forkTracker {
+	blockCache := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetBlockCache()
+	specs := blockCache.GetSpecs()
+	genesis := blockCache.GetGenesis()
+
+	tracker := &forkTracker{
+		task:     t,
+		fork:     fork,
+		forkSlot: fork.Epoch * specs.SlotsPerEpoch,
+		endEpoch: fork.Epoch + t.config.FinalityEpochs,
+		result: &ForkResult{
+			Fork:    fork.Name,
+			Epoch:   fork.Epoch,
+			Version: fmt.Sprintf("%#x", fork.CurrentVersion),
+			Clients: make([]*ClientResult, len(t.poolClients)),
+		},
+	}
+
+	if genesis != nil {
+		tracker.result.ActivationTime = uint64(genesis.GenesisTime.Unix()) + tracker.forkSlot*uint64(specs.SecondsPerSlot.Seconds())
+	}
+
+	executionForkCheck := t.config.CheckExecutionConfig && !slices.Contains(preTimestampForks, fork.Name)
+
+	for idx, client := range t.poolClients {
+		clientResult := &ClientResult{
+			Client:             client.Config.Name,
+			AnnouncedFork:      checkSkipped,
+			AnnouncedELFork:    checkSkipped,
+			ForkState:          checkPending,
+			ForkDigest:         checkSkipped,
+			ExecutionConfig:    checkSkipped,
+			executionForkCheck: executionForkCheck && client.ExecutionClient != nil,
+		}
+
+		if t.config.CheckForkDigest {
+			clientResult.ForkDigest = checkPending
+		}
+
+		if clientResult.executionForkCheck {
+			clientResult.ExecutionConfig = checkPending
+		}
+
+		tracker.result.Clients[idx] = clientResult
+	}
+
+	return tracker
+}
+
+// runPreForkChecks verifies that the clients announce the upcoming fork before it activates.
+func (tracker *forkTracker) runPreForkChecks(ctx context.Context, currentEpoch uint64) {
+	t := tracker.task
+	nextFork := tracker.getNextFork(currentEpoch)
+
+	tracker.forEachClient(ctx, func(ctx context.Context, client *clients.PoolClient, clientResult *ClientResult) {
+		if t.config.CheckForkDigest && nextFork == tracker.fork {
+			forkID, err := t.getENRForkID(ctx, client)
+
+			switch {
+			case err != nil:
+				clientResult.AnnouncedFork = fmt.Sprintf("failed fetching enr: %v", err)
+			case forkID.NextForkVersion != tracker.fork.CurrentVersion || forkID.NextForkEpoch != tracker.fork.Epoch:
+				clientResult.AnnouncedFork = fmt.Sprintf("enr announces next fork %#x at epoch %v, expected %#x at epoch %v", forkID.NextForkVersion, forkID.NextForkEpoch, tracker.fork.CurrentVersion, tracker.fork.Epoch)
+			default:
+				clientResult.AnnouncedFork = checkPass
+			}
+		}
+
+		if clientResult.executionForkCheck {
+			reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+			defer cancel()
+
+			ethConfig, err := client.ExecutionClient.GetRPCClient().GetEthConfig(reqCtx)
+
+			switch {
+			case err != nil && isMethodNotFound(err):
+				clientResult.AnnouncedELFork = checkSkipped
+			case err != nil:
+				clientResult.AnnouncedELFork = fmt.Sprintf("failed fetching eth_config: %v", err)
+			case ethConfig.Current.ActivationTime >= tracker.result.ActivationTime:
+				clientResult.AnnouncedELFork = fmt.Sprintf("current fork already activated at %v, expected fork at %v", ethConfig.Current.ActivationTime, tracker.result.ActivationTime)
+			case nextFork == tracker.fork && (ethConfig.Next == nil || ethConfig.Next.ActivationTime != tracker.result.ActivationTime):
+				nextActivation := "none"
+				if ethConfig.Next != nil {
+					nextActivation = fmt.Sprintf("%v", ethConfig.Next.ActivationTime)
+				}
+
+				clientResult.AnnouncedELFork = fmt.Sprintf("next fork activation time %v, expected %v", nextActivation, tracker.result.ActivationTime)
+			default:
+				clientResult.AnnouncedELFork = checkPass
+			}
+		}
+	})
+
+	for _, clientResult := range tracker.result.Clients {
+		for _, status := range []string{clientResult.AnnouncedFork, clientResult.AnnouncedELFork} {
+			if status != checkPass && status != checkSkipped {
+				t.logger.Warnf("%v does not announce %v fork: %v", clientResult.Client, tracker.fork.Name, status)
+			}
+		}
+	}
+}
+
+func (tracker *forkTracker) getNextFork(currentEpoch uint64) *consensus.ForkVersion {
+	for _, fork := range tracker.task.forkSchedule {
+		if fork.Epoch > currentEpoch && fork.Epoch != consensus.FarFutureEpoch {
+			return fork
+		}
+	}
+
+	return nil
+}
+
+func (tracker *forkTracker) processBlock(ctx context.Context, block *consensus.Block) {
+	t := tracker.task
+	specs := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetBlockCache().GetSpecs()
+	blockEpoch := uint64(block.Slot) / specs.SlotsPerEpoch
+
+	if tracker.completed || uint64(block.Slot) < tracker.forkSlot || blockEpoch >= tracker.endEpoch {
+		return
+	}
+
+	tracker.blocks = append(tracker.blocks, block)
+	tracker.result.BlockCount++
+
+	blockData := block.AwaitBlock(ctx, 2*time.Second)
+	if blockData == nil {
+		t.logger.Warnf("could not load block %v [0x%x] for version check", block.Slot, block.Root)
+		return
+	}
+
+	expectedFork := getExpectedFork(t.forkSchedule, blockEpoch)
+	if expectedFork != nil && !strings.EqualFold(blockData.Version.String(), expectedFork.Name) {
+		t.logger.Errorf("block %v [0x%x] has version %v, expected %v", block.Slot, block.Root, blockData.Version.String(), expectedFork.Name)
+		tracker.result.WrongVersionBlocks = append(tracker.result.WrongVersionBlocks, uint64(block.Slot))
+	}
+
+	proposerIndex, err := blockData.ProposerIndex()
+	if err != nil {
+		t.logger.Warnf("could not get proposer index for block %v [0x%x]: %v", block.Slot, block.Root, err)
+		return
+	}
+
+	validatorName := t.ctx.Scheduler.GetServices().ValidatorNames().GetValidatorName(uint64(proposerIndex))
+	if clientIdx := t.getClientByValidatorName(validatorName); clientIdx >= 0 {
+		tracker.result.Clients[clientIdx].ProducedBlocks++
+	}
+}
+
+// processSlot re-runs the post-fork checks of all clients that did not pass them yet.
+func (tracker *forkTracker) processSlot(ctx context.Context, slot uint64) {
+	if tracker.completed || slot <= tracker.forkSlot {
+		return
+	}
+
+	t := tracker.task
+	blockCache := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetBlockCache()
+	currentEpoch := slot / blockCache.GetSpecs().SlotsPerEpoch
+	activeFork := getExpectedFork(t.forkSchedule, currentEpoch)
+
+	tracker.forEachClient(ctx, func(ctx context.Context, client *clients.PoolClient, clientResult *ClientResult) {
+		if clientResult.ForkState != checkPass {
+			clientResult.ForkState = tracker.checkForkState(ctx, client)
+		}
+
+		if clientResult.ForkDigest != checkPass && clientResult.ForkDigest != checkSkipped {
+			if activeFork != tracker.fork {
+				// a later fork is active already, the digest is checked by its own tracker
+				clientResult.ForkDigest = checkSkipped
+			} else {
+				clientResult.ForkDigest = tracker.checkForkDigest(ctx, client, clientResult, currentEpoch)
+			}
+		}
+
+		if clientResult.ExecutionConfig != checkPass && clientResult.ExecutionConfig != checkSkipped {
+			clientResult.ExecutionConfig = tracker.checkExecutionConfig(ctx, client)
+		}
+	})
+}
+
+func (tracker *forkTracker) checkForkState(ctx context.Context, client *clients.PoolClient) string {
+	reqCtx, cancel := context.WithTimeout(ctx, tracker.task.config.RequestTimeout.Duration)
+	defer cancel()
+
+	forkState, err := client.ConsensusClient.GetRPCClient().GetForkState(reqCtx, "head")
+
+	switch {
+	case err != nil:
+		return fmt.Sprintf("failed fetching head fork: %v", err)
+	case uint64(forkState.Epoch) < tracker.fork.Epoch:
+		return fmt.Sprintf("head state still on fork %#x (epoch %v)", forkState.CurrentVersion, forkState.Epoch)
+	case uint64(forkState.Epoch) > tracker.fork.Epoch:
+		// head already transitioned to a later fork
+		return checkPass
+	case forkState.CurrentVersion != tracker.fork.CurrentVersion || forkState.PreviousVersion != tracker.fork.PreviousVersion:
+		return fmt.Sprintf("head fork %#x (previous: %#x), expected %#x (previous: %#x)", forkState.CurrentVersion, forkState.PreviousVersion, tracker.fork.CurrentVersion, tracker.fork.PreviousVersion)
+	}
+
+	return checkPass
+}
+
+func (tracker *forkTracker) checkForkDigest(ctx context.Context, client *clients.PoolClient, clientResult *ClientResult, currentEpoch uint64) string {
+	t := tracker.task
+	genesis := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetBlockCache().GetGenesis()
+
+	if clientResult.rawSpecs == nil {
+		reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+		defer cancel()
+
+		rawSpecs, err := client.ConsensusClient.GetRPCClient().GetRawConfigSpecs(reqCtx)
+		if err != nil {
+			return fmt.Sprintf("failed fetching specs: %v", err)
+		}
+
+		clientResult.rawSpecs = rawSpecs
+	}
+
+	expectedDigest, err := computeForkDigest(tracker.fork.CurrentVersion, genesis.GenesisValidatorsRoot, currentEpoch, clientResult.rawSpecs)
+	if err != nil {
+		return fmt.Sprintf("failed computing fork digest: %v", err)
+	}
+
+	forkID, err := t.getENRForkID(ctx, client)
+	if err != nil {
+		return fmt.Sprintf("failed fetching enr: %v", err)
+	}
+
+	if forkID.ForkDigest != expectedDigest {
+		return fmt.Sprintf("enr fork digest %#x, expected %#x", forkID.ForkDigest, expectedDigest)
+	}
+
+	return checkPass
+}
+
+func (tracker *forkTracker) checkExecutionConfig(ctx context.Context, client *clients.PoolClient) string {
+	reqCtx, cancel := context.WithTimeout(ctx, tracker.task.config.RequestTimeout.Duration)
+	defer cancel()
+
+	ethConfig, err := client.ExecutionClient.GetRPCClient().GetEthConfig(reqCtx)
+
+	switch {
+	case err != nil && isMethodNotFound(err):
+		return checkSkipped
+	case err != nil:
+		return fmt.Sprintf("failed fetching eth_config: %v", err)
+	case ethConfig.Current.ActivationTime < tracker.result.ActivationTime:
+		return fmt.Sprintf("current fork activated at %v, expected fork at %v", ethConfig.Current.ActivationTime, tracker.result.ActivationTime)
+	}
+
+	// a later activation time means the execution client already transitioned to a later fork
+	return checkPass
+}
+
+func (tracker *forkTracker) processEpoch(ctx context.Context, epoch uint64) {
+	if tracker.completed || epoch <= tracker.fork.Epoch {
+		return
+	}
+
+	t := tracker.task
+	finalizedEpoch, _ := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetBlockCache().GetFinalizedCheckpoint()
+	tracker.result.FinalizedEpoch = uint64(finalizedEpoch)
+
+	if epoch-uint64(finalizedEpoch) > t.config.MaxUnfinalizedEpochs {
+		err := fmt.Sprintf("finality stalled at epoch %v (finalized: %v)", epoch, finalizedEpoch)
+		t.logger.Errorf("%v fork: %v", tracker.fork.Name, err)
+		tracker.result.Errors = append(tracker.result.Errors, err)
+	}
+
+	if epoch >= tracker.endEpoch {
+		tracker.evaluate(ctx)
+	}
+}
+
+// evaluate checks block production & acceptance over the observation window and collects the final client results.
+func (tracker *forkTracker) evaluate(ctx context.Context) {
+	t := tracker.task
+	tracker.completed = true
+	tracker.result.Completed = true
+
+	if t.config.CheckBlockProduction {
+		tracker.loadProposalDuties(ctx)
+	}
+
+	if len(tracker.result.WrongVersionBlocks) > 0 {
+		tracker.result.Errors = append(tracker.result.Errors, fmt.Sprintf("%v blocks with unexpected version", len(tracker.result.WrongVersionBlocks)))
+	}
+
+	for idx, client := range t.poolClients {
+		clientResult := tracker.result.Clients[idx]
+
+		for _, block := range tracker.blocks {
+			for _, seenBy := range block.GetSeenBy() {
+				if seenBy == client.ConsensusClient {
+					clientResult.AcceptedBlocks++
+					break
+				}
+			}
+		}
+
+		if clientResult.AcceptedBlocks == 0 {
+			clientResult.Errors = append(clientResult.Errors, fmt.Sprintf("no %v block accepted", tracker.fork.Name))
+		}
+
+		if t.config.CheckBlockProduction && clientResult.ProposalDuties > 0 && clientResult.ProducedBlocks == 0 {
+			clientResult.Errors = append(clientResult.Errors, fmt.Sprintf("no %v block produced (%v proposal duties)", tracker.fork.Name, clientResult.ProposalDuties))
+		}
+
+		checks := map[string]string{
+			"announced fork":    clientResult.AnnouncedFork,
+			"announced el fork": clientResult.AnnouncedELFork,
+			"fork state":        clientResult.ForkState,
+			"fork digest":       clientResult.ForkDigest,
+			"execution config":  clientResult.ExecutionConfig,
+		}
+
+		for _, checkName := range []string{"announced fork", "announced el fork", "fork state", "fork digest", "execution config"} {
+			if status := checks[checkName]; status != checkPass && status != checkSkipped {
+				clientResult.Errors = append(clientResult.Errors, fmt.Sprintf("%v: %v", checkName, status))
+			}
+		}
+
+		for _, err := range clientResult.Errors {
+			t.logger.Errorf("%v fork check failed for %v: %v", tracker.fork.Name, clientResult.Client, err)
+		}
+
+		if len(clientResult.Errors) > 0 {
+			tracker.result.Errors = append(tracker.result.Errors, fmt.Sprintf("client %v failed %v checks", clientResult.Client, len(clientResult.Errors)))
+		}
+	}
+
+	tracker.result.Passed = len(tracker.result.Errors) == 0
+
+	if tracker.result.Passed {
+		t.logger.Infof("%v fork transition check passed (%v blocks, finalized epoch: %v)", tracker.fork.Name, tracker.result.BlockCount, tracker.result.FinalizedEpoch)
+	}
+}
+
+func (tracker *forkTracker) loadProposalDuties(ctx context.Context) {
+	t := tracker.task
+	consensusPool := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool()
+
+	readyClient := consensusPool.GetReadyEndpoint(consensus.AnyClient)
+	if readyClient == nil {
+		t.logger.Warnf("no ready client for loading proposer duties")
+		return
+	}
+
+	for epoch := tracker.fork.Epoch; epoch < tracker.endEpoch; epoch++ {
+		reqCtx, cancel := context.WithTimeout(ctx, t.config.RequestTimeout.Duration)
+		duties, err := readyClient.GetRPCClient().GetProposerDuties(reqCtx, epoch)
+
+		cancel()
+
+		if err != nil {
+			t.logger.Warnf("failed loading proposer duties for epoch %v: %v", epoch, err)
+			continue
+		}
+
+		for _, duty := range duties {
+			validatorName := t.ctx.Scheduler.GetServices().ValidatorNames().GetValidatorName(uint64(duty.ValidatorIndex))
+			if clientIdx := t.getClientByValidatorName(validatorName); clientIdx >= 0 {
+				tracker.result.Clients[clientIdx].ProposalDuties++
+			}
+		}
+	}
+}
+
+func (tracker *forkTracker) forEachClient(ctx context.Context, check func(ctx context.Context, client *clients.PoolClient, clientResult *ClientResult)) {
+	wg := sync.WaitGroup{}
+
+	for idx, client := range tracker.task.poolClients {
+		wg.Add(1)
+
+		go func(client *clients.PoolClient, clientResult *ClientResult) {
+			defer wg.Done()
+
+			check(ctx, client, clientResult)
+		}(client, tracker.result.Clients[idx])
+	}
+
+	wg.Wait()
+}
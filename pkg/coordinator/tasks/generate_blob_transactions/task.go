@@ -312,7 +312,7 @@ func (t *Task) generateTransaction(ctx context.Context, transactionIdx uint64, c
 	clientPool := t.ctx.Scheduler.GetServices().ClientPool()
 
 	if t.config.ClientPattern == "" && t.config.ExcludeClientPattern == "" {
-		clients = clientPool.GetExecutionPool().GetReadyEndpoints(true)
+		clients = clientPool.GetExecutionPool().GetReadyEndpointsForTest(t.ctx.Scheduler.GetTestRunID())
 	} else {
 		poolClients := clientPool.GetClientsByNamePatterns(t.config.ClientPattern, t.config.ExcludeClientPattern)
 		if len(poolClients) == 0 {
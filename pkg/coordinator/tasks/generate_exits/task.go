@@ -1,14 +1,12 @@
 package generateexits
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
 
-	v1 "github.com/attestantio/go-eth2-client/api/v1"
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	"github.com/erigontech/assertoor/pkg/coordinator/clients/consensus"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
@@ -175,15 +173,8 @@ func (t *Task) generateVoluntaryExit(ctx context.Context, accountIdx uint64, for
 	}
 
 	// select validator
-	var validator *v1.Validator
-
 	validatorPubkey := validatorPrivkey.PublicKey().Marshal()
-	for _, val := range t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetValidatorSet() {
-		if bytes.Equal(val.Validator.PublicKey[:], validatorPubkey) {
-			validator = val
-			break
-		}
-	}
+	validator := t.ctx.Scheduler.GetServices().ClientPool().GetConsensusPool().GetValidatorRegistry().GetValidatorByPubkey(phase0.BLSPubKey(validatorPubkey))
 
 	// check validator status
 	if validator == nil {
@@ -199,7 +190,7 @@ func (t *Task) generateVoluntaryExit(ctx context.Context, accountIdx uint64, for
 
 	clientPool := t.ctx.Scheduler.GetServices().ClientPool()
 	if t.config.ClientPattern == "" && t.config.ExcludeClientPattern == "" {
-		client = clientPool.GetConsensusPool().GetReadyEndpoint(consensus.AnyClient)
+		client = clientPool.GetConsensusPool().GetReadyEndpointForTest(consensus.AnyClient, t.ctx.Scheduler.GetTestRunID())
 	} else {
 		clients := clientPool.GetClientsByNamePatterns(t.config.ClientPattern, t.config.ExcludeClientPattern)
 		if len(clients) == 0 {
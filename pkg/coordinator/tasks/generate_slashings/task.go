@@ -215,7 +215,7 @@ func (t *Task) generateSlashing(ctx context.Context, accountIdx uint64, forkStat
 	var client *consensus.Client
 
 	if t.config.ClientPattern == "" && t.config.ExcludeClientPattern == "" {
-		client = clientPool.GetConsensusPool().GetReadyEndpoint(consensus.AnyClient)
+		client = clientPool.GetConsensusPool().GetReadyEndpointForTest(consensus.AnyClient, t.ctx.Scheduler.GetTestRunID())
 	} else {
 		clients := clientPool.GetClientsByNamePatterns(t.config.ClientPattern, t.config.ExcludeClientPattern)
 		if len(clients) == 0 {
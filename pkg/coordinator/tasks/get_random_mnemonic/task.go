@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/sirupsen/logrus"
 	"github.com/tyler-smith/go-bip39"
@@ -81,6 +82,9 @@ func (t *Task) Execute(_ context.Context) error {
 		return fmt.Errorf("could not create mnemonic: %v", err)
 	}
 
+	// generated mnemonics are key material, mask them in logs and persisted state
+	secrets.AddSecret(mnemonic)
+
 	t.logger.Infof("Generated random mnemonic: %v", mnemonic)
 
 	if t.config.MnemonicResultVar != "" {
@@ -3,18 +3,29 @@ package tasks
 import (
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 
+	acquirelock "github.com/erigontech/assertoor/pkg/coordinator/tasks/acquire_lock"
+	checkbeaconapiconformance "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_beacon_api_conformance"
+	checkchainspecconsistency "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_chain_spec_consistency"
 	checkclientsarehealthy "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_clients_are_healthy"
 	checkconsensusattestationstats "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_consensus_attestation_stats"
 	checkconsensusblockproposals "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_consensus_block_proposals"
 	checkconsensusfinality "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_consensus_finality"
 	checkconsensusforks "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_consensus_forks"
+	checkconsensuspendingqueues "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_consensus_pending_queues"
 	checkconsensusproposerduty "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_consensus_proposer_duty"
 	checkconsensusreorgs "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_consensus_reorgs"
 	checkconsensusslotrange "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_consensus_slot_range"
+	checkconsensusstateconsistency "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_consensus_state_consistency"
 	checkconsensussyncstatus "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_consensus_sync_status"
 	checkconsensusvalidatorstatus "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_consensus_validator_status"
+	checkconsensuswithdrawalsweep "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_consensus_withdrawal_sweep"
 	checkethcall "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_eth_call"
+	checkexecutionblockintegrity "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_execution_block_integrity"
+	checkexecutionfeemarket "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_execution_fee_market"
+	checkexecutiongaslimit "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_execution_gas_limit"
+	checkexecutionrpcconformance "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_execution_rpc_conformance"
 	checkexecutionsyncstatus "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_execution_sync_status"
+	checkforktransition "github.com/erigontech/assertoor/pkg/coordinator/tasks/check_fork_transition"
 	generateblobtransactions "github.com/erigontech/assertoor/pkg/coordinator/tasks/generate_blob_transactions"
 	generateblschanges "github.com/erigontech/assertoor/pkg/coordinator/tasks/generate_bls_changes"
 	generatechildwallet "github.com/erigontech/assertoor/pkg/coordinator/tasks/generate_child_wallet"
@@ -42,22 +53,34 @@ import (
 	txpoolclean "github.com/erigontech/assertoor/pkg/coordinator/tasks/tx_pool_clean"
 	txpoollatencyanalysis "github.com/erigontech/assertoor/pkg/coordinator/tasks/tx_pool_latency_analysis"
 	txpoolthroughputanalysis "github.com/erigontech/assertoor/pkg/coordinator/tasks/tx_pool_throughput_analysis"
+	waitforapproval "github.com/erigontech/assertoor/pkg/coordinator/tasks/wait_for_approval"
 )
 
 var AvailableTaskDescriptors = []*types.TaskDescriptor{
+	acquirelock.TaskDescriptor,
+	checkbeaconapiconformance.TaskDescriptor,
+	checkchainspecconsistency.TaskDescriptor,
 	checkclientsarehealthy.TaskDescriptor,
 	checkconsensusattestationstats.TaskDescriptor,
 	checkconsensusblockproposals.TaskDescriptor,
 	checkconsensusfinality.TaskDescriptor,
 	checkconsensusforks.TaskDescriptor,
+	checkconsensuspendingqueues.TaskDescriptor,
 	checkconsensusproposerduty.TaskDescriptor,
 	checkconsensusreorgs.TaskDescriptor,
 	checkconsensusslotrange.TaskDescriptor,
+	checkconsensusstateconsistency.TaskDescriptor,
 	checkconsensussyncstatus.TaskDescriptor,
 	checkconsensusvalidatorstatus.TaskDescriptor,
+	checkconsensuswithdrawalsweep.TaskDescriptor,
 	checkexecutionblock.TaskDescriptor,
+	checkexecutionblockintegrity.TaskDescriptor,
 	checkethcall.TaskDescriptor,
+	checkexecutionfeemarket.TaskDescriptor,
+	checkexecutiongaslimit.TaskDescriptor,
+	checkexecutionrpcconformance.TaskDescriptor,
 	checkexecutionsyncstatus.TaskDescriptor,
+	checkforktransition.TaskDescriptor,
 	generateblobtransactions.TaskDescriptor,
 	generateblschanges.TaskDescriptor,
 	generatechildwallet.TaskDescriptor,
@@ -84,6 +107,7 @@ var AvailableTaskDescriptors = []*types.TaskDescriptor{
 	txpoolthroughputanalysis.TaskDescriptor,
 	txpoolclean.TaskDescriptor,
 	sleep.TaskDescriptor,
+	waitforapproval.TaskDescriptor,
 }
 
 func GetTaskDescriptor(name string) *types.TaskDescriptor {
@@ -0,0 +1,32 @@
+package waitforapproval
+
+import (
+	"fmt"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+)
+
+type Config struct {
+	Message         string          `yaml:"message" json:"message"`
+	RequiredInputs  []string        `yaml:"requiredInputs" json:"requiredInputs"`
+	ApprovalTimeout helper.Duration `yaml:"approvalTimeout" json:"approvalTimeout"`
+	TimeoutResult   string          `yaml:"timeoutResult" json:"timeoutResult"`
+	FailOnReject    bool            `yaml:"failOnReject" json:"failOnReject"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		TimeoutResult: "failure",
+		FailOnReject:  true,
+	}
+}
+
+func (c *Config) Validate() error {
+	switch c.TimeoutResult {
+	case "success", "failure", "none":
+	default:
+		return fmt.Errorf("invalid timeoutResult: %v (must be success, failure or none)", c.TimeoutResult)
+	}
+
+	return nil
+}
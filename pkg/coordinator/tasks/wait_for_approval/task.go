@@ -0,0 +1,139 @@
+package waitforapproval
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	TaskName       = "wait_for_approval"
+	TaskDescriptor = &types.TaskDescriptor{
+		Name:        TaskName,
+		Description: "Waits for a manual approval or rejection via the API or the test run page.",
+		Config:      DefaultConfig(),
+		NewTask:     NewTask,
+	}
+)
+
+type Task struct {
+	ctx     *types.TaskContext
+	options *types.TaskOptions
+	config  Config
+	logger  logrus.FieldLogger
+}
+
+func NewTask(ctx *types.TaskContext, options *types.TaskOptions) (types.Task, error) {
+	return &Task{
+		ctx:     ctx,
+		options: options,
+		logger:  ctx.Logger.GetLogger(),
+	}, nil
+}
+
+func (t *Task) Config() interface{} {
+	return t.config
+}
+
+func (t *Task) Timeout() time.Duration {
+	return t.options.Timeout.Duration
+}
+
+func (t *Task) LoadConfig() error {
+	config := DefaultConfig()
+
+	// parse static config
+	if t.options.Config != nil {
+		if err := t.options.Config.Unmarshal(&config); err != nil {
+			return fmt.Errorf("error parsing task config for %v: %w", TaskName, err)
+		}
+	}
+
+	// load dynamic vars
+	err := t.ctx.Vars.ConsumeVars(&config, t.options.ConfigVars)
+	if err != nil {
+		return err
+	}
+
+	// validate config
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	t.config = config
+
+	return nil
+}
+
+func (t *Task) Execute(ctx context.Context) error {
+	approvalChan := t.ctx.Scheduler.RequestApproval(&types.TaskApprovalRequest{
+		TaskIndex:      t.ctx.Index,
+		Message:        t.config.Message,
+		RequiredInputs: t.config.RequiredInputs,
+		RequestTime:    time.Now(),
+	})
+	defer t.ctx.Scheduler.CancelApproval(t.ctx.Index)
+
+	t.logger.Infof("waiting for approval: %v", t.config.Message)
+
+	var timeoutChan <-chan time.Time
+
+	if t.config.ApprovalTimeout.Duration > 0 {
+		timer := time.NewTimer(t.config.ApprovalTimeout.Duration)
+		defer timer.Stop()
+
+		timeoutChan = timer.C
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timeoutChan:
+		t.logger.Warnf("no approval received within %v", t.config.ApprovalTimeout.Duration)
+		t.ctx.Outputs.SetVar("timeout", true)
+
+		switch t.config.TimeoutResult {
+		case "success":
+			t.ctx.SetResult(types.TaskResultSuccess)
+		case "none":
+			t.ctx.SetResult(types.TaskResultNone)
+		default:
+			t.ctx.SetResult(types.TaskResultFailure)
+			return fmt.Errorf("approval timed out")
+		}
+
+		return nil
+	case approval := <-approvalChan:
+		t.ctx.Outputs.SetVar("approved", approval.Approved)
+		t.ctx.Outputs.SetVar("approver", approval.Approver)
+		t.ctx.Outputs.SetVar("comment", approval.Comment)
+
+		inputs := approval.Inputs
+		if inputs == nil {
+			inputs = map[string]any{}
+		}
+
+		t.ctx.Outputs.SetVar("inputs", inputs)
+
+		if !approval.Approved {
+			t.logger.Warnf("rejected by '%v': %v", approval.Approver, approval.Comment)
+
+			if t.config.FailOnReject {
+				t.ctx.SetResult(types.TaskResultFailure)
+				return fmt.Errorf("approval rejected")
+			}
+
+			t.ctx.SetResult(types.TaskResultNone)
+
+			return nil
+		}
+
+		t.logger.Infof("approved by '%v': %v", approval.Approver, approval.Comment)
+		t.ctx.SetResult(types.TaskResultSuccess)
+	}
+
+	return nil
+}
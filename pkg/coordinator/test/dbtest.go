@@ -74,6 +74,25 @@ func (dbt *dbTest) Status() types.TestStatus {
 	return types.TestStatus(dbt.testRun.Status)
 }
 
+func (dbt *dbTest) ParentRunID() uint64 {
+	return dbt.testRun.ParentRunID
+}
+
+func (dbt *dbTest) MatrixValues() map[string]interface{} {
+	if dbt.testRun.MatrixValues == "" {
+		return nil
+	}
+
+	matrixValues := map[string]interface{}{}
+
+	err := yaml.Unmarshal([]byte(dbt.testRun.MatrixValues), &matrixValues)
+	if err != nil {
+		return nil
+	}
+
+	return matrixValues
+}
+
 func (dbt *dbTest) GetTaskScheduler() types.TaskScheduler {
 	return dbt
 }
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
@@ -133,7 +134,7 @@ func LoadExternalTestConfig(ctx context.Context, globalVars types.Variables, ext
 	testConfig := &types.TestConfig{}
 	testVars := globalVars.NewScope()
 
-	err := decoder.Decode(testConfig)
+	err := secrets.DecodeYaml(decoder, testConfig)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error decoding external test config %v: %v", extTestCfg.File, err)
 	}
@@ -166,6 +167,22 @@ func LoadExternalTestConfig(ctx context.Context, globalVars types.Variables, ext
 		testConfig.Schedule = extTestCfg.Schedule
 	}
 
+	if len(extTestCfg.Matrix) > 0 {
+		testConfig.Matrix = extTestCfg.Matrix
+	}
+
+	if len(extTestCfg.Locks) > 0 {
+		testConfig.Locks = extTestCfg.Locks
+	}
+
+	if extTestCfg.Priority != nil {
+		testConfig.Priority = *extTestCfg.Priority
+	}
+
+	if extTestCfg.ConcurrencyGroup != "" {
+		testConfig.ConcurrencyGroup = extTestCfg.ConcurrencyGroup
+	}
+
 	for k, v := range extTestCfg.Config {
 		testConfig.Config[k] = v
 		testVars.SetVar(k, v)
@@ -0,0 +1,54 @@
+package test
+
+import (
+	"sort"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+)
+
+// ExpandTestMatrix returns one set of config overrides for each combination of matrix values.
+// Matrix keys that are already set via configOverrides are not expanded.
+// Returns nil if there are no matrix values to expand.
+func ExpandTestMatrix(matrix types.TestMatrix, configOverrides map[string]any) []map[string]any {
+	matrixKeys := make([]string, 0, len(matrix))
+
+	for key, values := range matrix {
+		if _, isOverridden := configOverrides[key]; isOverridden {
+			continue
+		}
+
+		if len(values) == 0 {
+			continue
+		}
+
+		matrixKeys = append(matrixKeys, key)
+	}
+
+	if len(matrixKeys) == 0 {
+		return nil
+	}
+
+	sort.Strings(matrixKeys)
+
+	combinations := []map[string]any{{}}
+
+	for _, key := range matrixKeys {
+		newCombinations := make([]map[string]any, 0, len(combinations)*len(matrix[key]))
+
+		for _, combination := range combinations {
+			for _, value := range matrix[key] {
+				newCombination := make(map[string]any, len(combination)+1)
+				for k, v := range combination {
+					newCombination[k] = v
+				}
+
+				newCombination[key] = value
+				newCombinations = append(newCombinations, newCombination)
+			}
+		}
+
+		combinations = newCombinations
+	}
+
+	return combinations
+}
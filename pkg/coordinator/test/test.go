@@ -3,10 +3,12 @@ package test
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/erigontech/assertoor/pkg/coordinator/db"
 	"github.com/erigontech/assertoor/pkg/coordinator/scheduler"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/erigontech/assertoor/pkg/coordinator/vars"
 	"github.com/jmoiron/sqlx"
@@ -16,12 +18,16 @@ import (
 
 type Test struct {
 	runID         uint64
+	parentRunID   uint64
+	matrixValues  map[string]any
 	services      types.TaskServices
 	taskScheduler *scheduler.TaskScheduler
 	logger        logrus.FieldLogger
 	descriptor    types.TestDescriptor
 	config        *types.TestConfig
 	variables     types.Variables
+	locks         []string
+	priority      int
 
 	dbTestRun *db.TestRun
 
@@ -31,39 +37,71 @@ type Test struct {
 	timeout   time.Duration
 }
 
-func CreateTest(runID uint64, descriptor types.TestDescriptor, logger logrus.FieldLogger, services types.TaskServices, configOverrides map[string]any) (types.TestRunner, error) {
+func CreateTest(runID, parentRunID uint64, descriptor types.TestDescriptor, logger logrus.FieldLogger, services types.TaskServices, configOverrides, matrixValues map[string]any) (types.TestRunner, error) {
 	test := &Test{
-		runID:      runID,
-		services:   services,
-		logger:     logger.WithField("RunID", runID).WithField("TestID", descriptor.ID()),
-		descriptor: descriptor,
-		config:     descriptor.Config(),
-		status:     types.TestStatusPending,
+		runID:        runID,
+		parentRunID:  parentRunID,
+		matrixValues: matrixValues,
+		services:     services,
+		logger:       logger.WithField("RunID", runID).WithField("TestID", descriptor.ID()),
+		descriptor:   descriptor,
+		config:       descriptor.Config(),
+		status:       types.TestStatusPending,
+		priority:     descriptor.Config().Priority,
 	}
 	if test.config.Timeout.Duration > 0 {
 		test.timeout = test.config.Timeout.Duration
 	}
 
+	if parentRunID != 0 {
+		test.logger = test.logger.WithField("ParentRunID", parentRunID)
+	}
+
 	// set test variables
 	test.variables = vars.NewVariables(descriptor.Vars())
 	for cfgKey, cfgValue := range configOverrides {
 		test.variables.SetVar(cfgKey, cfgValue)
 	}
 
+	for cfgKey, cfgValue := range matrixValues {
+		test.variables.SetVar(cfgKey, cfgValue)
+	}
+
+	// resolve lock names, which may reference test variables
+	for _, lockName := range test.config.Locks {
+		lockName = test.variables.ResolvePlaceholders(lockName)
+		if lockName != "" && !slices.Contains(test.locks, lockName) {
+			test.locks = append(test.locks, lockName)
+		}
+	}
+
 	// add test run to database
-	configYaml, err := yaml.Marshal(test.variables.GetVarsMap(nil, false))
+	configYaml, err := yaml.Marshal(secrets.RedactData(test.variables.GetVarsMap(nil, false)))
 	if err != nil {
 		return nil, err
 	}
 
+	matrixYaml := ""
+
+	if len(matrixValues) > 0 {
+		matrixYamlBytes, err := yaml.Marshal(matrixValues)
+		if err != nil {
+			return nil, err
+		}
+
+		matrixYaml = string(matrixYamlBytes)
+	}
+
 	test.dbTestRun = &db.TestRun{
-		RunID:   runID,
-		TestID:  descriptor.ID(),
-		Name:    test.config.Name,
-		Source:  descriptor.Source(),
-		Config:  string(configYaml),
-		Timeout: int32(test.timeout.Seconds()),
-		Status:  string(test.status),
+		RunID:        runID,
+		TestID:       descriptor.ID(),
+		Name:         test.config.Name,
+		Source:       descriptor.Source(),
+		Config:       string(configYaml),
+		Timeout:      int32(test.timeout.Seconds()),
+		Status:       string(test.status),
+		ParentRunID:  parentRunID,
+		MatrixValues: matrixYaml,
 	}
 
 	if err := services.Database().RunTransaction(func(tx *sqlx.Tx) error {
@@ -159,6 +197,14 @@ func (t *Test) Status() types.TestStatus {
 	return t.status
 }
 
+func (t *Test) ParentRunID() uint64 {
+	return t.parentRunID
+}
+
+func (t *Test) MatrixValues() map[string]interface{} {
+	return t.matrixValues
+}
+
 func (t *Test) Logger() logrus.FieldLogger {
 	return t.logger
 }
@@ -200,11 +246,21 @@ func (t *Test) Run(ctx context.Context) error {
 	defer func() {
 		t.stopTime = time.Now()
 
+		t.services.ClientPool().ReleaseTestClients(t.runID)
+
 		if err := t.updateTestStatus(); err != nil {
 			t.logger.WithError(err).Error("failed updating test status")
 		}
 	}()
 
+	// resolve secret references, so rotated secrets are picked up by every new run
+	if err := t.resolveSecretReferences(ctx); err != nil {
+		t.logger.WithError(err).Error("failed resolving secret references")
+		t.status = types.TestStatusFailure
+
+		return err
+	}
+
 	// run test tasks
 	t.logger.WithField("timeout", t.timeout.String()).Info("starting test")
 
@@ -236,6 +292,26 @@ func (t *Test) Run(ctx context.Context) error {
 	return nil
 }
 
+func (t *Test) resolveSecretReferences(ctx context.Context) error {
+	resolver := t.services.SecretResolver()
+	if resolver == nil {
+		return nil
+	}
+
+	for name, value := range t.variables.GetVarsMap(nil, false) {
+		resolved, changed, err := resolver.ResolveData(ctx, value)
+		if err != nil {
+			return fmt.Errorf("variable %v: %w", name, err)
+		}
+
+		if changed {
+			t.variables.SetVar(name, resolved)
+		}
+	}
+
+	return nil
+}
+
 func (t *Test) AbortTest(skipCleanup bool) {
 	t.logger.Info("aborting test")
 	t.status = types.TestStatusAborted
@@ -252,3 +328,19 @@ func (t *Test) GetTaskScheduler() types.TaskScheduler {
 func (t *Test) GetTestVariables() types.Variables {
 	return t.variables
 }
+
+func (t *Test) Locks() []string {
+	return t.locks
+}
+
+func (t *Test) Priority() int {
+	return t.priority
+}
+
+func (t *Test) SetPriority(priority int) {
+	t.priority = priority
+}
+
+func (t *Test) ConcurrencyGroup() string {
+	return t.config.ConcurrencyGroup
+}
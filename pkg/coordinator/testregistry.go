@@ -10,6 +10,7 @@ import (
 
 	"github.com/erigontech/assertoor/pkg/coordinator/db"
 	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/test"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/erigontech/assertoor/pkg/coordinator/vars"
@@ -70,11 +71,13 @@ func (c *TestRegistry) LoadTests(ctx context.Context, local []*types.TestConfig,
 
 	for _, dbTestConfig := range dbTestConfigs {
 		externalTest := &types.ExternalTestConfig{
-			ID:         dbTestConfig.TestID,
-			File:       dbTestConfig.Source,
-			Name:       dbTestConfig.Name,
-			Config:     map[string]interface{}{},
-			ConfigVars: map[string]string{},
+			ID:               dbTestConfig.TestID,
+			File:             dbTestConfig.Source,
+			Name:             dbTestConfig.Name,
+			Config:           map[string]interface{}{},
+			ConfigVars:       map[string]string{},
+			Priority:         dbTestConfig.Priority,
+			ConcurrencyGroup: dbTestConfig.ConcurrencyGroup,
 			Schedule: &types.TestSchedule{
 				Startup: false,
 				Cron:    []string{},
@@ -85,11 +88,13 @@ func (c *TestRegistry) LoadTests(ctx context.Context, local []*types.TestConfig,
 			externalTest.Timeout = &helper.Duration{Duration: time.Duration(dbTestConfig.Timeout) * time.Second}
 		}
 
-		if err := yaml.Unmarshal([]byte(dbTestConfig.Config), &externalTest.Config); err != nil {
+		if err := secrets.UnmarshalYaml([]byte(dbTestConfig.Config), &externalTest.Config); err != nil {
 			c.coordinator.Logger().Errorf("error decoding test config %v from db: %v", dbTestConfig.TestID, err)
 			continue
 		}
 
+		c.restoreRedactedConfig(externalTest, external)
+
 		if err := yaml.Unmarshal([]byte(dbTestConfig.ConfigVars), &externalTest.ConfigVars); err != nil {
 			c.coordinator.Logger().Errorf("error decoding test configVars %v from db: %v", dbTestConfig.TestID, err)
 			continue
@@ -102,6 +107,20 @@ func (c *TestRegistry) LoadTests(ctx context.Context, local []*types.TestConfig,
 			}
 		}
 
+		if dbTestConfig.MatrixYaml != "" {
+			if err := yaml.Unmarshal([]byte(dbTestConfig.MatrixYaml), &externalTest.Matrix); err != nil {
+				c.coordinator.Logger().Errorf("error decoding test matrix %v from db: %v", dbTestConfig.TestID, err)
+				continue
+			}
+		}
+
+		if dbTestConfig.LocksYaml != "" {
+			if err := yaml.Unmarshal([]byte(dbTestConfig.LocksYaml), &externalTest.Locks); err != nil {
+				c.coordinator.Logger().Errorf("error decoding test locks %v from db: %v", dbTestConfig.TestID, err)
+				continue
+			}
+		}
+
 		externalTests = append(externalTests, externalTest)
 	}
 
@@ -119,6 +138,10 @@ func (c *TestRegistry) LoadTests(ctx context.Context, local []*types.TestConfig,
 			cfgExternalTest.Config = externalTest.Config
 			cfgExternalTest.ConfigVars = externalTest.ConfigVars
 			cfgExternalTest.Schedule = externalTest.Schedule
+			cfgExternalTest.Matrix = externalTest.Matrix
+			cfgExternalTest.Locks = externalTest.Locks
+			cfgExternalTest.Priority = externalTest.Priority
+			cfgExternalTest.ConcurrencyGroup = externalTest.ConcurrencyGroup
 			found = true
 
 			break
@@ -168,8 +191,16 @@ func (c *TestRegistry) LoadTests(ctx context.Context, local []*types.TestConfig,
 	c.testDescriptorsMutex.Lock()
 	defer c.testDescriptorsMutex.Unlock()
 
+	oldDescriptors := c.testDescriptors
 	c.testDescriptors = map[string]testDescriptorEntry{}
 
+	// keep tests that were added via api, as they are not persisted in the db
+	for testID, descriptorEntry := range oldDescriptors {
+		if descriptorEntry.descriptor.Source() == "api-call" {
+			c.testDescriptors[testID] = descriptorEntry
+		}
+	}
+
 	for _, descriptor := range descriptors {
 		if descriptor.Err() != nil {
 			c.coordinator.Logger().Errorf("error while loading test '%v': %v", descriptor.ID(), descriptor.Err())
@@ -177,8 +208,12 @@ func (c *TestRegistry) LoadTests(ctx context.Context, local []*types.TestConfig,
 			errCount++
 		}
 
-		c.testDescriptorIndex++
-		entryIndex := c.testDescriptorIndex
+		// keep the position of tests that were loaded before
+		entryIndex := oldDescriptors[descriptor.ID()].index
+		if entryIndex == 0 {
+			c.testDescriptorIndex++
+			entryIndex = c.testDescriptorIndex
+		}
 
 		c.testDescriptors[descriptor.ID()] = testDescriptorEntry{
 			descriptor: descriptor,
@@ -189,6 +224,34 @@ func (c *TestRegistry) LoadTests(ctx context.Context, local []*types.TestConfig,
 	c.coordinator.Logger().Infof("loaded %v test descriptors (%v errors)", len(descriptors), errCount)
 }
 
+// restoreRedactedConfig replaces config values that were redacted before persisting them to the db.
+// Values of tests from the config file are taken from the config file again, all other redacted values are dropped
+// and need to be provided when scheduling the test.
+func (c *TestRegistry) restoreRedactedConfig(externalTest *types.ExternalTestConfig, cfgExternalTests []*types.ExternalTestConfig) {
+	var cfgConfig map[string]interface{}
+
+	for _, cfgExternalTest := range cfgExternalTests {
+		if cfgExternalTest.ID == externalTest.ID || cfgExternalTest.File == externalTest.File {
+			cfgConfig = cfgExternalTest.Config
+			break
+		}
+	}
+
+	for key, value := range externalTest.Config {
+		if !secrets.ContainsRedacted(value) {
+			continue
+		}
+
+		if cfgValue, found := cfgConfig[key]; found && !secrets.ContainsRedacted(cfgValue) {
+			externalTest.Config[key] = cfgValue
+			continue
+		}
+
+		delete(externalTest.Config, key)
+		c.coordinator.Logger().Warnf("secret config value %v of test %v is not persisted, it needs to be provided when scheduling the test", key, externalTest.ID)
+	}
+}
+
 func (c *TestRegistry) AddLocalTest(testConfig *types.TestConfig) (types.TestDescriptor, error) {
 	if testConfig.ID == "" {
 		return nil, fmt.Errorf("cannot add test descriptor without ID")
@@ -278,9 +341,11 @@ func (c *TestRegistry) AddExternalTest(ctx context.Context, extTestCfg *types.Ex
 
 func (c *TestRegistry) externalTestCfgToDB(cfgExternalTest *types.ExternalTestConfig) (*db.TestConfig, error) {
 	dbTestCfg := &db.TestConfig{
-		TestID: cfgExternalTest.ID,
-		Source: cfgExternalTest.File,
-		Name:   cfgExternalTest.Name,
+		TestID:           cfgExternalTest.ID,
+		Source:           cfgExternalTest.File,
+		Name:             cfgExternalTest.Name,
+		Priority:         cfgExternalTest.Priority,
+		ConcurrencyGroup: cfgExternalTest.ConcurrencyGroup,
 	}
 
 	if cfgExternalTest.Timeout != nil {
@@ -302,7 +367,8 @@ func (c *TestRegistry) externalTestCfgToDB(cfgExternalTest *types.ExternalTestCo
 		dbTestCfg.ScheduleStartup = true
 	}
 
-	configYaml, err := yaml.Marshal(cfgExternalTest.Config)
+	// secret values are not persisted, they are restored from the config file or need to be provided when scheduling the test
+	configYaml, err := yaml.Marshal(secrets.RedactData(cfgExternalTest.Config))
 	if err != nil {
 		return nil, fmt.Errorf("error encoding test config %v: %v", cfgExternalTest.ID, err)
 	}
@@ -316,6 +382,24 @@ func (c *TestRegistry) externalTestCfgToDB(cfgExternalTest *types.ExternalTestCo
 
 	dbTestCfg.ConfigVars = string(configVarsYaml)
 
+	if len(cfgExternalTest.Matrix) > 0 {
+		matrixYaml, err := yaml.Marshal(cfgExternalTest.Matrix)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding test matrix %v: %v", cfgExternalTest.ID, err)
+		}
+
+		dbTestCfg.MatrixYaml = string(matrixYaml)
+	}
+
+	if len(cfgExternalTest.Locks) > 0 {
+		locksYaml, err := yaml.Marshal(cfgExternalTest.Locks)
+		if err != nil {
+			return nil, fmt.Errorf("error encoding test locks %v: %v", cfgExternalTest.ID, err)
+		}
+
+		dbTestCfg.LocksYaml = string(locksYaml)
+	}
+
 	return dbTestCfg, nil
 }
 
@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"runtime/debug"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/erigontech/assertoor/pkg/coordinator/locks"
 	"github.com/erigontech/assertoor/pkg/coordinator/test"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/gorhill/cronexpr"
+	"github.com/jmoiron/sqlx"
 )
 
 type TestRunner struct {
@@ -23,6 +26,13 @@ type TestRunner struct {
 	testRegistryMutex        sync.RWMutex
 	queueNotificationChan    chan bool
 	offQueueNotificationChan chan types.TestRunner
+
+	concurrencyLimit  uint64
+	concurrencyGroups map[string]uint64
+	runningTests      uint64
+	runningGroups     map[string]uint64
+	preemptRuns       map[uint64]bool
+	queueWaitReasons  map[uint64]string
 }
 
 func NewTestRunner(coordinator types.Coordinator, lastRunID uint64) *TestRunner {
@@ -34,6 +44,12 @@ func NewTestRunner(coordinator types.Coordinator, lastRunID uint64) *TestRunner
 		testQueue:                []types.TestRunner{},
 		queueNotificationChan:    make(chan bool, 1),
 		offQueueNotificationChan: make(chan types.TestRunner, 10),
+
+		concurrencyLimit:  1,
+		concurrencyGroups: map[string]uint64{},
+		runningGroups:     map[string]uint64{},
+		preemptRuns:       map[uint64]bool{},
+		queueWaitReasons:  map[uint64]string{},
 	}
 }
 
@@ -44,6 +60,20 @@ func (c *TestRunner) GetTestByRunID(runID uint64) types.Test {
 	return c.testRunMap[runID]
 }
 
+// HasRunningTests returns true if any test run is currently running.
+func (c *TestRunner) HasRunningTests() bool {
+	c.testRegistryMutex.RLock()
+	defer c.testRegistryMutex.RUnlock()
+
+	for _, test := range c.testRunMap {
+		if test.Status() == types.TestStatusRunning {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (c *TestRunner) GetTestQueue() []types.Test {
 	c.testRegistryMutex.RLock()
 	defer c.testRegistryMutex.RUnlock()
@@ -68,6 +98,10 @@ func (c *TestRunner) RemoveTestFromQueue(runID uint64) bool {
 
 			c.testQueue = append(c.testQueue[:idx], c.testQueue[idx+1:]...)
 			delete(c.testRunMap, runID)
+			delete(c.preemptRuns, runID)
+			delete(c.queueWaitReasons, runID)
+
+			c.coordinator.LockManager().SetWaiting(test.Locks(), runID, false)
 
 			return true
 		}
@@ -76,29 +110,110 @@ func (c *TestRunner) RemoveTestFromQueue(runID uint64) bool {
 	return false
 }
 
+func (c *TestRunner) GetTestQueueEntries() []*types.TestQueueEntry {
+	c.testRegistryMutex.RLock()
+	defer c.testRegistryMutex.RUnlock()
+
+	entries := make([]*types.TestQueueEntry, len(c.testQueue))
+	for idx, test := range c.testQueue {
+		entries[idx] = &types.TestQueueEntry{
+			Test:             test,
+			Priority:         test.Priority(),
+			ConcurrencyGroup: test.ConcurrencyGroup(),
+			Preempt:          c.preemptRuns[test.RunID()],
+			WaitReason:       c.queueWaitReasons[test.RunID()],
+		}
+	}
+
+	return entries
+}
+
+func (c *TestRunner) UpdateTestQueue(runID uint64, update *types.TestQueueUpdate) error {
+	c.testRegistryMutex.Lock()
+	defer c.testRegistryMutex.Unlock()
+
+	queueIdx := -1
+
+	for idx, test := range c.testQueue {
+		if test.RunID() == runID {
+			queueIdx = idx
+			break
+		}
+	}
+
+	if queueIdx == -1 {
+		return fmt.Errorf("test run %v is not queued", runID)
+	}
+
+	testRef := c.testQueue[queueIdx]
+	c.testQueue = append(c.testQueue[:queueIdx], c.testQueue[queueIdx+1:]...)
+
+	if update.Priority != nil {
+		testRef.SetPriority(*update.Priority)
+	}
+
+	switch {
+	case update.Preempt:
+		c.preemptRuns[runID] = true
+		c.insertTestIntoQueue(testRef, 0)
+	case update.Position != nil:
+		c.insertTestIntoQueue(testRef, *update.Position)
+	default:
+		c.insertTestIntoQueue(testRef, -1)
+	}
+
+	c.notifyQueue()
+
+	return nil
+}
+
+// insertTestIntoQueue inserts the test at the given position.
+// A negative position inserts the test behind all queued tests with the same or a higher priority.
+func (c *TestRunner) insertTestIntoQueue(testRef types.TestRunner, position int) {
+	if position < 0 {
+		position = len(c.testQueue)
+		for position > 0 && c.testQueue[position-1].Priority() < testRef.Priority() {
+			position--
+		}
+	} else if position > len(c.testQueue) {
+		position = len(c.testQueue)
+	}
+
+	c.testQueue = append(c.testQueue, nil)
+	copy(c.testQueue[position+1:], c.testQueue[position:])
+	c.testQueue[position] = testRef
+}
+
+func (c *TestRunner) notifyQueue() {
+	select {
+	case c.queueNotificationChan <- true:
+	default:
+	}
+}
+
 func (c *TestRunner) ScheduleTest(descriptor types.TestDescriptor, configOverrides map[string]any, allowDuplicate, skipQueue bool) (types.TestRunner, error) {
 	if descriptor.Err() != nil {
 		return nil, fmt.Errorf("cannot create test from failed test descriptor: %w", descriptor.Err())
 	}
 
-	testRef, err := c.createTestRun(descriptor, configOverrides, allowDuplicate, skipQueue)
+	testRefs, err := c.createTestRuns(descriptor, configOverrides, allowDuplicate, skipQueue)
 	if err != nil {
 		return nil, err
 	}
 
 	if skipQueue {
-		c.offQueueNotificationChan <- testRef
-	} else {
-		select {
-		case c.queueNotificationChan <- true:
-		default:
+		for _, testRef := range testRefs {
+			c.offQueueNotificationChan <- testRef
 		}
+	} else {
+		c.notifyQueue()
 	}
 
-	return testRef, nil
+	// return the first run, which is also the parent of all matrix runs
+	return testRefs[0], nil
 }
 
-func (c *TestRunner) createTestRun(descriptor types.TestDescriptor, configOverrides map[string]any, allowDuplicate, skipQueue bool) (types.TestRunner, error) {
+func (c *TestRunner) createTestRuns(descriptor types.TestDescriptor, configOverrides map[string]any, allowDuplicate, skipQueue bool) ([]types.TestRunner, error) {
 	c.testSchedulerMutex.Lock()
 	defer c.testSchedulerMutex.Unlock()
 
@@ -110,74 +225,212 @@ func (c *TestRunner) createTestRun(descriptor types.TestDescriptor, configOverri
 		}
 	}
 
+	matrixCombinations := test.ExpandTestMatrix(descriptor.Config().Matrix, configOverrides)
+	if len(matrixCombinations) == 0 {
+		testRef, err := c.createTestRun(descriptor, 0, configOverrides, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		c.registerTestRuns([]types.TestRunner{testRef}, skipQueue)
+
+		return []types.TestRunner{testRef}, nil
+	}
+
+	// all runs of a matrix share the run id of the first created run as parent run id
+	parentRunID := c.runIDCounter + 1
+	testRefs := make([]types.TestRunner, 0, len(matrixCombinations))
+
+	// the runs are only queued after all combinations have been created, so a failed combination fails the whole schedule
+	for _, matrixValues := range matrixCombinations {
+		testRef, err := c.createTestRun(descriptor, parentRunID, configOverrides, matrixValues)
+		if err != nil {
+			c.deleteTestRuns(testRefs)
+			return nil, fmt.Errorf("failed creating matrix run for test %v: %w", descriptor.ID(), err)
+		}
+
+		testRefs = append(testRefs, testRef)
+	}
+
+	c.registerTestRuns(testRefs, skipQueue)
+
+	return testRefs, nil
+}
+
+func (c *TestRunner) createTestRun(descriptor types.TestDescriptor, parentRunID uint64, configOverrides, matrixValues map[string]any) (types.TestRunner, error) {
 	c.runIDCounter++
 	runID := c.runIDCounter
 
-	testRef, err := test.CreateTest(runID, descriptor, c.coordinator.Logger().WithField("module", "test"), c.coordinator, configOverrides)
+	testRef, err := test.CreateTest(runID, parentRunID, descriptor, c.coordinator.Logger().WithField("module", "test"), c.coordinator, configOverrides, matrixValues)
 	if err != nil {
 		return nil, fmt.Errorf("failed initializing test run #%v '%v': %w", runID, descriptor.Config().Name, err)
 	}
 
+	return testRef, nil
+}
+
+func (c *TestRunner) registerTestRuns(testRefs []types.TestRunner, skipQueue bool) {
 	c.testRegistryMutex.Lock()
-	if !skipQueue {
-		c.testQueue = append(c.testQueue, testRef)
+	defer c.testRegistryMutex.Unlock()
+
+	for _, testRef := range testRefs {
+		if !skipQueue {
+			c.insertTestIntoQueue(testRef, -1)
+		}
+
+		c.testRunMap[testRef.RunID()] = testRef
 	}
+}
 
-	c.testRunMap[runID] = testRef
-	c.testRegistryMutex.Unlock()
+// deleteTestRuns removes the database records of created runs that have not been registered.
+func (c *TestRunner) deleteTestRuns(testRefs []types.TestRunner) {
+	database := c.coordinator.Database()
 
-	return testRef, nil
+	for _, testRef := range testRefs {
+		err := database.RunTransaction(func(tx *sqlx.Tx) error {
+			return database.DeleteTestRun(tx, testRef.RunID())
+		})
+		if err != nil {
+			c.coordinator.Logger().Errorf("failed deleting test run #%v: %v", testRef.RunID(), err)
+		}
+	}
 }
 
-func (c *TestRunner) RunTestExecutionLoop(ctx context.Context, concurrencyLimit uint64) {
+func (c *TestRunner) RunTestExecutionLoop(ctx context.Context, concurrencyLimit uint64, concurrencyGroups map[string]uint64) {
 	if concurrencyLimit < 1 {
 		concurrencyLimit = 1
 	}
 
-	semaphore := make(chan bool, concurrencyLimit)
+	c.testRegistryMutex.Lock()
+	c.concurrencyLimit = concurrencyLimit
+
+	for group, limit := range concurrencyGroups {
+		c.concurrencyGroups[group] = limit
+	}
+	c.testRegistryMutex.Unlock()
+
 	waitGroup := sync.WaitGroup{}
 
 runLoop:
-	for {
-		var nextTest types.TestRunner
+	for ctx.Err() == nil {
+		releaseChan := c.coordinator.LockManager().ReleaseNotify()
 
-		c.testRegistryMutex.Lock()
-		if len(c.testQueue) > 0 {
-			nextTest = c.testQueue[0]
-			c.testQueue = c.testQueue[1:]
-		}
-		c.testRegistryMutex.Unlock()
-
-		if nextTest != nil {
+		for _, nextTest := range c.getRunnableTests() {
 			// run next test
-			testFunc := func(nextTest types.TestRunner) {
+			waitGroup.Add(1)
+
+			go func(nextTest types.TestRunner) {
 				defer func() {
-					<-semaphore
+					c.releaseTestSlot(nextTest, true)
 					waitGroup.Done()
 				}()
 
 				c.runTest(ctx, nextTest)
-			}
-			semaphore <- true
-			if ctx.Err() != nil {
-				break runLoop
+			}(nextTest)
+		}
+
+		// sleep and wait for queue notification or released locks
+		select {
+		case <-ctx.Done():
+			break runLoop
+		case <-c.queueNotificationChan:
+		case <-releaseChan:
+		case <-time.After(60 * time.Second):
+		}
+	}
+
+	waitGroup.Wait()
+}
+
+// getRunnableTests removes and returns all queued tests that can be started right now.
+// Tests that are blocked by the concurrency limits or by locks held by other test runs stay in the queue,
+// the reason is kept for the queue overview.
+func (c *TestRunner) getRunnableTests() []types.TestRunner {
+	c.testRegistryMutex.Lock()
+	defer c.testRegistryMutex.Unlock()
+
+	lockManager := c.coordinator.LockManager()
+	runnableTests := []types.TestRunner{}
+	remainingTests := make([]types.TestRunner, 0, len(c.testQueue))
+	c.queueWaitReasons = map[uint64]string{}
+
+	for _, queuedTest := range c.testQueue {
+		runID := queuedTest.RunID()
+		testLocks := queuedTest.Locks()
+
+		if queuedTest.Status() == types.TestStatusPending {
+			waitReason := c.getQueueWaitReason(queuedTest)
+
+			if waitReason == "" && len(testLocks) > 0 && !lockManager.TryAcquire(testLocks, locks.LockHolder{RunID: runID}) {
+				waitReason = "waiting for locks"
+
+				if blockingLocks := lockManager.GetBlockingLocks(testLocks, runID); len(blockingLocks) > 0 {
+					waitReason = fmt.Sprintf("waiting for lock '%v' held by run #%v", blockingLocks[0].Name, blockingLocks[0].Holder.RunID)
+				}
+
+				lockManager.SetWaiting(testLocks, runID, true)
 			}
 
-			waitGroup.Add(1)
+			if waitReason != "" {
+				c.queueWaitReasons[runID] = waitReason
+				remainingTests = append(remainingTests, queuedTest)
 
-			go testFunc(nextTest)
-		} else {
-			// sleep and wait for queue notification
-			select {
-			case <-ctx.Done():
-				break runLoop
-			case <-c.queueNotificationChan:
-			case <-time.After(60 * time.Second):
+				continue
 			}
 		}
+
+		if len(testLocks) > 0 {
+			lockManager.SetWaiting(testLocks, runID, false)
+		}
+
+		c.runningTests++
+
+		if group := queuedTest.ConcurrencyGroup(); group != "" {
+			c.runningGroups[group]++
+		}
+
+		delete(c.preemptRuns, runID)
+
+		runnableTests = append(runnableTests, queuedTest)
 	}
 
-	waitGroup.Wait()
+	c.testQueue = remainingTests
+
+	return runnableTests
+}
+
+func (c *TestRunner) getQueueWaitReason(testRef types.TestRunner) string {
+	if c.preemptRuns[testRef.RunID()] {
+		return ""
+	}
+
+	if c.runningTests >= c.concurrencyLimit {
+		return fmt.Sprintf("waiting for a free test slot (%v/%v running)", c.runningTests, c.concurrencyLimit)
+	}
+
+	if group := testRef.ConcurrencyGroup(); group != "" {
+		if limit := c.concurrencyGroups[group]; limit > 0 && c.runningGroups[group] >= limit {
+			return fmt.Sprintf("concurrency group '%v' is full (%v/%v running)", group, c.runningGroups[group], limit)
+		}
+	}
+
+	return ""
+}
+
+func (c *TestRunner) releaseTestSlot(testRef types.TestRunner, queued bool) {
+	c.testRegistryMutex.Lock()
+
+	if queued && c.runningTests > 0 {
+		c.runningTests--
+	}
+
+	if group := testRef.ConcurrencyGroup(); group != "" && c.runningGroups[group] > 0 {
+		c.runningGroups[group]--
+	}
+
+	c.testRegistryMutex.Unlock()
+
+	c.notifyQueue()
 }
 
 func (c *TestRunner) RunOffQueueTestExecutionLoop(ctx context.Context) {
@@ -186,12 +439,29 @@ func (c *TestRunner) RunOffQueueTestExecutionLoop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case testRef := <-c.offQueueNotificationChan:
-			go c.runTest(ctx, testRef)
+			// off-queue tests are not limited, but still count towards their concurrency group
+			if group := testRef.ConcurrencyGroup(); group != "" {
+				c.testRegistryMutex.Lock()
+				c.runningGroups[group]++
+				c.testRegistryMutex.Unlock()
+			}
+
+			go func(testRef types.TestRunner) {
+				defer c.releaseTestSlot(testRef, false)
+
+				c.runTest(ctx, testRef)
+			}(testRef)
 		}
 	}
 }
 
 func (c *TestRunner) runTest(ctx context.Context, testRef types.TestRunner) {
+	defer c.coordinator.LockManager().ReleaseAll(testRef.RunID())
+
+	if !c.waitForTestLocks(ctx, testRef) {
+		return
+	}
+
 	if err := testRef.Validate(); err != nil {
 		testRef.Logger().Errorf("test validation failed: %v", err)
 		return
@@ -202,6 +472,46 @@ func (c *TestRunner) runTest(ctx context.Context, testRef types.TestRunner) {
 	}
 }
 
+// waitForTestLocks acquires the locks declared by the test and waits while they are held by other test runs.
+// Returns false if the test got aborted while waiting.
+func (c *TestRunner) waitForTestLocks(ctx context.Context, testRef types.TestRunner) bool {
+	testLocks := testRef.Locks()
+	if len(testLocks) == 0 || testRef.Status() != types.TestStatusPending {
+		return true
+	}
+
+	lockManager := c.coordinator.LockManager()
+	lockHolder := locks.LockHolder{RunID: testRef.RunID()}
+
+	if lockManager.TryAcquire(testLocks, lockHolder) {
+		return true
+	}
+
+	testRef.Logger().Infof("waiting for locks: %v", strings.Join(testLocks, ", "))
+
+	lockManager.SetWaiting(testLocks, testRef.RunID(), true)
+	defer lockManager.SetWaiting(testLocks, testRef.RunID(), false)
+
+	for {
+		releaseChan := lockManager.ReleaseNotify()
+
+		if lockManager.TryAcquire(testLocks, lockHolder) {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-releaseChan:
+		case <-time.After(10 * time.Second):
+		}
+
+		if testRef.Status() != types.TestStatusPending {
+			return false
+		}
+	}
+}
+
 func (c *TestRunner) RunTestScheduler(ctx context.Context) {
 	defer func() {
 		if err := recover(); err != nil {
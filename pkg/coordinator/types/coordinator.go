@@ -5,8 +5,10 @@ import (
 
 	"github.com/erigontech/assertoor/pkg/coordinator/clients"
 	"github.com/erigontech/assertoor/pkg/coordinator/db"
+	"github.com/erigontech/assertoor/pkg/coordinator/locks"
 	"github.com/erigontech/assertoor/pkg/coordinator/logger"
 	"github.com/erigontech/assertoor/pkg/coordinator/names"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/wallet"
 	"github.com/sirupsen/logrus"
 )
@@ -17,17 +19,39 @@ type Coordinator interface {
 	Database() *db.Database
 	ClientPool() *clients.ClientPool
 	WalletManager() *wallet.Manager
+	LockManager() *locks.Manager
+	SecretResolver() *secrets.Resolver
 	ValidatorNames() *names.ValidatorNames
 	GlobalVariables() Variables
 	TestRegistry() TestRegistry
 
 	GetTestByRunID(runID uint64) Test
 	GetTestQueue() []Test
+	GetTestQueueEntries() []*TestQueueEntry
+	UpdateTestQueue(runID uint64, update *TestQueueUpdate) error
 	GetTestHistory(testID string, firstRunID uint64, offset uint64, limit uint64) ([]Test, uint64)
+	GetTestRunGroup(parentRunID uint64) []Test
 	ScheduleTest(descriptor TestDescriptor, configOverrides map[string]any, allowDuplicate bool, skipQueue bool) (TestRunner, error)
 	DeleteTestRun(runID uint64) error
 }
 
+// TestQueueEntry describes a queued test run and the reason why it has not been started yet.
+type TestQueueEntry struct {
+	Test             Test
+	Priority         int
+	ConcurrencyGroup string
+	Preempt          bool
+	WaitReason       string
+}
+
+// TestQueueUpdate changes the position of a queued test run.
+// Position is the new zero-based index in the queue, Preempt starts the run regardless of the concurrency limits.
+type TestQueueUpdate struct {
+	Priority *int
+	Position *int
+	Preempt  bool
+}
+
 type TestRegistry interface {
 	AddLocalTest(testConfig *TestConfig) (TestDescriptor, error)
 	AddExternalTest(ctx context.Context, extTestConfig *ExternalTestConfig) (TestDescriptor, error)
@@ -2,11 +2,14 @@ package types
 
 import (
 	"context"
+	"time"
 
 	"github.com/erigontech/assertoor/pkg/coordinator/clients"
 	"github.com/erigontech/assertoor/pkg/coordinator/db"
 	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+	"github.com/erigontech/assertoor/pkg/coordinator/locks"
 	"github.com/erigontech/assertoor/pkg/coordinator/names"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/wallet"
 )
 
@@ -17,6 +20,8 @@ type TaskSchedulerRunner interface {
 	ParseTaskOptions(rawtask helper.IRawMessage) (*TaskOptions, error)
 	ExecuteTask(ctx context.Context, taskIndex TaskIndex, taskWatchFn func(ctx context.Context, cancelFn context.CancelFunc, taskIndex TaskIndex)) error
 	WatchTaskPass(ctx context.Context, cancelFn context.CancelFunc, taskIndex TaskIndex)
+	RequestApproval(request *TaskApprovalRequest) <-chan *TaskApproval
+	CancelApproval(taskIndex TaskIndex)
 }
 
 type TaskScheduler interface {
@@ -32,5 +37,51 @@ type TaskServices interface {
 	Database() *db.Database
 	ClientPool() *clients.ClientPool
 	WalletManager() *wallet.Manager
+	LockManager() *locks.Manager
+	SecretResolver() *secrets.Resolver
 	ValidatorNames() *names.ValidatorNames
 }
+
+type TaskSchedulerDebugger interface {
+	GetDebugState() *TaskDebugState
+	PauseTasks()
+	ResumeTasks()
+	StepTask()
+	SetBreakpoint(breakpoint TaskBreakpoint, enabled bool)
+	GetTaskVars(taskIndex TaskIndex) (map[string]any, error)
+	SetTaskVars(taskIndex TaskIndex, variables map[string]any) error
+}
+
+// TaskBreakpoint pauses the test before the matching task gets executed.
+// Tasks are matched either by index or by task ID.
+type TaskBreakpoint struct {
+	Index TaskIndex `yaml:"index" json:"index,omitempty"`
+	ID    string    `yaml:"id" json:"id,omitempty"`
+}
+
+type TaskDebugState struct {
+	Paused      bool             `json:"paused"`
+	PausedTasks []TaskIndex      `json:"paused_tasks"`
+	Breakpoints []TaskBreakpoint `json:"breakpoints"`
+}
+
+type TaskSchedulerApprover interface {
+	GetPendingApprovals() []*TaskApprovalRequest
+	SubmitApproval(taskIndex TaskIndex, approval *TaskApproval) error
+}
+
+// TaskApprovalRequest is a pending request for a manual approval by a task.
+type TaskApprovalRequest struct {
+	TaskIndex      TaskIndex `json:"task_index"`
+	Message        string    `json:"message"`
+	RequiredInputs []string  `json:"required_inputs"`
+	RequestTime    time.Time `json:"request_time"`
+}
+
+// TaskApproval is the decision submitted for a pending approval request.
+type TaskApproval struct {
+	Approved bool           `yaml:"approved" json:"approved"`
+	Approver string         `yaml:"approver" json:"approver"`
+	Comment  string         `yaml:"comment" json:"comment"`
+	Inputs   map[string]any `yaml:"inputs" json:"inputs"`
+}
@@ -25,6 +25,10 @@ type TestRunner interface {
 	Run(ctx context.Context) error
 	Logger() logrus.FieldLogger
 	GetTestVariables() Variables
+	Locks() []string
+	Priority() int
+	SetPriority(priority int)
+	ConcurrencyGroup() string
 }
 
 type Test interface {
@@ -35,29 +39,39 @@ type Test interface {
 	StopTime() time.Time
 	Timeout() time.Duration
 	Status() TestStatus
+	ParentRunID() uint64
+	MatrixValues() map[string]interface{}
 	GetTaskScheduler() TaskScheduler
 	AbortTest(skipCleanup bool)
 }
 
 type TestConfig struct {
-	ID           string                 `yaml:"id" json:"id"`
-	Name         string                 `yaml:"name" json:"name"`
-	Timeout      helper.Duration        `yaml:"timeout" json:"timeout"`
-	Config       map[string]interface{} `yaml:"config" json:"config"`
-	ConfigVars   map[string]string      `yaml:"configVars" json:"configVars"`
-	Tasks        []helper.RawMessage    `yaml:"tasks" json:"tasks"`
-	CleanupTasks []helper.RawMessage    `yaml:"cleanupTasks" json:"cleanupTasks"`
-	Schedule     *TestSchedule          `yaml:"schedule" json:"schedule"`
+	ID               string                 `yaml:"id" json:"id"`
+	Name             string                 `yaml:"name" json:"name"`
+	Timeout          helper.Duration        `yaml:"timeout" json:"timeout"`
+	Config           map[string]interface{} `yaml:"config" json:"config"`
+	ConfigVars       map[string]string      `yaml:"configVars" json:"configVars"`
+	Tasks            []helper.RawMessage    `yaml:"tasks" json:"tasks"`
+	CleanupTasks     []helper.RawMessage    `yaml:"cleanupTasks" json:"cleanupTasks"`
+	Schedule         *TestSchedule          `yaml:"schedule" json:"schedule"`
+	Matrix           TestMatrix             `yaml:"matrix" json:"matrix"`
+	Locks            []string               `yaml:"locks" json:"locks"`
+	Priority         int                    `yaml:"priority" json:"priority"`
+	ConcurrencyGroup string                 `yaml:"concurrencyGroup" json:"concurrencyGroup"`
 }
 
 type ExternalTestConfig struct {
-	ID         string                 `yaml:"id" json:"id"`
-	File       string                 `yaml:"file" json:"file"`
-	Name       string                 `yaml:"name" json:"name"`
-	Timeout    *helper.Duration       `yaml:"timeout" json:"timeout"`
-	Config     map[string]interface{} `yaml:"config" json:"config"`
-	ConfigVars map[string]string      `yaml:"configVars" json:"configVars"`
-	Schedule   *TestSchedule          `yaml:"schedule" json:"schedule"`
+	ID               string                 `yaml:"id" json:"id"`
+	File             string                 `yaml:"file" json:"file"`
+	Name             string                 `yaml:"name" json:"name"`
+	Timeout          *helper.Duration       `yaml:"timeout" json:"timeout"`
+	Config           map[string]interface{} `yaml:"config" json:"config"`
+	ConfigVars       map[string]string      `yaml:"configVars" json:"configVars"`
+	Schedule         *TestSchedule          `yaml:"schedule" json:"schedule"`
+	Matrix           TestMatrix             `yaml:"matrix" json:"matrix"`
+	Locks            []string               `yaml:"locks" json:"locks"`
+	Priority         *int                   `yaml:"priority" json:"priority"`
+	ConcurrencyGroup string                 `yaml:"concurrencyGroup" json:"concurrencyGroup"`
 }
 
 type TestSchedule struct {
@@ -66,6 +80,10 @@ type TestSchedule struct {
 	SkipQueue bool     `yaml:"skipQueue" json:"skipQueue"`
 }
 
+// TestMatrix maps config variable names to the list of values a test should be run with.
+// Each combination of values results in a separate test run.
+type TestMatrix map[string][]interface{}
+
 type TestDescriptor interface {
 	ID() string
 	Source() string
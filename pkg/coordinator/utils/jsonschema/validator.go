@@ -0,0 +1,361 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	maxValidationDepth  = 64
+	maxValidationErrors = 20
+)
+
+// Validator validates decoded json values against the json schema subset used by openapi & openrpc documents.
+// Supported keywords: $ref, allOf, anyOf, oneOf, type, nullable, enum, properties, required, additionalProperties,
+// items, minItems, maxItems, minLength, maxLength, pattern, minimum & maximum.
+type Validator struct {
+	document     map[string]interface{}
+	patternMutex sync.Mutex
+	patterns     map[string]*regexp.Regexp
+}
+
+func NewValidator(document map[string]interface{}) *Validator {
+	return &Validator{
+		document: document,
+		patterns: map[string]*regexp.Regexp{},
+	}
+}
+
+// ResolveRef returns the schema referenced by a local json pointer like `#/components/schemas/Name`.
+func (v *Validator) ResolveRef(ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported schema reference: %v", ref)
+	}
+
+	var current interface{} = v.document
+
+	for _, part := range strings.Split(ref[2:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+
+		currentMap, isMap := current.(map[string]interface{})
+		if !isMap {
+			return nil, fmt.Errorf("invalid schema reference: %v", ref)
+		}
+
+		current = currentMap[part]
+	}
+
+	schema, isMap := current.(map[string]interface{})
+	if !isMap {
+		return nil, fmt.Errorf("schema reference not found: %v", ref)
+	}
+
+	return schema, nil
+}
+
+// Validate returns the list of schema violations of the value. An empty list means the value is valid.
+func (v *Validator) Validate(schema, value interface{}) []string {
+	errs := []string{}
+	v.validate(schema, value, "$", 0, &errs)
+
+	return errs
+}
+
+func (v *Validator) addError(errs *[]string, path, format string, args ...interface{}) {
+	if len(*errs) >= maxValidationErrors {
+		return
+	}
+
+	*errs = append(*errs, fmt.Sprintf("%v: %v", path, fmt.Sprintf(format, args...)))
+}
+
+//nolint:gocyclo // keyword checks are easier to follow in one place
+func (v *Validator) validate(schemaObj, value interface{}, path string, depth int, errs *[]string) {
+	if depth > maxValidationDepth || len(*errs) >= maxValidationErrors {
+		return
+	}
+
+	schema, isMap := schemaObj.(map[string]interface{})
+	if !isMap {
+		return
+	}
+
+	if value == nil {
+		if nullable, _ := schema["nullable"].(bool); nullable || schemaAllowsType(schema, "null") {
+			return
+		}
+	}
+
+	if ref, isRef := schema["$ref"].(string); isRef {
+		refSchema, err := v.ResolveRef(ref)
+		if err != nil {
+			v.addError(errs, path, "%v", err)
+			return
+		}
+
+		v.validate(refSchema, value, path, depth+1, errs)
+
+		return
+	}
+
+	if allOf, isList := schema["allOf"].([]interface{}); isList {
+		for _, subSchema := range allOf {
+			v.validate(subSchema, value, path, depth+1, errs)
+		}
+	}
+
+	if anyOf, isList := schema["anyOf"].([]interface{}); isList && v.countMatches(anyOf, value, path, depth) == 0 {
+		v.addError(errs, path, "does not match any of the allowed schemas")
+	}
+
+	if oneOf, isList := schema["oneOf"].([]interface{}); isList {
+		if matches := v.countMatches(oneOf, value, path, depth); matches != 1 {
+			v.addError(errs, path, "matches %v instead of exactly one of the allowed schemas", matches)
+		}
+	}
+
+	if types := getSchemaTypes(schema); len(types) > 0 {
+		valueType := getValueType(value)
+		typeMatch := false
+
+		for _, schemaType := range types {
+			if schemaType == valueType || (schemaType == "number" && valueType == "integer") {
+				typeMatch = true
+				break
+			}
+		}
+
+		if !typeMatch {
+			v.addError(errs, path, "expected %v, got %v", strings.Join(types, " or "), valueType)
+			return
+		}
+	}
+
+	if enum, isList := schema["enum"].([]interface{}); isList {
+		found := false
+
+		for _, enumValue := range enum {
+			if fmt.Sprint(enumValue) == fmt.Sprint(value) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			v.addError(errs, path, "value %v is not one of %v", value, enum)
+		}
+	}
+
+	switch typedValue := value.(type) {
+	case string:
+		v.validateString(schema, typedValue, path, errs)
+	case float64, json.Number:
+		v.validateNumber(schema, toFloat(typedValue), path, errs)
+	case []interface{}:
+		if minItems, ok := getNumber(schema["minItems"]); ok && float64(len(typedValue)) < minItems {
+			v.addError(errs, path, "expected at least %v items, got %v", minItems, len(typedValue))
+		}
+
+		if maxItems, ok := getNumber(schema["maxItems"]); ok && float64(len(typedValue)) > maxItems {
+			v.addError(errs, path, "expected at most %v items, got %v", maxItems, len(typedValue))
+		}
+
+		if items, hasItems := schema["items"]; hasItems {
+			for idx, item := range typedValue {
+				v.validate(items, item, fmt.Sprintf("%v[%v]", path, idx), depth+1, errs)
+			}
+		}
+	case map[string]interface{}:
+		v.validateObject(schema, typedValue, path, depth, errs)
+	}
+}
+
+func (v *Validator) validateString(schema map[string]interface{}, value, path string, errs *[]string) {
+	if minLength, ok := getNumber(schema["minLength"]); ok && float64(len(value)) < minLength {
+		v.addError(errs, path, "expected at least %v characters, got %v", minLength, len(value))
+	}
+
+	if maxLength, ok := getNumber(schema["maxLength"]); ok && float64(len(value)) > maxLength {
+		v.addError(errs, path, "expected at most %v characters, got %v", maxLength, len(value))
+	}
+
+	if pattern, isString := schema["pattern"].(string); isString {
+		regex, err := v.getPattern(pattern)
+		if err != nil {
+			v.addError(errs, path, "invalid pattern %v: %v", pattern, err)
+		} else if !regex.MatchString(value) {
+			v.addError(errs, path, "value %q does not match pattern %v", shortenValue(value), pattern)
+		}
+	}
+}
+
+func (v *Validator) validateNumber(schema map[string]interface{}, value float64, path string, errs *[]string) {
+	if minimum, ok := getNumber(schema["minimum"]); ok && value < minimum {
+		v.addError(errs, path, "value %v is lower than %v", value, minimum)
+	}
+
+	if maximum, ok := getNumber(schema["maximum"]); ok && value > maximum {
+		v.addError(errs, path, "value %v is higher than %v", value, maximum)
+	}
+}
+
+func (v *Validator) validateObject(schema, value map[string]interface{}, path string, depth int, errs *[]string) {
+	if required, isList := schema["required"].([]interface{}); isList {
+		for _, field := range required {
+			if _, found := value[fmt.Sprint(field)]; !found {
+				v.addError(errs, path, "missing required property %v", field)
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		propPath := fmt.Sprintf("%v.%v", path, key)
+
+		if propSchema, found := properties[key]; found {
+			v.validate(propSchema, value[key], propPath, depth+1, errs)
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.addError(errs, path, "unexpected property %v", key)
+			}
+		case map[string]interface{}:
+			v.validate(additional, value[key], propPath, depth+1, errs)
+		}
+	}
+}
+
+func (v *Validator) countMatches(schemas []interface{}, value interface{}, path string, depth int) int {
+	matches := 0
+
+	for _, subSchema := range schemas {
+		subErrs := []string{}
+		v.validate(subSchema, value, path, depth+1, &subErrs)
+
+		if len(subErrs) == 0 {
+			matches++
+		}
+	}
+
+	return matches
+}
+
+func (v *Validator) getPattern(pattern string) (*regexp.Regexp, error) {
+	v.patternMutex.Lock()
+	defer v.patternMutex.Unlock()
+
+	if regex, found := v.patterns[pattern]; found {
+		return regex, nil
+	}
+
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	v.patterns[pattern] = regex
+
+	return regex, nil
+}
+
+func getSchemaTypes(schema map[string]interface{}) []string {
+	switch schemaType := schema["type"].(type) {
+	case string:
+		return []string{schemaType}
+	case []interface{}:
+		types := make([]string, 0, len(schemaType))
+		for _, t := range schemaType {
+			types = append(types, fmt.Sprint(t))
+		}
+
+		return types
+	}
+
+	return nil
+}
+
+func schemaAllowsType(schema map[string]interface{}, valueType string) bool {
+	for _, schemaType := range getSchemaTypes(schema) {
+		if schemaType == valueType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getValueType(value interface{}) string {
+	switch typedValue := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if typedValue == math.Trunc(typedValue) {
+			return "integer"
+		}
+
+		return "number"
+	case json.Number:
+		if _, err := typedValue.Int64(); err == nil || !strings.ContainsAny(typedValue.String(), ".eE") {
+			return "integer"
+		}
+
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func getNumber(value interface{}) (float64, bool) {
+	switch typedValue := value.(type) {
+	case int:
+		return float64(typedValue), true
+	case int64:
+		return float64(typedValue), true
+	case uint64:
+		return float64(typedValue), true
+	case float64:
+		return typedValue, true
+	case json.Number:
+		f, err := typedValue.Float64()
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+func toFloat(value interface{}) float64 {
+	f, _ := getNumber(value)
+	return f
+}
+
+func shortenValue(value string) string {
+	if len(value) > 80 {
+		return value[:77] + "..."
+	}
+
+	return value
+}
@@ -0,0 +1,561 @@
+package vars
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/itchyny/gojq"
+)
+
+// JqFunction is a custom function that can be called in variable queries.
+type JqFunction struct {
+	Name     string
+	MinArity int
+	MaxArity int
+	Callback func(input any, args []any) any
+}
+
+var (
+	jqFunctionsMutex sync.RWMutex
+	jqFunctions      = map[string]*JqFunction{}
+)
+
+// RegisterJqFunction adds a custom function to all variable queries.
+// Functions that are registered later replace functions with the same name.
+func RegisterJqFunction(fn *JqFunction) {
+	jqFunctionsMutex.Lock()
+	defer jqFunctionsMutex.Unlock()
+
+	jqFunctions[fmt.Sprintf("%v/%v-%v", fn.Name, fn.MinArity, fn.MaxArity)] = fn
+}
+
+func compileQuery(queryStr string) (*gojq.Code, error) {
+	query, err := gojq.Parse(queryStr)
+	if err != nil {
+		return nil, err
+	}
+
+	jqFunctionsMutex.RLock()
+	options := make([]gojq.CompilerOption, 0, len(jqFunctions))
+
+	for _, fn := range jqFunctions {
+		options = append(options, gojq.WithFunction(fn.Name, fn.MinArity, fn.MaxArity, fn.Callback))
+	}
+	jqFunctionsMutex.RUnlock()
+
+	return gojq.Compile(query, options...)
+}
+
+var unitDecimals = map[string]int64{
+	"wei":   0,
+	"gwei":  9,
+	"ether": 18,
+	"eth":   18,
+}
+
+//nolint:gochecknoinits // ignore
+func init() {
+	builtinFunctions := []*JqFunction{
+		{Name: "to_wei", MinArity: 1, MaxArity: 1, Callback: jqToWei},
+		{Name: "from_wei", MinArity: 1, MaxArity: 1, Callback: jqFromWei},
+		{Name: "big_add", MinArity: 1, MaxArity: 1, Callback: jqBigMath(func(a, b *big.Int) (*big.Int, error) { return new(big.Int).Add(a, b), nil })},
+		{Name: "big_sub", MinArity: 1, MaxArity: 1, Callback: jqBigMath(func(a, b *big.Int) (*big.Int, error) { return new(big.Int).Sub(a, b), nil })},
+		{Name: "big_mul", MinArity: 1, MaxArity: 1, Callback: jqBigMath(func(a, b *big.Int) (*big.Int, error) { return new(big.Int).Mul(a, b), nil })},
+		{Name: "big_div", MinArity: 1, MaxArity: 1, Callback: jqBigMath(func(a, b *big.Int) (*big.Int, error) {
+			if b.Sign() == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+
+			return new(big.Int).Quo(a, b), nil
+		})},
+		{Name: "big_mod", MinArity: 1, MaxArity: 1, Callback: jqBigMath(func(a, b *big.Int) (*big.Int, error) {
+			if b.Sign() == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+
+			return new(big.Int).Rem(a, b), nil
+		})},
+		{Name: "big_cmp", MinArity: 1, MaxArity: 1, Callback: jqBigCmp},
+		{Name: "to_hex", MinArity: 0, MaxArity: 0, Callback: jqToHex},
+		{Name: "from_hex", MinArity: 0, MaxArity: 0, Callback: jqFromHex},
+		{Name: "keccak256", MinArity: 0, MaxArity: 0, Callback: jqKeccak256},
+		{Name: "checksum_address", MinArity: 0, MaxArity: 0, Callback: jqChecksumAddress},
+		{Name: "abi_encode", MinArity: 2, MaxArity: 2, Callback: jqAbiEncode},
+		{Name: "abi_decode", MinArity: 1, MaxArity: 1, Callback: jqAbiDecode},
+	}
+
+	for _, fn := range builtinFunctions {
+		RegisterJqFunction(fn)
+	}
+}
+
+// ParseBigInt parses numbers, decimal strings and 0x prefixed hex strings.
+func ParseBigInt(value any) (*big.Int, error) {
+	switch v := value.(type) {
+	case int:
+		return big.NewInt(int64(v)), nil
+	case float64:
+		res, accuracy := big.NewFloat(v).Int(nil)
+		if accuracy != big.Exact {
+			return nil, fmt.Errorf("%v is not an integer", v)
+		}
+
+		return res, nil
+	case *big.Int:
+		return v, nil
+	case string:
+		str := strings.TrimSpace(v)
+		base := 10
+
+		if strings.HasPrefix(str, "0x") || strings.HasPrefix(str, "0X") {
+			str = str[2:]
+			base = 16
+		}
+
+		res, ok := new(big.Int).SetString(str, base)
+		if !ok {
+			return nil, fmt.Errorf("invalid number: %v", v)
+		}
+
+		return res, nil
+	default:
+		return nil, fmt.Errorf("invalid number type: %T", value)
+	}
+}
+
+func parseUnit(unit any) (int64, error) {
+	unitStr, ok := unit.(string)
+	if !ok {
+		return 0, fmt.Errorf("unit must be a string")
+	}
+
+	decimals, ok := unitDecimals[strings.ToLower(unitStr)]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit: %v", unitStr)
+	}
+
+	return decimals, nil
+}
+
+// jqToWei converts an amount in the given unit to a decimal wei string, e.g. `"1.5" | to_wei("ether")`.
+func jqToWei(input any, args []any) any {
+	decimals, err := parseUnit(args[0])
+	if err != nil {
+		return err
+	}
+
+	var amount *big.Rat
+
+	switch v := input.(type) {
+	case float64:
+		amount, _ = new(big.Rat).SetString(strconv.FormatFloat(v, 'f', -1, 64))
+	case string:
+		if strings.HasPrefix(v, "0x") {
+			bigValue, err := ParseBigInt(v)
+			if err != nil {
+				return err
+			}
+
+			amount = new(big.Rat).SetInt(bigValue)
+		} else if amount, _ = new(big.Rat).SetString(v); amount == nil {
+			return fmt.Errorf("invalid amount: %v", v)
+		}
+	default:
+		bigValue, err := ParseBigInt(input)
+		if err != nil {
+			return err
+		}
+
+		amount = new(big.Rat).SetInt(bigValue)
+	}
+
+	amount.Mul(amount, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil)))
+
+	if !amount.IsInt() {
+		return fmt.Errorf("amount has more decimals than wei")
+	}
+
+	return amount.Num().String()
+}
+
+// jqFromWei converts a wei amount to a decimal string in the given unit, e.g. `"1500000000" | from_wei("gwei")`.
+func jqFromWei(input any, args []any) any {
+	decimals, err := parseUnit(args[0])
+	if err != nil {
+		return err
+	}
+
+	amount, err := ParseBigInt(input)
+	if err != nil {
+		return err
+	}
+
+	res := new(big.Rat).SetFrac(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil)).FloatString(int(decimals))
+	if strings.Contains(res, ".") {
+		res = strings.TrimRight(strings.TrimRight(res, "0"), ".")
+	}
+
+	return res
+}
+
+func jqBigMath(op func(a, b *big.Int) (*big.Int, error)) func(input any, args []any) any {
+	return func(input any, args []any) any {
+		a, err := ParseBigInt(input)
+		if err != nil {
+			return err
+		}
+
+		b, err := ParseBigInt(args[0])
+		if err != nil {
+			return err
+		}
+
+		res, err := op(a, b)
+		if err != nil {
+			return err
+		}
+
+		return res.String()
+	}
+}
+
+func jqBigCmp(input any, args []any) any {
+	a, err := ParseBigInt(input)
+	if err != nil {
+		return err
+	}
+
+	b, err := ParseBigInt(args[0])
+	if err != nil {
+		return err
+	}
+
+	return a.Cmp(b)
+}
+
+func jqToHex(input any, _ []any) any {
+	value, err := ParseBigInt(input)
+	if err != nil {
+		return err
+	}
+
+	return hexutil.EncodeBig(value)
+}
+
+func jqFromHex(input any, _ []any) any {
+	value, err := ParseBigInt(input)
+	if err != nil {
+		return err
+	}
+
+	return value.String()
+}
+
+// jqKeccak256 hashes hex encoded data (0x prefixed) or the utf8 bytes of any other string.
+func jqKeccak256(input any, _ []any) any {
+	str, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("keccak256 input must be a string")
+	}
+
+	data := []byte(str)
+
+	if strings.HasPrefix(str, "0x") {
+		hexData, err := hexutil.Decode(str)
+		if err == nil {
+			data = hexData
+		}
+	}
+
+	return crypto.Keccak256Hash(data).Hex()
+}
+
+func jqChecksumAddress(input any, _ []any) any {
+	str, ok := input.(string)
+	if !ok || !common.IsHexAddress(str) {
+		return fmt.Errorf("invalid address: %v", input)
+	}
+
+	return common.HexToAddress(str).Hex()
+}
+
+// parseAbiSignature parses signatures like `transfer(address,uint256)` or `(address,uint256)`.
+func parseAbiSignature(signature string) (selector []byte, args abi.Arguments, err error) {
+	signature = strings.ReplaceAll(signature, " ", "")
+
+	openIdx := strings.Index(signature, "(")
+	if openIdx < 0 || !strings.HasSuffix(signature, ")") {
+		return nil, nil, fmt.Errorf("invalid abi signature: %v", signature)
+	}
+
+	if openIdx > 0 {
+		selector = crypto.Keccak256([]byte(signature))[:4]
+	}
+
+	typesStr := signature[openIdx+1 : len(signature)-1]
+	if typesStr == "" {
+		return selector, args, nil
+	}
+
+	for _, typeStr := range strings.Split(typesStr, ",") {
+		abiType, err := abi.NewType(typeStr, "", nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid abi type %v: %w", typeStr, err)
+		}
+
+		args = append(args, abi.Argument{Type: abiType})
+	}
+
+	return selector, args, nil
+}
+
+// jqAbiEncode encodes a call, e.g. `abi_encode("transfer(address,uint256)"; ["0x...", "1000"])`.
+// Signatures without function name encode the arguments only.
+func jqAbiEncode(_ any, args []any) any {
+	signature, ok := args[0].(string)
+	if !ok {
+		return fmt.Errorf("abi signature must be a string")
+	}
+
+	values, ok := args[1].([]any)
+	if !ok {
+		return fmt.Errorf("abi arguments must be an array")
+	}
+
+	selector, abiArgs, err := parseAbiSignature(signature)
+	if err != nil {
+		return err
+	}
+
+	if len(values) != len(abiArgs) {
+		return fmt.Errorf("expected %v abi arguments, got %v", len(abiArgs), len(values))
+	}
+
+	packValues := make([]any, len(values))
+
+	for idx, value := range values {
+		packValue, err := convertAbiValue(&abiArgs[idx].Type, value)
+		if err != nil {
+			return fmt.Errorf("abi argument %v: %w", idx, err)
+		}
+
+		packValues[idx] = packValue.Interface()
+	}
+
+	data, err := abiArgs.Pack(packValues...)
+	if err != nil {
+		return err
+	}
+
+	return hexutil.Encode(append(selector, data...))
+}
+
+// jqAbiDecode decodes hex data with the given signature, e.g. `.calldata | abi_decode("transfer(address,uint256)")`.
+// The 4 byte selector is stripped from the data if the signature contains a function name.
+func jqAbiDecode(input any, args []any) any {
+	signature, ok := args[0].(string)
+	if !ok {
+		return fmt.Errorf("abi signature must be a string")
+	}
+
+	dataStr, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("abi data must be a hex string")
+	}
+
+	data, err := hexutil.Decode(dataStr)
+	if err != nil {
+		return err
+	}
+
+	selector, abiArgs, err := parseAbiSignature(signature)
+	if err != nil {
+		return err
+	}
+
+	if selector != nil {
+		if len(data) < 4 || !reflect.DeepEqual(data[:4], selector) {
+			return fmt.Errorf("data does not match selector of %v", signature)
+		}
+
+		data = data[4:]
+	}
+
+	values, err := abiArgs.Unpack(data)
+	if err != nil {
+		return err
+	}
+
+	res := make([]any, len(values))
+	for idx, value := range values {
+		res[idx] = generalizeAbiValue(reflect.ValueOf(value))
+	}
+
+	return res
+}
+
+// checkAbiIntRange returns an error if the value does not fit into the given int or uint type,
+// so values are never truncated silently.
+func checkAbiIntRange(abiType *abi.Type, value *big.Int) error {
+	if abiType.T == abi.UintTy {
+		if value.Sign() < 0 || value.BitLen() > abiType.Size {
+			return fmt.Errorf("value %v out of range for %v", value.String(), abiType.String())
+		}
+
+		return nil
+	}
+
+	// signed values use one bit for the sign, negative values range down to -2^(size-1)
+	magnitude := value
+	if value.Sign() < 0 {
+		magnitude = new(big.Int).Neg(value)
+		magnitude.Sub(magnitude, big.NewInt(1))
+	}
+
+	if magnitude.BitLen() > abiType.Size-1 {
+		return fmt.Errorf("value %v out of range for %v", value.String(), abiType.String())
+	}
+
+	return nil
+}
+
+func convertAbiValue(abiType *abi.Type, value any) (reflect.Value, error) {
+	goType := abiType.GetType()
+
+	switch abiType.T {
+	case abi.IntTy, abi.UintTy:
+		bigValue, err := ParseBigInt(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if err := checkAbiIntRange(abiType, bigValue); err != nil {
+			return reflect.Value{}, err
+		}
+
+		if goType == reflect.TypeOf(bigValue) {
+			return reflect.ValueOf(bigValue), nil
+		}
+
+		if abiType.T == abi.UintTy {
+			return reflect.ValueOf(bigValue.Uint64()).Convert(goType), nil
+		}
+
+		return reflect.ValueOf(bigValue.Int64()).Convert(goType), nil
+	case abi.BoolTy:
+		boolValue, ok := value.(bool)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected bool, got %T", value)
+		}
+
+		return reflect.ValueOf(boolValue), nil
+	case abi.StringTy:
+		return reflect.ValueOf(fmt.Sprintf("%v", value)), nil
+	case abi.AddressTy:
+		str, ok := value.(string)
+		if !ok || !common.IsHexAddress(str) {
+			return reflect.Value{}, fmt.Errorf("invalid address: %v", value)
+		}
+
+		return reflect.ValueOf(common.HexToAddress(str)), nil
+	case abi.BytesTy, abi.FixedBytesTy:
+		str, ok := value.(string)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected hex string, got %T", value)
+		}
+
+		data, err := hexutil.Decode(str)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if abiType.T == abi.BytesTy {
+			return reflect.ValueOf(data), nil
+		}
+
+		if len(data) > abiType.Size {
+			return reflect.Value{}, fmt.Errorf("value too long for bytes%v", abiType.Size)
+		}
+
+		res := reflect.New(goType).Elem()
+		reflect.Copy(res, reflect.ValueOf(data))
+
+		return res, nil
+	case abi.SliceTy, abi.ArrayTy:
+		items, ok := value.([]any)
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("expected array, got %T", value)
+		}
+
+		var res reflect.Value
+
+		if abiType.T == abi.SliceTy {
+			res = reflect.MakeSlice(goType, len(items), len(items))
+		} else {
+			if len(items) != abiType.Size {
+				return reflect.Value{}, fmt.Errorf("expected %v items, got %v", abiType.Size, len(items))
+			}
+
+			res = reflect.New(goType).Elem()
+		}
+
+		for idx, item := range items {
+			itemValue, err := convertAbiValue(abiType.Elem, item)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+
+			res.Index(idx).Set(itemValue)
+		}
+
+		return res, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported abi type: %v", abiType.String())
+	}
+}
+
+func generalizeAbiValue(value reflect.Value) any {
+	switch v := value.Interface().(type) {
+	case *big.Int:
+		return v.String()
+	case common.Address:
+		return v.Hex()
+	case []byte:
+		return hexutil.Encode(v)
+	case bool, string:
+		return v
+	}
+
+	switch value.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			data := make([]byte, value.Len())
+			reflect.Copy(reflect.ValueOf(data), value)
+
+			return hexutil.Encode(data)
+		}
+
+		fallthrough
+	case reflect.Slice:
+		res := make([]any, value.Len())
+		for idx := range res {
+			res[idx] = generalizeAbiValue(value.Index(idx))
+		}
+
+		return res
+	default:
+		return fmt.Sprintf("%v", value.Interface())
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
-	"github.com/itchyny/gojq"
 	"gopkg.in/yaml.v3"
 )
 
@@ -242,7 +241,7 @@ func (v *Variables) ResolveQuery(queryStr string) (val interface{}, ok bool, err
 
 	queryStr = fmt.Sprintf(".%v", queryStr)
 
-	query, err := gojq.Parse(queryStr)
+	query, err := compileQuery(queryStr)
 	if err != nil {
 		return nil, false, fmt.Errorf("could not parse variable query '%v': %v", queryStr, err)
 	}
@@ -255,6 +254,10 @@ func (v *Variables) ResolveQuery(queryStr string) (val interface{}, ok bool, err
 		return nil, false, nil
 	}
 
+	if err, isErr := val.(error); isErr {
+		return nil, false, fmt.Errorf("failed executing variable query '%v': %v", queryStr, err)
+	}
+
 	return val, true, nil
 }
 
@@ -273,7 +276,7 @@ func (v *Variables) ConsumeVars(config interface{}, consumeMap map[string]string
 	for cfgName, varQuery := range consumeMap {
 		queryStr := fmt.Sprintf(".%v", varQuery)
 
-		query, err2 := gojq.Parse(queryStr)
+		query, err2 := compileQuery(queryStr)
 		if err2 != nil {
 			return fmt.Errorf("could not parse variable query '%v': %v", queryStr, err2)
 		}
@@ -286,6 +289,10 @@ func (v *Variables) ConsumeVars(config interface{}, consumeMap map[string]string
 			continue
 		}
 
+		if err, isErr := val.(error); isErr {
+			return fmt.Errorf("failed executing variable query '%v': %v", queryStr, err)
+		}
+
 		if v, ok := val.(float64); ok {
 			val = NoScientificFloat64(v)
 		}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"errors"
+	"fmt"
 	"runtime/debug"
 	"sync"
 	"time"
 
 	"github.com/erigontech/assertoor/pkg/coordinator/clients/execution"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/ethereum/go-ethereum/common"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -48,6 +50,9 @@ func (manager *Manager) GetWalletByPrivkey(privkey *ecdsa.PrivateKey) (*Wallet,
 
 	if wallet.privkey == nil {
 		wallet.privkey = privkey
+
+		// private keys of all wallets are masked in logs, database and api responses
+		secrets.AddSecret(fmt.Sprintf("%x", crypto.FromECDSA(privkey)))
 	}
 
 	return wallet, nil
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/rpcstats"
+)
+
+type GetClientsResponse struct {
+	Index        uint16                      `json:"index"`
+	Name         string                      `json:"name"`
+	Weight       uint64                      `json:"weight"`
+	Drained      bool                        `json:"drained"`
+	CLType       string                      `json:"cl_type"`
+	CLVersion    string                      `json:"cl_version"`
+	CLStatus     string                      `json:"cl_status"`
+	CLReady      bool                        `json:"cl_ready"`
+	CLHeadSlot   uint64                      `json:"cl_head_slot"`
+	CLSSZ        bool                        `json:"cl_ssz"`
+	CLLastError  string                      `json:"cl_error,omitempty"`
+	CLRequests   *GetClientsResponseRequests `json:"cl_requests"`
+	ELType       string                      `json:"el_type"`
+	ELVersion    string                      `json:"el_version"`
+	ELStatus     string                      `json:"el_status"`
+	ELReady      bool                        `json:"el_ready"`
+	ELHeadNumber uint64                      `json:"el_head_number"`
+	ELLastError  string                      `json:"el_error,omitempty"`
+	ELRequests   *GetClientsResponseRequests `json:"el_requests"`
+}
+
+type GetClientsResponseRequests struct {
+	Requests     uint64     `json:"requests"`
+	Errors       uint64     `json:"errors"`
+	Timeouts     uint64     `json:"timeouts"`
+	Retries      uint64     `json:"retries"`
+	AvgLatency   int64      `json:"avg_latency_ms"`
+	ErrorRate    float64    `json:"error_rate"`
+	Ejections    uint64     `json:"ejections"`
+	Ejected      bool       `json:"ejected"`
+	EjectedUntil *time.Time `json:"ejected_until,omitempty"`
+	CircuitState string     `json:"circuit_state"`
+	CircuitOpens uint64     `json:"circuit_openings"`
+}
+
+// GetClients godoc
+// @Id getClients
+// @Summary Get list of endpoints in the client pool
+// @Tags Client
+// @Description Returns the list of consensus & execution client pairs with their current status.
+// @Produce  json
+// @Success 200 {object} Response{data=[]GetClientsResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 500 {object} Response "Server Error"
+// @Router /api/v1/clients [get]
+func (ah *APIHandler) GetClients(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	clientPool := ah.coordinator.ClientPool()
+	clientList := []*GetClientsResponse{}
+
+	for _, client := range clientPool.GetAllClients() {
+		headSlot, _ := client.ConsensusClient.GetLastHead()
+		blockNum, _ := client.ExecutionClient.GetLastHead()
+
+		clientRes := &GetClientsResponse{
+			Index:        client.ConsensusClient.GetIndex(),
+			Name:         client.Config.Name,
+			Weight:       client.ConsensusClient.GetWeight(),
+			Drained:      client.IsDisabled(),
+			CLType:       client.ConsensusClient.GetClientType().String(),
+			CLVersion:    client.ConsensusClient.GetVersion(),
+			CLStatus:     client.ConsensusClient.GetStatus().String(),
+			CLReady:      clientPool.GetConsensusPool().GetCanonicalFork(2).IsClientReady(client.ConsensusClient),
+			CLHeadSlot:   uint64(headSlot),
+			CLSSZ:        client.ConsensusClient.GetRPCClient().IsSSZEnabled(),
+			CLRequests:   getClientsResponseRequests(client.ConsensusClient.GetRequestStats()),
+			ELType:       client.ExecutionClient.GetClientType().String(),
+			ELVersion:    client.ExecutionClient.GetVersion(),
+			ELStatus:     client.ExecutionClient.GetStatus().String(),
+			ELReady:      clientPool.GetExecutionPool().GetCanonicalFork(2).IsClientReady(client.ExecutionClient),
+			ELHeadNumber: blockNum,
+			ELRequests:   getClientsResponseRequests(client.ExecutionClient.GetRequestStats()),
+		}
+
+		if lastError := client.ConsensusClient.GetLastError(); lastError != nil {
+			clientRes.CLLastError = lastError.Error()
+		}
+
+		if lastError := client.ExecutionClient.GetLastError(); lastError != nil {
+			clientRes.ELLastError = lastError.Error()
+		}
+
+		clientList = append(clientList, clientRes)
+	}
+
+	ah.sendOKResponse(w, r.URL.String(), clientList)
+}
+
+func getClientsResponseRequests(stats rpcstats.Stats) *GetClientsResponseRequests {
+	res := &GetClientsResponseRequests{
+		Requests:   stats.Requests,
+		Errors:     stats.Errors,
+		Timeouts:   stats.Timeouts,
+		Retries:    stats.Retries,
+		AvgLatency: stats.AvgLatency.Milliseconds(),
+		ErrorRate:  stats.ErrorRate,
+		Ejections:  stats.Ejections,
+		Ejected:    time.Now().Before(stats.EjectedUntil),
+
+		CircuitState: stats.CircuitState.String(),
+		CircuitOpens: stats.CircuitOpenings,
+	}
+
+	if res.Ejected {
+		res.EjectedUntil = &stats.EjectedUntil
+	}
+
+	return res
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"net/http"
+)
+
+type GetLocksResponse struct {
+	Name        string   `json:"name"`
+	Held        bool     `json:"held"`
+	RunID       uint64   `json:"run_id,omitempty"`
+	TaskIndex   uint64   `json:"task_index,omitempty"`
+	AcquireTime int64    `json:"acquire_time,omitempty"`
+	WaitingRuns []uint64 `json:"waiting_runs"`
+}
+
+// GetLocks godoc
+// @Id getLocks
+// @Summary Get list of test locks
+// @Tags TestRun
+// @Description Returns the list of named locks that are currently held by test runs or awaited by pending test runs.
+// @Produce  json
+// @Success 200 {object} Response{data=[]GetLocksResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 500 {object} Response "Server Error"
+// @Router /api/v1/locks [get]
+func (ah *APIHandler) GetLocks(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	lockList := []*GetLocksResponse{}
+
+	for _, lockInfo := range ah.coordinator.LockManager().GetLocks() {
+		lockRes := &GetLocksResponse{
+			Name:        lockInfo.Name,
+			Held:        lockInfo.Holder.RunID != 0,
+			RunID:       lockInfo.Holder.RunID,
+			TaskIndex:   lockInfo.Holder.TaskIndex,
+			WaitingRuns: lockInfo.Waiting,
+		}
+
+		if lockRes.WaitingRuns == nil {
+			lockRes.WaitingRuns = []uint64{}
+		}
+
+		if !lockInfo.AcquireTime.IsZero() {
+			lockRes.AcquireTime = lockInfo.AcquireTime.Unix()
+		}
+
+		lockList = append(lockList, lockRes)
+	}
+
+	ah.sendOKResponse(w, r.URL.String(), lockList)
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/gorilla/mux"
 	"gopkg.in/yaml.v3"
@@ -97,7 +98,7 @@ func (ah *APIHandler) GetTestRunTaskDetails(w http.ResponseWriter, r *http.Reque
 	}
 
 	if taskStatus.Error != nil {
-		taskData.ResultError = taskStatus.Error.Error()
+		taskData.ResultError = secrets.Redact(taskStatus.Error.Error())
 	}
 
 	// logs (limit 100 entries)
@@ -132,13 +133,13 @@ func (ah *APIHandler) GetTestRunTaskDetails(w http.ResponseWriter, r *http.Reque
 
 	// config yaml
 	if cfgData, err := yaml.Marshal(taskState.Config()); err == nil {
-		taskData.ConfigYaml = string(cfgData)
+		taskData.ConfigYaml = secrets.Redact(string(cfgData))
 	} else {
 		taskData.ConfigYaml = fmt.Sprintf("failed marshalling config: %v", err)
 	}
 
 	// result yaml
-	if resData, err := yaml.Marshal(taskState.GetTaskStatusVars().GetVarsMap(nil, false)); err == nil {
+	if resData, err := yaml.Marshal(secrets.RedactData(taskState.GetTaskStatusVars().GetVarsMap(nil, false))); err == nil {
 		taskData.ResultYaml = string(resData)
 	} else {
 		taskData.ResultYaml = fmt.Sprintf("failed marshalling result: %v", err)
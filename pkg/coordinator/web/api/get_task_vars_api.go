@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/erigontech/assertoor/pkg/coordinator/vars"
+	"github.com/gorilla/mux"
+)
+
+type GetTestRunTaskVarsResponse struct {
+	RunID     uint64 `json:"run_id"`
+	TaskIndex uint64 `json:"task_index"`
+	Vars      any    `json:"vars"`
+}
+
+// GetTestRunTaskVars godoc
+// @Id getTestRunTaskVars
+// @Summary Get variable scope of a task
+// @Tags TestRun
+// @Description Returns the full variable scope of the task, including all variables inherited from parent scopes.
+// @Produce json
+// @Param runId path string true "ID of the test run"
+// @Param taskIndex path string true "Index of the task"
+// @Success 200 {object} Response{data=GetTestRunTaskVarsResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 404 {object} Response "Not Found"
+// @Failure 500 {object} Response "Server Error"
+// @Router /api/v1/test_run/{runId}/task/{taskIndex}/vars [get]
+func (ah *APIHandler) GetTestRunTaskVars(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	taskIdx, err := strconv.ParseUint(mux.Vars(r)["taskIndex"], 10, 64)
+	if err != nil {
+		ah.sendErrorResponse(w, r.URL.String(), "invalid taskIndex provided", http.StatusBadRequest)
+		return
+	}
+
+	testInstance, debugger := ah.getTestRunDebugger(w, r)
+	if debugger == nil {
+		return
+	}
+
+	varsMap, err := debugger.GetTaskVars(types.TaskIndex(taskIdx))
+	if err != nil {
+		ah.sendErrorResponse(w, r.URL.String(), err.Error(), http.StatusNotFound)
+		return
+	}
+
+	generalizedVars, err := vars.GeneralizeData(varsMap)
+	if err != nil {
+		ah.sendErrorResponse(w, r.URL.String(), "failed to serialize task variables", http.StatusInternalServerError)
+		return
+	}
+
+	ah.sendOKResponse(w, r.URL.String(), &GetTestRunTaskVarsResponse{
+		RunID:     testInstance.RunID(),
+		TaskIndex: taskIdx,
+		Vars:      secrets.RedactData(generalizedVars),
+	})
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/gorilla/mux"
 )
@@ -62,7 +63,7 @@ func (ah *APIHandler) GetTest(w http.ResponseWriter, r *http.Request) {
 		Source:     testDescriptor.Source(),
 		Name:       testConfig.Name,
 		Timeout:    uint64(testConfig.Timeout.Seconds()),
-		Config:     testConfig.Config,
+		Config:     secrets.RedactData(testConfig.Config).(map[string]interface{}),
 		ConfigVars: testConfig.ConfigVars,
 		Schedule:   testConfig.Schedule,
 	})
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+)
+
+type GetTestQueueResponse struct {
+	Position         int    `json:"position"`
+	RunID            uint64 `json:"run_id"`
+	TestID           string `json:"test_id"`
+	Name             string `json:"name"`
+	Status           string `json:"status"`
+	Priority         int    `json:"priority"`
+	ConcurrencyGroup string `json:"concurrency_group,omitempty"`
+	Preempt          bool   `json:"preempt"`
+	WaitReason       string `json:"wait_reason"`
+}
+
+// GetTestQueue godoc
+// @Id getTestQueue
+// @Summary Get the test run queue
+// @Tags TestRun
+// @Description Returns the queued test runs in execution order, together with the reason why each run has not been started yet.
+// @Produce  json
+// @Success 200 {object} Response{data=[]GetTestQueueResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 500 {object} Response "Server Error"
+// @Router /api/v1/test_queue [get]
+func (ah *APIHandler) GetTestQueue(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	queue := []*GetTestQueueResponse{}
+
+	for idx, entry := range ah.coordinator.GetTestQueueEntries() {
+		queue = append(queue, &GetTestQueueResponse{
+			Position:         idx,
+			RunID:            entry.Test.RunID(),
+			TestID:           entry.Test.TestID(),
+			Name:             entry.Test.Name(),
+			Status:           string(entry.Test.Status()),
+			Priority:         entry.Priority,
+			ConcurrencyGroup: entry.ConcurrencyGroup,
+			Preempt:          entry.Preempt,
+			WaitReason:       entry.WaitReason,
+		})
+	}
+
+	ah.sendOKResponse(w, r.URL.String(), queue)
+}
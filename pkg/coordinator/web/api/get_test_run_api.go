@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/erigontech/assertoor/pkg/coordinator/db"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/gorilla/mux"
 )
@@ -19,6 +20,9 @@ type GetTestRunResponse struct {
 	StartTime int64             `json:"start_time"`
 	StopTime  int64             `json:"stop_time"`
 	Tasks     []*GetTestRunTask `json:"tasks"`
+
+	ParentRunID  uint64         `json:"parent_run_id,omitempty"`
+	MatrixValues map[string]any `json:"matrix_values,omitempty"`
 }
 
 type GetTestRunTask struct {
@@ -80,6 +84,9 @@ func (ah *APIHandler) GetTestRun(w http.ResponseWriter, r *http.Request) {
 		Name:   testInstance.Name(),
 		Status: testInstance.Status(),
 		Tasks:  []*GetTestRunTask{},
+
+		ParentRunID:  testInstance.ParentRunID(),
+		MatrixValues: testInstance.MatrixValues(),
 	}
 
 	if !testInstance.StartTime().IsZero() {
@@ -165,7 +172,7 @@ func (ah *APIHandler) GetTestRun(w http.ResponseWriter, r *http.Request) {
 			}
 
 			if taskStatus.Error != nil {
-				taskData.ResultError = taskStatus.Error.Error()
+				taskData.ResultError = secrets.Redact(taskStatus.Error.Error())
 			}
 
 			if len(resultHeaderMap[uint64(taskState.Index())]) > 0 {
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/gorilla/mux"
+)
+
+type GetTestRunApprovalsResponse struct {
+	RunID     uint64                       `json:"run_id"`
+	Approvals []*types.TaskApprovalRequest `json:"approvals"`
+}
+
+// GetTestRunApprovals godoc
+// @Id getTestRunApprovals
+// @Summary Get pending approvals of a test run
+// @Tags TestRun
+// @Description Returns all approval requests of the test run that are waiting for a decision.
+// @Produce json
+// @Param runId path string true "ID of the test run"
+// @Success 200 {object} Response{data=GetTestRunApprovalsResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 404 {object} Response "Not Found"
+// @Router /api/v1/test_run/{runId}/approvals [get]
+func (ah *APIHandler) GetTestRunApprovals(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	testInstance, approver := ah.getTestRunApprover(w, r)
+	if approver == nil {
+		return
+	}
+
+	ah.sendOKResponse(w, r.URL.String(), &GetTestRunApprovalsResponse{
+		RunID:     testInstance.RunID(),
+		Approvals: approver.GetPendingApprovals(),
+	})
+}
+
+// getTestRunApprover resolves the test run from the request path and returns its approval handler.
+// An error response is sent and nil is returned if the test run does not accept approvals.
+func (ah *APIHandler) getTestRunApprover(w http.ResponseWriter, r *http.Request) (types.Test, types.TaskSchedulerApprover) {
+	vars := mux.Vars(r)
+
+	runID, err := strconv.ParseUint(vars["runId"], 10, 64)
+	if err != nil {
+		ah.sendErrorResponse(w, r.URL.String(), "invalid runId provided", http.StatusBadRequest)
+		return nil, nil
+	}
+
+	testInstance := ah.coordinator.GetTestByRunID(runID)
+	if testInstance == nil {
+		ah.sendErrorResponse(w, r.URL.String(), "test run not found", http.StatusNotFound)
+		return nil, nil
+	}
+
+	approver, ok := testInstance.GetTaskScheduler().(types.TaskSchedulerApprover)
+	if !ok || approver == nil {
+		ah.sendErrorResponse(w, r.URL.String(), "test run is not active", http.StatusBadRequest)
+		return nil, nil
+	}
+
+	return testInstance, approver
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/gorilla/mux"
+)
+
+type GetTestRunDebugResponse struct {
+	RunID       uint64                 `json:"run_id"`
+	Status      types.TestStatus       `json:"status"`
+	Paused      bool                   `json:"paused"`
+	PausedTasks []types.TaskIndex      `json:"paused_tasks"`
+	Breakpoints []types.TaskBreakpoint `json:"breakpoints"`
+}
+
+// GetTestRunDebug godoc
+// @Id getTestRunDebug
+// @Summary Get debugger state of a test run
+// @Tags TestRun
+// @Description Returns the pause state, the currently paused tasks and all breakpoints of the test run.
+// @Produce json
+// @Param runId path string true "ID of the test run"
+// @Success 200 {object} Response{data=GetTestRunDebugResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 404 {object} Response "Not Found"
+// @Router /api/v1/test_run/{runId}/debug [get]
+func (ah *APIHandler) GetTestRunDebug(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	testInstance, debugger := ah.getTestRunDebugger(w, r)
+	if debugger == nil {
+		return
+	}
+
+	ah.sendOKResponse(w, r.URL.String(), ah.getTestRunDebugResponse(testInstance, debugger))
+}
+
+func (ah *APIHandler) getTestRunDebugResponse(testInstance types.Test, debugger types.TaskSchedulerDebugger) *GetTestRunDebugResponse {
+	debugState := debugger.GetDebugState()
+
+	return &GetTestRunDebugResponse{
+		RunID:       testInstance.RunID(),
+		Status:      testInstance.Status(),
+		Paused:      debugState.Paused,
+		PausedTasks: debugState.PausedTasks,
+		Breakpoints: debugState.Breakpoints,
+	}
+}
+
+// getTestRunDebugger resolves the test run from the request path and returns its task debugger.
+// An error response is sent and nil is returned if the test run cannot be debugged.
+func (ah *APIHandler) getTestRunDebugger(w http.ResponseWriter, r *http.Request) (types.Test, types.TaskSchedulerDebugger) {
+	vars := mux.Vars(r)
+
+	runID, err := strconv.ParseUint(vars["runId"], 10, 64)
+	if err != nil {
+		ah.sendErrorResponse(w, r.URL.String(), "invalid runId provided", http.StatusBadRequest)
+		return nil, nil
+	}
+
+	testInstance := ah.coordinator.GetTestByRunID(runID)
+	if testInstance == nil {
+		ah.sendErrorResponse(w, r.URL.String(), "test run not found", http.StatusNotFound)
+		return nil, nil
+	}
+
+	debugger, ok := testInstance.GetTaskScheduler().(types.TaskSchedulerDebugger)
+	if !ok || debugger == nil {
+		ah.sendErrorResponse(w, r.URL.String(), "test run is not active", http.StatusBadRequest)
+		return nil, nil
+	}
+
+	return testInstance, debugger
+}
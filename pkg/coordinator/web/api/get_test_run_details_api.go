@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/gorilla/mux"
 	"gopkg.in/yaml.v3"
@@ -19,6 +20,9 @@ type GetTestRunDetailsResponse struct {
 	StartTime int64                     `json:"start_time"`
 	StopTime  int64                     `json:"stop_time"`
 	Tasks     []*GetTestRunDetailedTask `json:"tasks"`
+
+	ParentRunID  uint64         `json:"parent_run_id,omitempty"`
+	MatrixValues map[string]any `json:"matrix_values,omitempty"`
 }
 
 type GetTestRunDetailedTask struct {
@@ -82,6 +86,9 @@ func (ah *APIHandler) GetTestRunDetails(w http.ResponseWriter, r *http.Request)
 		Name:   testInstance.Name(),
 		Status: testInstance.Status(),
 		Tasks:  []*GetTestRunDetailedTask{},
+
+		ParentRunID:  testInstance.ParentRunID(),
+		MatrixValues: testInstance.MatrixValues(),
 	}
 
 	if !testInstance.StartTime().IsZero() {
@@ -154,7 +161,7 @@ func (ah *APIHandler) GetTestRunDetails(w http.ResponseWriter, r *http.Request)
 			}
 
 			if taskStatus.Error != nil {
-				taskData.ResultError = taskStatus.Error.Error()
+				taskData.ResultError = secrets.Redact(taskStatus.Error.Error())
 			}
 
 			logCount := taskStatus.Logger.GetLogEntryCount()
@@ -190,10 +197,10 @@ func (ah *APIHandler) GetTestRunDetails(w http.ResponseWriter, r *http.Request)
 			if err != nil {
 				taskData.ConfigYaml = fmt.Sprintf("failed marshalling config: %v", err)
 			} else {
-				taskData.ConfigYaml = string(taskConfig)
+				taskData.ConfigYaml = secrets.Redact(string(taskConfig))
 			}
 
-			taskResult, err := yaml.Marshal(taskState.GetTaskStatusVars().GetVarsMap(nil, false))
+			taskResult, err := yaml.Marshal(secrets.RedactData(taskState.GetTaskStatusVars().GetVarsMap(nil, false)))
 			if err != nil {
 				taskData.ResultYaml = fmt.Sprintf("failed marshalling result: %v", err)
 			} else {
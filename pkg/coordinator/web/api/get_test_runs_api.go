@@ -13,6 +13,9 @@ type GetTestRunsResponse struct {
 	Status    types.TestStatus `json:"status"`
 	StartTime int64            `json:"start_time"`
 	StopTime  int64            `json:"stop_time"`
+
+	ParentRunID  uint64         `json:"parent_run_id,omitempty"`
+	MatrixValues map[string]any `json:"matrix_values,omitempty"`
 }
 
 // GetTestRuns godoc
@@ -41,6 +44,9 @@ func (ah *APIHandler) GetTestRuns(w http.ResponseWriter, r *http.Request) {
 			TestID: testInstance.TestID(),
 			Name:   testInstance.Name(),
 			Status: testInstance.Status(),
+
+			ParentRunID:  testInstance.ParentRunID(),
+			MatrixValues: testInstance.MatrixValues(),
 		}
 
 		if !testInstance.StartTime().IsZero() {
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type PostClientActionResponse struct {
+	Name    string `json:"name"`
+	Drained bool   `json:"drained"`
+}
+
+// PostClientRemove godoc
+// @Id postClientRemove
+// @Summary Remove an endpoint from the client pool
+// @Tags Client
+// @Description Disconnects the consensus & execution client and removes them from the client pool.
+// @Description Running tasks that still hold a reference to the client see it as offline.
+// @Produce json
+// @Param clientName path string true "Name of the endpoint"
+// @Success 200 {object} Response{data=PostClientActionResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 404 {object} Response "Not Found"
+// @Router /api/v1/client/{clientName}/remove [post]
+func (ah *APIHandler) PostClientRemove(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	clientName := mux.Vars(r)["clientName"]
+
+	if !ah.coordinator.ClientPool().RemoveClient(clientName) {
+		ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("endpoint %v not found", clientName), http.StatusNotFound)
+		return
+	}
+
+	ah.coordinator.Logger().Infof("endpoint %v removed via api", clientName)
+
+	ah.sendOKResponse(w, r.URL.String(), &PostClientActionResponse{
+		Name: clientName,
+	})
+}
+
+// PostClientDrain godoc
+// @Id postClientDrain
+// @Summary Drain an endpoint
+// @Tags Client
+// @Description Stops using the endpoint for new requests. The endpoint stays connected, but is not selected as ready client or by client patterns.
+// @Produce json
+// @Param clientName path string true "Name of the endpoint"
+// @Success 200 {object} Response{data=PostClientActionResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 404 {object} Response "Not Found"
+// @Router /api/v1/client/{clientName}/drain [post]
+func (ah *APIHandler) PostClientDrain(w http.ResponseWriter, r *http.Request) {
+	ah.setClientDisabled(w, r, true)
+}
+
+// PostClientEnable godoc
+// @Id postClientEnable
+// @Summary Re-enable a drained endpoint
+// @Tags Client
+// @Description Re-enables an endpoint that has been drained before.
+// @Produce json
+// @Param clientName path string true "Name of the endpoint"
+// @Success 200 {object} Response{data=PostClientActionResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 404 {object} Response "Not Found"
+// @Router /api/v1/client/{clientName}/enable [post]
+func (ah *APIHandler) PostClientEnable(w http.ResponseWriter, r *http.Request) {
+	ah.setClientDisabled(w, r, false)
+}
+
+func (ah *APIHandler) setClientDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	clientName := mux.Vars(r)["clientName"]
+
+	if !ah.coordinator.ClientPool().SetClientDisabled(clientName, disabled) {
+		ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("endpoint %v not found", clientName), http.StatusNotFound)
+		return
+	}
+
+	if disabled {
+		ah.coordinator.Logger().Infof("endpoint %v drained via api", clientName)
+	} else {
+		ah.coordinator.Logger().Infof("endpoint %v re-enabled via api", clientName)
+	}
+
+	ah.sendOKResponse(w, r.URL.String(), &PostClientActionResponse{
+		Name:    clientName,
+		Drained: disabled,
+	})
+}
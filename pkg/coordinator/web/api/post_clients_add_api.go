@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+	"github.com/erigontech/assertoor/pkg/coordinator/clients/rpcstats"
+	"gopkg.in/yaml.v3"
+)
+
+type PostClientsAddRequest struct {
+	Name             string                 `yaml:"name" json:"name"`
+	ConsensusURL     string                 `yaml:"consensusUrl" json:"consensusUrl"`
+	ConsensusHeaders map[string]string      `yaml:"consensusHeaders" json:"consensusHeaders"`
+	ExecutionURL     string                 `yaml:"executionUrl" json:"executionUrl"`
+	ExecutionHeaders map[string]string      `yaml:"executionHeaders" json:"executionHeaders"`
+	Weight           uint64                 `yaml:"weight" json:"weight"`
+	RPCPolicy        *rpcstats.PolicyConfig `yaml:"rpcPolicy" json:"rpcPolicy"`
+	DisableSSZ       bool                   `yaml:"disableSsz" json:"disableSsz"`
+}
+
+type PostClientsAddResponse struct {
+	Index uint16 `json:"index"`
+	Name  string `json:"name"`
+}
+
+// PostClientsAdd godoc
+// @Id postClientsAdd
+// @Summary Add an endpoint to the client pool
+// @Tags Client
+// @Description Adds a consensus & execution client pair to the client pool. URLs and headers may contain secret references like `env:NAME`.
+// @Description Endpoints added via api are kept when the config is reloaded, but are not persisted across restarts.
+// @Accept json
+// @Produce json
+// @Param endpoint body PostClientsAddRequest true "Endpoint config (json or yaml)"
+// @Success 200 {object} Response{data=PostClientsAddResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 500 {object} Response "Server Error"
+// @Router /api/v1/clients/add [post]
+func (ah *APIHandler) PostClientsAdd(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	// parse request body
+	req := &PostClientsAddRequest{}
+
+	if r.Header.Get("Content-Type") == contentTypeYAML {
+		decoder := yaml.NewDecoder(r.Body)
+
+		err := decoder.Decode(req)
+		if err != nil {
+			ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("error decoding request body yaml: %v", err), http.StatusBadRequest)
+			return
+		}
+	} else {
+		decoder := json.NewDecoder(r.Body)
+
+		err := decoder.Decode(req)
+		if err != nil {
+			ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("error decoding request body json: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.Name == "" {
+		ah.sendErrorResponse(w, r.URL.String(), "name missing", http.StatusBadRequest)
+		return
+	}
+
+	if req.ConsensusURL == "" || req.ExecutionURL == "" {
+		ah.sendErrorResponse(w, r.URL.String(), "consensusUrl and executionUrl are required", http.StatusBadRequest)
+		return
+	}
+
+	clientPool := ah.coordinator.ClientPool()
+
+	clientConfig := &clients.ClientConfig{
+		Name:             req.Name,
+		ConsensusURL:     req.ConsensusURL,
+		ConsensusHeaders: req.ConsensusHeaders,
+		ExecutionURL:     req.ExecutionURL,
+		ExecutionHeaders: req.ExecutionHeaders,
+		Weight:           req.Weight,
+		RPCPolicy:        req.RPCPolicy,
+		DisableSSZ:       req.DisableSSZ,
+	}
+
+	if err := ah.resolveClientSecrets(r, clientConfig); err != nil {
+		ah.sendErrorResponse(w, r.URL.String(), err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := clientPool.AddClient(clientConfig); errors.Is(err, clients.ErrClientExists) {
+		ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("endpoint with name %v already exists", req.Name), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("failed adding endpoint: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	ah.coordinator.Logger().Infof("endpoint %v added via api", req.Name)
+
+	ah.sendOKResponse(w, r.URL.String(), &PostClientsAddResponse{
+		Index: clientPool.GetClient(req.Name).ConsensusClient.GetIndex(),
+		Name:  req.Name,
+	})
+}
+
+func (ah *APIHandler) resolveClientSecrets(r *http.Request, clientConfig *clients.ClientConfig) error {
+	var err error
+
+	resolver := ah.coordinator.SecretResolver()
+
+	if clientConfig.ConsensusURL, err = resolver.Resolve(r.Context(), clientConfig.ConsensusURL); err != nil {
+		return err
+	}
+
+	if clientConfig.ExecutionURL, err = resolver.Resolve(r.Context(), clientConfig.ExecutionURL); err != nil {
+		return err
+	}
+
+	for _, headers := range []map[string]string{clientConfig.ConsensusHeaders, clientConfig.ExecutionHeaders} {
+		for key, value := range headers {
+			if headers[key], err = resolver.Resolve(r.Context(), value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v3"
+)
+
+type PostTestRunTaskApprovalRequest struct {
+	Approved bool           `yaml:"approved" json:"approved"`
+	Approver string         `yaml:"approver" json:"approver"`
+	Comment  string         `yaml:"comment" json:"comment"`
+	Inputs   map[string]any `yaml:"inputs" json:"inputs"`
+}
+
+// PostTestRunTaskApproval godoc
+// @Id postTestRunTaskApproval
+// @Summary Approve or reject a waiting task
+// @Tags TestRun
+// @Description Approves or rejects a `wait_for_approval` task. The inputs are written into the task outputs.
+// @Produce json
+// @Param runId path string true "ID of the test run"
+// @Param taskIndex path string true "Index of the waiting task"
+// @Param approval body PostTestRunTaskApprovalRequest true "Approval decision (json or yaml)"
+// @Success 200 {object} Response "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 404 {object} Response "Not Found"
+// @Router /api/v1/test_run/{runId}/task/{taskIndex}/approval [post]
+func (ah *APIHandler) PostTestRunTaskApproval(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	// parse request body
+	req := &PostTestRunTaskApprovalRequest{}
+
+	if r.Header.Get("Content-Type") == contentTypeYAML {
+		decoder := yaml.NewDecoder(r.Body)
+
+		err := decoder.Decode(req)
+		if err != nil {
+			ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("error decoding request body yaml: %v", err), http.StatusBadRequest)
+			return
+		}
+	} else {
+		decoder := json.NewDecoder(r.Body)
+
+		err := decoder.Decode(req)
+		if err != nil {
+			ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("error decoding request body json: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	taskIdx, err := strconv.ParseUint(mux.Vars(r)["taskIndex"], 10, 64)
+	if err != nil {
+		ah.sendErrorResponse(w, r.URL.String(), "invalid taskIndex provided", http.StatusBadRequest)
+		return
+	}
+
+	_, approver := ah.getTestRunApprover(w, r)
+	if approver == nil {
+		return
+	}
+
+	err = approver.SubmitApproval(types.TaskIndex(taskIdx), &types.TaskApproval{
+		Approved: req.Approved,
+		Approver: req.Approver,
+		Comment:  req.Comment,
+		Inputs:   req.Inputs,
+	})
+	if err != nil {
+		ah.sendErrorResponse(w, r.URL.String(), err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ah.sendOKResponse(w, r.URL.String(), nil)
+}
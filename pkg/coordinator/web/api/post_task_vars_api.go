@@ -0,0 +1,81 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v3"
+)
+
+type PostTestRunTaskVarsRequest struct {
+	Vars map[string]any `yaml:"vars" json:"vars"`
+}
+
+// PostTestRunTaskVars godoc
+// @Id postTestRunTaskVars
+// @Summary Set variables in the scope of a task
+// @Tags TestRun
+// @Description Sets variables in the scope of a task that has not been started yet. Use this to edit variables of a task paused at a breakpoint before continuing.
+// @Produce json
+// @Param runId path string true "ID of the test run"
+// @Param taskIndex path string true "Index of the task"
+// @Param variables body PostTestRunTaskVarsRequest true "Variables to set (json or yaml)"
+// @Success 200 {object} Response "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 404 {object} Response "Not Found"
+// @Router /api/v1/test_run/{runId}/task/{taskIndex}/vars [post]
+func (ah *APIHandler) PostTestRunTaskVars(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	// parse request body
+	req := &PostTestRunTaskVarsRequest{}
+
+	if r.Header.Get("Content-Type") == contentTypeYAML {
+		decoder := yaml.NewDecoder(r.Body)
+
+		err := secrets.DecodeYaml(decoder, req)
+		if err != nil {
+			ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("error decoding request body yaml: %v", err), http.StatusBadRequest)
+			return
+		}
+	} else {
+		decoder := json.NewDecoder(r.Body)
+
+		err := decoder.Decode(req)
+		if err != nil {
+			ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("error decoding request body json: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	taskIdx, err := strconv.ParseUint(mux.Vars(r)["taskIndex"], 10, 64)
+	if err != nil {
+		ah.sendErrorResponse(w, r.URL.String(), "invalid taskIndex provided", http.StatusBadRequest)
+		return
+	}
+
+	_, debugger := ah.getTestRunDebugger(w, r)
+	if debugger == nil {
+		return
+	}
+
+	for name, value := range req.Vars {
+		if secrets.ContainsRedacted(value) {
+			ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("variable %v contains a redacted secret", name), http.StatusBadRequest)
+			return
+		}
+	}
+
+	err = debugger.SetTaskVars(types.TaskIndex(taskIdx), req.Vars)
+	if err != nil {
+		ah.sendErrorResponse(w, r.URL.String(), err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ah.sendOKResponse(w, r.URL.String(), nil)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"gopkg.in/yaml.v3"
 )
@@ -14,6 +15,7 @@ type PostTestRunsScheduleRequest struct {
 	Config         map[string]any `json:"config"`
 	AllowDuplicate bool           `json:"allow_duplicate"`
 	SkipQueue      bool           `json:"skip_queue"`
+	Priority       *int           `json:"priority,omitempty"`
 }
 
 type PostTestRunsScheduleResponse struct {
@@ -21,6 +23,9 @@ type PostTestRunsScheduleResponse struct {
 	RunID  uint64         `json:"run_id"`
 	Name   string         `json:"name"`
 	Config map[string]any `json:"config"`
+
+	// run ids of all runs created from the test matrix (if any)
+	MatrixRunIDs []uint64 `json:"matrix_run_ids,omitempty"`
 }
 
 // PostTestRunsSchedule godoc
@@ -28,6 +33,8 @@ type PostTestRunsScheduleResponse struct {
 // @Summary Schedule new test run by test ID
 // @Tags TestRun
 // @Description Returns the test & run id of the scheduled test execution.
+// @Description If the test defines a matrix, one run is scheduled per combination and the run id of the first run is returned.
+// @Description The optional priority overrides the priority from the test config for the queued runs.
 // @Produce json
 // @Param runOptions body PostTestRunsScheduleRequest true "Rest run options"
 // @Success 200 {object} Response{data=PostTestRunsScheduleResponse} "Success"
@@ -77,6 +84,13 @@ func (ah *APIHandler) PostTestRunsSchedule(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// drop redacted secrets that have been sent back unchanged, so the original values are used
+	for key, value := range req.Config {
+		if secrets.ContainsRedacted(value) {
+			delete(req.Config, key)
+		}
+	}
+
 	// create test run
 	testInstance, err := ah.coordinator.ScheduleTest(testDescriptor, req.Config, req.AllowDuplicate, req.SkipQueue)
 	if err != nil {
@@ -84,10 +98,33 @@ func (ah *APIHandler) PostTestRunsSchedule(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	ah.sendOKResponse(w, r.URL.String(), &PostTestRunsScheduleResponse{
+	response := &PostTestRunsScheduleResponse{
 		TestID: testDescriptor.ID(),
 		RunID:  testInstance.RunID(),
 		Name:   testInstance.Name(),
-		Config: testInstance.GetTestVariables().GetVarsMap(nil, false),
-	})
+		Config: secrets.RedactData(testInstance.GetTestVariables().GetVarsMap(nil, false)).(map[string]any),
+	}
+
+	runIDs := []uint64{testInstance.RunID()}
+
+	if parentRunID := testInstance.ParentRunID(); parentRunID != 0 {
+		runIDs = []uint64{}
+
+		for _, matrixRun := range ah.coordinator.GetTestRunGroup(parentRunID) {
+			response.MatrixRunIDs = append(response.MatrixRunIDs, matrixRun.RunID())
+			runIDs = append(runIDs, matrixRun.RunID())
+		}
+	}
+
+	if req.Priority != nil && !req.SkipQueue {
+		for _, runID := range runIDs {
+			// ignore errors, the run might already have been started
+			//nolint:errcheck // ignore
+			ah.coordinator.UpdateTestQueue(runID, &types.TestQueueUpdate{
+				Priority: req.Priority,
+			})
+		}
+	}
+
+	ah.sendOKResponse(w, r.URL.String(), response)
 }
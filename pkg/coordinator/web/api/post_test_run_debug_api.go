@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"gopkg.in/yaml.v3"
+)
+
+type PostTestRunBreakpointRequest struct {
+	TaskIndex uint64 `json:"task_index"`
+	TaskID    string `json:"task_id"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// PostTestRunPause godoc
+// @Id postTestRunPause
+// @Summary Pause test run
+// @Tags TestRun
+// @Description Pauses the test run before the next task gets executed. Running tasks are not interrupted. Returns the debugger state.
+// @Produce json
+// @Param runId path string true "ID of the test run to pause"
+// @Success 200 {object} Response{data=GetTestRunDebugResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 404 {object} Response "Not Found"
+// @Router /api/v1/test_run/{runId}/pause [post]
+func (ah *APIHandler) PostTestRunPause(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	testInstance, debugger := ah.getTestRunDebugger(w, r)
+	if debugger == nil {
+		return
+	}
+
+	debugger.PauseTasks()
+
+	ah.sendOKResponse(w, r.URL.String(), ah.getTestRunDebugResponse(testInstance, debugger))
+}
+
+// PostTestRunResume godoc
+// @Id postTestRunResume
+// @Summary Resume paused test run
+// @Tags TestRun
+// @Description Resumes a paused test run. The test continues until the next breakpoint. Returns the debugger state.
+// @Produce json
+// @Param runId path string true "ID of the test run to resume"
+// @Success 200 {object} Response{data=GetTestRunDebugResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 404 {object} Response "Not Found"
+// @Router /api/v1/test_run/{runId}/resume [post]
+func (ah *APIHandler) PostTestRunResume(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	testInstance, debugger := ah.getTestRunDebugger(w, r)
+	if debugger == nil {
+		return
+	}
+
+	debugger.ResumeTasks()
+
+	ah.sendOKResponse(w, r.URL.String(), ah.getTestRunDebugResponse(testInstance, debugger))
+}
+
+// PostTestRunStep godoc
+// @Id postTestRunStep
+// @Summary Step to the next task of a paused test run
+// @Tags TestRun
+// @Description Executes the paused task and pauses the test run again before the next task. Returns the debugger state.
+// @Produce json
+// @Param runId path string true "ID of the test run"
+// @Success 200 {object} Response{data=GetTestRunDebugResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 404 {object} Response "Not Found"
+// @Router /api/v1/test_run/{runId}/step [post]
+func (ah *APIHandler) PostTestRunStep(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	testInstance, debugger := ah.getTestRunDebugger(w, r)
+	if debugger == nil {
+		return
+	}
+
+	debugger.StepTask()
+
+	ah.sendOKResponse(w, r.URL.String(), ah.getTestRunDebugResponse(testInstance, debugger))
+}
+
+// PostTestRunBreakpoint godoc
+// @Id postTestRunBreakpoint
+// @Summary Set or remove a breakpoint
+// @Tags TestRun
+// @Description Sets or removes a breakpoint by task index or task ID. The test run pauses before a matching task gets executed. Returns the debugger state.
+// @Produce json
+// @Param runId path string true "ID of the test run"
+// @Param breakpoint body PostTestRunBreakpointRequest true "Breakpoint options"
+// @Success 200 {object} Response{data=GetTestRunDebugResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 404 {object} Response "Not Found"
+// @Router /api/v1/test_run/{runId}/breakpoint [post]
+func (ah *APIHandler) PostTestRunBreakpoint(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	// parse request body
+	req := &PostTestRunBreakpointRequest{}
+
+	if r.Header.Get("Content-Type") == contentTypeYAML {
+		decoder := yaml.NewDecoder(r.Body)
+
+		err := decoder.Decode(req)
+		if err != nil {
+			ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("error decoding request body yaml: %v", err), http.StatusBadRequest)
+			return
+		}
+	} else {
+		decoder := json.NewDecoder(r.Body)
+
+		err := decoder.Decode(req)
+		if err != nil {
+			ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("error decoding request body json: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if (req.TaskIndex == 0) == (req.TaskID == "") {
+		ah.sendErrorResponse(w, r.URL.String(), "either task_index or task_id must be provided", http.StatusBadRequest)
+		return
+	}
+
+	testInstance, debugger := ah.getTestRunDebugger(w, r)
+	if debugger == nil {
+		return
+	}
+
+	debugger.SetBreakpoint(types.TaskBreakpoint{
+		Index: types.TaskIndex(req.TaskIndex),
+		ID:    req.TaskID,
+	}, req.Enabled)
+
+	ah.sendOKResponse(w, r.URL.String(), ah.getTestRunDebugResponse(testInstance, debugger))
+}
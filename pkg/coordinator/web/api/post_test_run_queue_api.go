@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/types"
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v3"
+)
+
+type PostTestRunQueueRequest struct {
+	Priority *int `json:"priority,omitempty"`
+	Position *int `json:"position,omitempty"`
+	Preempt  bool `json:"preempt"`
+}
+
+type PostTestRunQueueResponse struct {
+	RunID    uint64 `json:"run_id"`
+	Position int    `json:"position"`
+	Priority int    `json:"priority"`
+	Preempt  bool   `json:"preempt"`
+}
+
+// PostTestRunQueue godoc
+// @Id postTestRunQueue
+// @Summary Reorder or preempt a queued test run
+// @Tags TestRun
+// @Description Changes the priority and/or position of a queued test run.
+// @Description If only the priority is given, the run is moved behind all queued runs with the same or a higher priority.
+// @Description Preempted runs are moved to the front of the queue and started regardless of the concurrency limits, but still wait for their locks.
+// @Produce json
+// @Param runId path string true "ID of the queued test run"
+// @Param queueOptions body PostTestRunQueueRequest true "Queue options"
+// @Success 200 {object} Response{data=PostTestRunQueueResponse} "Success"
+// @Failure 400 {object} Response "Failure"
+// @Failure 500 {object} Response "Server Error"
+// @Router /api/v1/test_run/{runId}/queue [post]
+func (ah *APIHandler) PostTestRunQueue(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+
+	// parse request body
+	req := &PostTestRunQueueRequest{}
+
+	if r.Header.Get("Content-Type") == contentTypeYAML {
+		decoder := yaml.NewDecoder(r.Body)
+
+		err := decoder.Decode(req)
+		if err != nil {
+			ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("error decoding request body yaml: %v", err), http.StatusBadRequest)
+			return
+		}
+	} else {
+		decoder := json.NewDecoder(r.Body)
+
+		err := decoder.Decode(req)
+		if err != nil {
+			ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("error decoding request body json: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if req.Position != nil && *req.Position < 0 {
+		ah.sendErrorResponse(w, r.URL.String(), "invalid position provided", http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	runID, err := strconv.ParseUint(vars["runId"], 10, 64)
+	if err != nil {
+		ah.sendErrorResponse(w, r.URL.String(), "invalid runId provided", http.StatusBadRequest)
+		return
+	}
+
+	err = ah.coordinator.UpdateTestQueue(runID, &types.TestQueueUpdate{
+		Priority: req.Priority,
+		Position: req.Position,
+		Preempt:  req.Preempt,
+	})
+	if err != nil {
+		ah.sendErrorResponse(w, r.URL.String(), err.Error(), http.StatusNotFound)
+		return
+	}
+
+	response := &PostTestRunQueueResponse{
+		RunID:    runID,
+		Position: -1,
+	}
+
+	for idx, entry := range ah.coordinator.GetTestQueueEntries() {
+		if entry.Test.RunID() == runID {
+			response.Position = idx
+			response.Priority = entry.Priority
+			response.Preempt = entry.Preempt
+
+			break
+		}
+	}
+
+	ah.sendOKResponse(w, r.URL.String(), response)
+}
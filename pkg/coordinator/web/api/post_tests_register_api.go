@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"gopkg.in/yaml.v3"
 )
@@ -48,7 +49,7 @@ func (ah *APIHandler) PostTestsRegister(w http.ResponseWriter, r *http.Request)
 	if r.Header.Get("Content-Type") == contentTypeYAML {
 		decoder := yaml.NewDecoder(r.Body)
 
-		err := decoder.Decode(req)
+		err := secrets.DecodeYaml(decoder, req)
 		if err != nil {
 			ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("error decoding request body yaml: %v", err), http.StatusBadRequest)
 			return
@@ -107,6 +108,6 @@ func (ah *APIHandler) PostTestsRegister(w http.ResponseWriter, r *http.Request)
 	ah.sendOKResponse(w, r.URL.String(), &PostTestsRegisterResponse{
 		TestID: testDescriptor.ID(),
 		Name:   testDescriptor.Config().Name,
-		Config: testDescriptor.Config().Config,
+		Config: secrets.RedactData(testDescriptor.Config().Config).(map[string]any),
 	})
 }
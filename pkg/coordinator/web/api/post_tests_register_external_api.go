@@ -7,17 +7,22 @@ import (
 	"time"
 
 	"github.com/erigontech/assertoor/pkg/coordinator/helper"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"gopkg.in/yaml.v3"
 )
 
 type PostTestsRegisterExternalRequest struct {
-	File       string                 `yaml:"file" json:"file"`
-	Name       string                 `yaml:"name" json:"name,omitempty"`
-	Timeout    uint64                 `yaml:"timeout" json:"timeout,omitempty"`
-	Config     map[string]interface{} `yaml:"config" json:"config,omitempty"`
-	ConfigVars map[string]string      `yaml:"configVars" json:"configVars,omitempty"`
-	Schedule   *types.TestSchedule    `yaml:"schedule" json:"schedule,omitempty"`
+	File             string                 `yaml:"file" json:"file"`
+	Name             string                 `yaml:"name" json:"name,omitempty"`
+	Timeout          uint64                 `yaml:"timeout" json:"timeout,omitempty"`
+	Config           map[string]interface{} `yaml:"config" json:"config,omitempty"`
+	ConfigVars       map[string]string      `yaml:"configVars" json:"configVars,omitempty"`
+	Schedule         *types.TestSchedule    `yaml:"schedule" json:"schedule,omitempty"`
+	Matrix           types.TestMatrix       `yaml:"matrix" json:"matrix,omitempty"`
+	Locks            []string               `yaml:"locks" json:"locks,omitempty"`
+	Priority         *int                   `yaml:"priority" json:"priority,omitempty"`
+	ConcurrencyGroup string                 `yaml:"concurrencyGroup" json:"concurrencyGroup,omitempty"`
 }
 
 type PostTestsRegisterExternalResponse struct {
@@ -47,7 +52,7 @@ func (ah *APIHandler) PostTestsRegisterExternal(w http.ResponseWriter, r *http.R
 	if r.Header.Get("Content-Type") == contentTypeYAML {
 		decoder := yaml.NewDecoder(r.Body)
 
-		err := decoder.Decode(req)
+		err := secrets.DecodeYaml(decoder, req)
 		if err != nil {
 			ah.sendErrorResponse(w, r.URL.String(), fmt.Sprintf("error decoding request body yaml: %v", err), http.StatusBadRequest)
 			return
@@ -63,12 +68,16 @@ func (ah *APIHandler) PostTestsRegisterExternal(w http.ResponseWriter, r *http.R
 	}
 
 	extTestCfg := &types.ExternalTestConfig{
-		File:       req.File,
-		Name:       req.Name,
-		Timeout:    &helper.Duration{},
-		Config:     req.Config,
-		ConfigVars: req.ConfigVars,
-		Schedule:   req.Schedule,
+		File:             req.File,
+		Name:             req.Name,
+		Timeout:          &helper.Duration{},
+		Config:           req.Config,
+		ConfigVars:       req.ConfigVars,
+		Schedule:         req.Schedule,
+		Matrix:           req.Matrix,
+		Locks:            req.Locks,
+		Priority:         req.Priority,
+		ConcurrencyGroup: req.ConcurrencyGroup,
 	}
 	if req.Timeout > 0 {
 		extTestCfg.Timeout = &helper.Duration{Duration: time.Duration(req.Timeout) * time.Second} //nolint:gosec // no overflow possible
@@ -84,6 +93,6 @@ func (ah *APIHandler) PostTestsRegisterExternal(w http.ResponseWriter, r *http.R
 	ah.sendOKResponse(w, r.URL.String(), &PostTestsRegisterResponse{
 		TestID: testDescriptor.ID(),
 		Name:   testDescriptor.Config().Name,
-		Config: testDescriptor.Config().Config,
+		Config: secrets.RedactData(testDescriptor.Config().Config).(map[string]any),
 	})
 }
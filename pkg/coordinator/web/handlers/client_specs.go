@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/erigontech/assertoor/pkg/coordinator/clients"
+)
+
+type ClientSpecsPage struct {
+	Clients       []string                  `json:"clients"`
+	Fields        []*clients.ChainSpecField `json:"fields"`
+	Errors        []*clients.ChainSpecError `json:"errors"`
+	FieldCount    int                       `json:"field_count"`
+	MismatchCount int                       `json:"mismatch_count"`
+	ShowAll       bool                      `json:"show_all"`
+}
+
+// ClientSpecs will return the "client specs" page using a go template
+func (fh *FrontendHandler) ClientSpecs(w http.ResponseWriter, r *http.Request) {
+	templateFiles := LayoutTemplateFiles
+	templateFiles = append(templateFiles,
+		"clients/specs.html",
+	)
+
+	pageTemplate := fh.templates.GetTemplate(templateFiles...)
+	data := fh.initPageData(r, "clients", "/clients/specs", "Chain Specs", templateFiles)
+
+	var pageError error
+	data.Data, pageError = fh.getClientSpecsPageData(r.Context(), r.URL.Query().Has("all"))
+
+	if pageError != nil {
+		fh.HandlePageError(w, r, pageError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+
+	if fh.handleTemplateError(w, r, "client_specs.go", "ClientSpecs", pageTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+//nolint:unparam // ignore
+func (fh *FrontendHandler) getClientSpecsPageData(ctx context.Context, showAll bool) (*ClientSpecsPage, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	report := clients.CompareChainSpecs(reqCtx, fh.coordinator.ClientPool().GetAllClients(), &clients.ChainSpecCompareOptions{
+		IgnoreMissing: true,
+	})
+
+	pageData := &ClientSpecsPage{
+		Clients:       report.Clients,
+		Fields:        []*clients.ChainSpecField{},
+		Errors:        report.Errors,
+		FieldCount:    report.FieldCount,
+		MismatchCount: report.MismatchCount,
+		ShowAll:       showAll,
+	}
+
+	for _, field := range report.Fields {
+		if showAll || len(field.Deviating) > 0 {
+			pageData.Fields = append(pageData.Fields, field)
+		}
+	}
+
+	return pageData, nil
+}
@@ -12,11 +12,14 @@ import (
 type ClientsPage struct {
 	Clients     []*ClientsPageClient `json:"clients"`
 	ClientCount uint64               `json:"client_count"`
+	CanManage   bool                 `json:"can_manage"`
 }
 
 type ClientsPageClient struct {
 	Index         int       `json:"index"`
 	Name          string    `json:"name"`
+	IsDrained     bool      `json:"drained"`
+	Weight        uint64    `json:"weight"`
 	CLVersion     string    `json:"cl_version"`
 	CLType        int64     `json:"cl_type"`
 	CLHeadSlot    uint64    `json:"cl_head_slot"`
@@ -25,6 +28,10 @@ type ClientsPageClient struct {
 	CLLastRefresh time.Time `json:"cl_refresh"`
 	CLLastError   string    `json:"cl_error"`
 	CLIsReady     bool      `json:"cl_ready"`
+	CLLatency     int64     `json:"cl_latency"`
+	CLErrorRate   float64   `json:"cl_error_rate"`
+	CLIsEjected   bool      `json:"cl_ejected"`
+	CLCircuit     string    `json:"cl_circuit"`
 	ELVersion     string    `json:"el_version"`
 	ELType        int64     `json:"el_type"`
 	ELHeadNumber  uint64    `json:"el_head_number"`
@@ -33,6 +40,10 @@ type ClientsPageClient struct {
 	ELLastRefresh time.Time `json:"el_refresh"`
 	ELLastError   string    `json:"el_error"`
 	ELIsReady     bool      `json:"el_ready"`
+	ELLatency     int64     `json:"el_latency"`
+	ELErrorRate   float64   `json:"el_error_rate"`
+	ELIsEjected   bool      `json:"el_ejected"`
+	ELCircuit     string    `json:"el_circuit"`
 }
 
 // Clients will return the "clients" page using a go template
@@ -63,7 +74,8 @@ func (fh *FrontendHandler) Clients(w http.ResponseWriter, r *http.Request) {
 //nolint:unparam // ignore
 func (fh *FrontendHandler) getClientsPageData() (*ClientsPage, error) {
 	pageData := &ClientsPage{
-		Clients: []*ClientsPageClient{},
+		Clients:   []*ClientsPageClient{},
+		CanManage: fh.isAPIEnabled && !fh.securityTrimmed,
 	}
 
 	// get clients
@@ -81,21 +93,33 @@ func (fh *FrontendHandler) getClientsPageClientData(client *clients.PoolClient)
 	clientPool := fh.coordinator.ClientPool()
 	headSlot, headRoot := client.ConsensusClient.GetLastHead()
 	blockNum, blockHash := client.ExecutionClient.GetLastHead()
+	clStats := client.ConsensusClient.GetRequestStats()
+	elStats := client.ExecutionClient.GetRequestStats()
 	clientData := &ClientsPageClient{
 		Index:         int(client.ConsensusClient.GetIndex()),
 		Name:          client.ConsensusClient.GetName(),
+		IsDrained:     client.IsDisabled(),
+		Weight:        client.ConsensusClient.GetWeight(),
 		CLVersion:     client.ConsensusClient.GetVersion(),
 		CLType:        int64(client.ConsensusClient.GetClientType()),
 		CLHeadSlot:    uint64(headSlot),
 		CLHeadRoot:    headRoot[:],
 		CLLastRefresh: client.ConsensusClient.GetLastEventTime(),
 		CLIsReady:     clientPool.GetConsensusPool().GetCanonicalFork(2).IsClientReady(client.ConsensusClient),
+		CLLatency:     clStats.AvgLatency.Milliseconds(),
+		CLErrorRate:   clStats.ErrorRate * 100,
+		CLIsEjected:   client.ConsensusClient.IsEjected(),
+		CLCircuit:     clStats.CircuitState.String(),
 		ELVersion:     client.ExecutionClient.GetVersion(),
 		ELType:        int64(client.ExecutionClient.GetClientType()),
 		ELHeadNumber:  blockNum,
 		ELHeadHash:    blockHash[:],
 		ELLastRefresh: client.ExecutionClient.GetLastEventTime(),
 		ELIsReady:     clientPool.GetExecutionPool().GetCanonicalFork(2).IsClientReady(client.ExecutionClient),
+		ELLatency:     elStats.AvgLatency.Milliseconds(),
+		ELErrorRate:   elStats.ErrorRate * 100,
+		ELIsEjected:   client.ExecutionClient.IsEjected(),
+		ELCircuit:     elStats.CircuitState.String(),
 	}
 
 	if lastError := client.ConsensusClient.GetLastError(); lastError != nil {
@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"math"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
@@ -18,6 +21,7 @@ type IndexPageArgs struct {
 
 type IndexPage struct {
 	CanCancel      bool           `json:"can_cancel"`
+	Locks          []*LockData    `json:"locks"`
 	Tests          []*TestRunData `json:"tests"`
 	TotalTests     uint64         `json:"total_tests"`
 	FirstTestIndex uint64         `json:"first_test_index"`
@@ -46,6 +50,17 @@ type TestRunData struct {
 	HasRunTime  bool          `json:"has_runtime"`
 	Status      string        `json:"status"`
 	TaskCount   uint64        `json:"task_count"`
+	ParentRunID uint64        `json:"parent_run_id"`
+	Matrix      string        `json:"matrix"`
+}
+
+type LockData struct {
+	Name        string    `json:"name"`
+	IsHeld      bool      `json:"held"`
+	RunID       uint64    `json:"run_id"`
+	TaskIndex   uint64    `json:"task_index"`
+	AcquireTime time.Time `json:"acquire_time"`
+	WaitingRuns []uint64  `json:"waiting_runs"`
 }
 
 func (fh *FrontendHandler) parseIndexPageArgs(r *http.Request) *IndexPageArgs {
@@ -138,6 +153,17 @@ func (fh *FrontendHandler) getIndexPageData(pageArgs *IndexPageArgs) (*IndexPage
 		CanCancel: fh.isAPIEnabled && !fh.securityTrimmed,
 	}
 
+	for _, lockInfo := range fh.coordinator.LockManager().GetLocks() {
+		pageData.Locks = append(pageData.Locks, &LockData{
+			Name:        lockInfo.Name,
+			IsHeld:      lockInfo.Holder.RunID != 0,
+			RunID:       lockInfo.Holder.RunID,
+			TaskIndex:   lockInfo.Holder.TaskIndex,
+			AcquireTime: lockInfo.AcquireTime,
+			WaitingRuns: lockInfo.Waiting,
+		})
+	}
+
 	pageOffset := (pageArgs.Page - 1) * pageArgs.PageSize
 	testInstances, totalTests := fh.coordinator.GetTestHistory("", 0, pageOffset, pageArgs.PageSize)
 
@@ -176,6 +202,9 @@ func (fh *FrontendHandler) getTestRunData(idx uint64, test types.Test) *TestRunD
 		Timeout:    test.Timeout(),
 		HasTimeout: test.Timeout() > 0,
 		Status:     string(test.Status()),
+
+		ParentRunID: test.ParentRunID(),
+		Matrix:      formatMatrixValues(test.MatrixValues()),
 	}
 
 	switch test.Status() {
@@ -206,3 +235,23 @@ func (fh *FrontendHandler) getTestRunData(idx uint64, test types.Test) *TestRunD
 
 	return testData
 }
+
+func formatMatrixValues(matrixValues map[string]interface{}) string {
+	if len(matrixValues) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(matrixValues))
+	for key := range matrixValues {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for idx, key := range keys {
+		parts[idx] = fmt.Sprintf("%v=%v", key, matrixValues[key])
+	}
+
+	return strings.Join(parts, ", ")
+}
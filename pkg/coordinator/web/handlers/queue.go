@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+type QueuePage struct {
+	CanManage bool              `json:"can_manage"`
+	Queue     []*QueueEntryData `json:"queue"`
+}
+
+type QueueEntryData struct {
+	Position         int    `json:"position"`
+	RunID            uint64 `json:"run_id"`
+	TestID           string `json:"test_id"`
+	Name             string `json:"name"`
+	Status           string `json:"status"`
+	Priority         int    `json:"priority"`
+	ConcurrencyGroup string `json:"concurrency_group"`
+	Preempt          bool   `json:"preempt"`
+	WaitReason       string `json:"wait_reason"`
+	IsFirst          bool   `json:"is_first"`
+	IsLast           bool   `json:"is_last"`
+}
+
+// Queue will return the "queue" page using a go template
+func (fh *FrontendHandler) Queue(w http.ResponseWriter, r *http.Request) {
+	urlArgs := r.URL.Query()
+	if urlArgs.Has("json") {
+		fh.QueueData(w, r)
+		return
+	}
+
+	templateFiles := LayoutTemplateFiles
+	templateFiles = append(templateFiles,
+		"queue/queue.html",
+		"sidebar/sidebar.html",
+	)
+	pageTemplate := fh.templates.GetTemplate(templateFiles...)
+	data := fh.initPageData(r, "queue", "/queue", "Test Queue", templateFiles)
+
+	var pageError error
+	data.Data, pageError = fh.getQueuePageData()
+
+	if pageError != nil {
+		fh.HandlePageError(w, r, pageError)
+		return
+	}
+
+	data.ShowSidebar = true
+	data.SidebarData = fh.getSidebarData("*queue")
+
+	w.Header().Set("Content-Type", "text/html")
+
+	if fh.handleTemplateError(w, r, "queue.go", "Queue", pageTemplate.ExecuteTemplate(w, "layout", data)) != nil {
+		return // an error has occurred and was processed
+	}
+}
+
+func (fh *FrontendHandler) QueueData(w http.ResponseWriter, r *http.Request) {
+	var pageData *QueuePage
+
+	var pageError error
+
+	pageData, pageError = fh.getQueuePageData()
+
+	if pageError != nil {
+		fh.HandlePageError(w, r, pageError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(pageData)
+	if err != nil {
+		logrus.WithError(err).Error("error encoding queue data")
+
+		//nolint:gocritic // ignore
+		http.Error(w, "Internal server error", http.StatusServiceUnavailable)
+	}
+}
+
+//nolint:unparam // ignore
+func (fh *FrontendHandler) getQueuePageData() (*QueuePage, error) {
+	pageData := &QueuePage{
+		CanManage: fh.isAPIEnabled && !fh.securityTrimmed,
+		Queue:     []*QueueEntryData{},
+	}
+
+	queueEntries := fh.coordinator.GetTestQueueEntries()
+
+	for idx, entry := range queueEntries {
+		waitReason := entry.WaitReason
+		if waitReason == "" {
+			waitReason = "starting"
+		}
+
+		pageData.Queue = append(pageData.Queue, &QueueEntryData{
+			Position:         idx,
+			RunID:            entry.Test.RunID(),
+			TestID:           entry.Test.TestID(),
+			Name:             entry.Test.Name(),
+			Status:           string(entry.Test.Status()),
+			Priority:         entry.Priority,
+			ConcurrencyGroup: entry.ConcurrencyGroup,
+			Preempt:          entry.Preempt,
+			WaitReason:       waitReason,
+			IsFirst:          idx == 0,
+			IsLast:           idx == len(queueEntries)-1,
+		})
+	}
+
+	return pageData, nil
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/erigontech/assertoor/pkg/coordinator/db"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/sirupsen/logrus"
 )
@@ -201,7 +202,7 @@ func (fh *FrontendHandler) getTestRegistryData(idx uint64, test types.TestDescri
 	}
 
 	if !fh.securityTrimmed && test.Vars() != nil {
-		configJSON, err := json.Marshal(test.Vars().GetVarsMap(nil, true))
+		configJSON, err := json.Marshal(secrets.RedactData(test.Vars().GetVarsMap(nil, true)))
 		if err == nil {
 			testData.Config = string(configJSON)
 		}
@@ -13,6 +13,8 @@ type SidebarData struct {
 	TestDescriptors  []*SidebarTest `json:"tests"`
 	AllTestsActive   bool           `json:"all_tests_active"`
 	RegistryActive   bool           `json:"registry_active"`
+	QueueActive      bool           `json:"queue_active"`
+	QueueLength      uint64         `json:"queue_length"`
 	CanRegisterTests bool           `json:"can_register_tests"`
 	Version          string         `json:"version"`
 }
@@ -28,6 +30,8 @@ func (fh *FrontendHandler) getSidebarData(activeTestID string) *SidebarData {
 		TestDescriptors:  []*SidebarTest{},
 		AllTestsActive:   activeTestID == "*",
 		RegistryActive:   activeTestID == "*registry",
+		QueueActive:      activeTestID == "*queue",
+		QueueLength:      uint64(len(fh.coordinator.GetTestQueue())),
 		CanRegisterTests: !fh.securityTrimmed && fh.isAPIEnabled,
 		Version:          buildinfo.GetVersion(),
 	}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
@@ -152,7 +153,7 @@ func (fh *FrontendHandler) getTestPageData(testID string, pageArgs *TestPageArgs
 	}
 
 	if fh.isAPIEnabled && !fh.securityTrimmed {
-		testCfgJSON, err := json.Marshal(testDescriptor.Vars().GetVarsMap(nil, false))
+		testCfgJSON, err := json.Marshal(secrets.RedactData(testDescriptor.Vars().GetVarsMap(nil, false)))
 		if err != nil {
 			return nil, fmt.Errorf("failed marshalling test vars: %v", err)
 		}
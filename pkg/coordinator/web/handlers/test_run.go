@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/erigontech/assertoor/pkg/coordinator/db"
+	"github.com/erigontech/assertoor/pkg/coordinator/secrets"
 	"github.com/erigontech/assertoor/pkg/coordinator/types"
 	"github.com/erigontech/assertoor/pkg/coordinator/web/api"
 	"github.com/gorilla/mux"
@@ -25,7 +26,12 @@ type TestRunPage struct {
 	Timeout      int64          `json:"timeout"` // milliseconds
 	Status       string         `json:"status"`
 	IsSecTrimmed bool           `json:"is_sec_trimmed"`
+	CanDebug     bool           `json:"can_debug"`
+	CanApprove   bool           `json:"can_approve"`
 	Tasks        []*TestRunTask `json:"tasks"`
+	ParentRunID  uint64         `json:"parent_run_id"`
+	Matrix       string         `json:"matrix"`
+	MatrixRuns   []*TestRunData `json:"matrix_runs"`
 }
 
 type TestRunTask struct {
@@ -143,6 +149,16 @@ func (fh *FrontendHandler) getTestRunPageData(runID uint64) (*TestRunPage, error
 		Timeout:      test.Timeout().Milliseconds(),
 		Status:       string(test.Status()),
 		IsSecTrimmed: fh.securityTrimmed,
+		CanDebug:     fh.isAPIEnabled && !fh.securityTrimmed,
+		CanApprove:   fh.isAPIEnabled && !fh.securityTrimmed,
+		ParentRunID:  test.ParentRunID(),
+		Matrix:       formatMatrixValues(test.MatrixValues()),
+	}
+
+	if pageData.ParentRunID != 0 {
+		for idx, matrixRun := range fh.coordinator.GetTestRunGroup(pageData.ParentRunID) {
+			pageData.MatrixRuns = append(pageData.MatrixRuns, fh.getTestRunData(uint64(idx), matrixRun)) //nolint:gosec // no overflow possible
+		}
 	}
 
 	switch test.Status() {
@@ -225,7 +241,7 @@ func (fh *FrontendHandler) getTestRunPageData(runID uint64) (*TestRunPage, error
 			}
 
 			if taskStatus.Error != nil {
-				taskData.ResultError = taskStatus.Error.Error()
+				taskData.ResultError = secrets.Redact(taskStatus.Error.Error())
 			}
 
 			if !fh.securityTrimmed && len(resultHeaderMap[taskData.Index]) > 0 {
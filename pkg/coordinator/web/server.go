@@ -85,6 +85,9 @@ func (ws *Server) ConfigureRoutes(frontendConfig *types.FrontendConfig, apiConfi
 		ws.router.HandleFunc("/api/v1/test_runs", apiHandler.GetTestRuns).Methods("GET")
 		ws.router.HandleFunc("/api/v1/test_run/{runId}", apiHandler.GetTestRun).Methods("GET")
 		ws.router.HandleFunc("/api/v1/test_run/{runId}/status", apiHandler.GetTestRunStatus).Methods("GET")
+		ws.router.HandleFunc("/api/v1/test_queue", apiHandler.GetTestQueue).Methods("GET")
+		ws.router.HandleFunc("/api/v1/locks", apiHandler.GetLocks).Methods("GET")
+		ws.router.HandleFunc("/api/v1/clients", apiHandler.GetClients).Methods("GET")
 
 		// private apis
 		if !securityTrimmed {
@@ -95,9 +98,23 @@ func (ws *Server) ConfigureRoutes(frontendConfig *types.FrontendConfig, apiConfi
 			ws.router.HandleFunc("/api/v1/test_runs/schedule", apiHandler.PostTestRunsSchedule).Methods("POST")
 			ws.router.HandleFunc("/api/v1/test_runs/delete", apiHandler.PostTestRunsDelete).Methods("POST")
 			ws.router.HandleFunc("/api/v1/test_run/{runId}/cancel", apiHandler.PostTestRunCancel).Methods("POST")
+			ws.router.HandleFunc("/api/v1/test_run/{runId}/queue", apiHandler.PostTestRunQueue).Methods("POST")
 			ws.router.HandleFunc("/api/v1/test_run/{runId}/details", apiHandler.GetTestRunDetails).Methods("GET")
 			ws.router.HandleFunc("/api/v1/test_run/{runId}/task/{taskIndex}/details", apiHandler.GetTestRunTaskDetails).Methods("GET")
 			ws.router.HandleFunc("/api/v1/test_run/{runId}/task/{taskId}/result/{resultType}/{fileId:.*}", apiHandler.GetTaskResult).Methods("GET")
+			ws.router.HandleFunc("/api/v1/test_run/{runId}/debug", apiHandler.GetTestRunDebug).Methods("GET")
+			ws.router.HandleFunc("/api/v1/test_run/{runId}/pause", apiHandler.PostTestRunPause).Methods("POST")
+			ws.router.HandleFunc("/api/v1/test_run/{runId}/resume", apiHandler.PostTestRunResume).Methods("POST")
+			ws.router.HandleFunc("/api/v1/test_run/{runId}/step", apiHandler.PostTestRunStep).Methods("POST")
+			ws.router.HandleFunc("/api/v1/test_run/{runId}/breakpoint", apiHandler.PostTestRunBreakpoint).Methods("POST")
+			ws.router.HandleFunc("/api/v1/test_run/{runId}/task/{taskIndex}/vars", apiHandler.GetTestRunTaskVars).Methods("GET")
+			ws.router.HandleFunc("/api/v1/test_run/{runId}/task/{taskIndex}/vars", apiHandler.PostTestRunTaskVars).Methods("POST")
+			ws.router.HandleFunc("/api/v1/test_run/{runId}/approvals", apiHandler.GetTestRunApprovals).Methods("GET")
+			ws.router.HandleFunc("/api/v1/test_run/{runId}/task/{taskIndex}/approval", apiHandler.PostTestRunTaskApproval).Methods("POST")
+			ws.router.HandleFunc("/api/v1/clients/add", apiHandler.PostClientsAdd).Methods("POST")
+			ws.router.HandleFunc("/api/v1/client/{clientName}/remove", apiHandler.PostClientRemove).Methods("POST")
+			ws.router.HandleFunc("/api/v1/client/{clientName}/drain", apiHandler.PostClientDrain).Methods("POST")
+			ws.router.HandleFunc("/api/v1/client/{clientName}/enable", apiHandler.PostClientEnable).Methods("POST")
 		}
 	}
 
@@ -120,9 +137,11 @@ func (ws *Server) ConfigureRoutes(frontendConfig *types.FrontendConfig, apiConfi
 
 			ws.router.HandleFunc("/", frontendHandler.Index).Methods("GET")
 			ws.router.HandleFunc("/registry", frontendHandler.Registry).Methods("GET")
+			ws.router.HandleFunc("/queue", frontendHandler.Queue).Methods("GET")
 			ws.router.HandleFunc("/test/{testId}", frontendHandler.TestPage).Methods("GET")
 			ws.router.HandleFunc("/run/{runId}", frontendHandler.TestRun).Methods("GET")
 			ws.router.HandleFunc("/clients", frontendHandler.Clients).Methods("GET")
+			ws.router.HandleFunc("/clients/specs", frontendHandler.ClientSpecs).Methods("GET")
 			ws.router.HandleFunc("/logs/{since}", frontendHandler.LogsData).Methods("GET")
 
 			if isAPIEnabled {